@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/activities"
+	"github.com/devpies/devpie-client-core/projects/domain/projects"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+type Activities struct {
+	repo  *database.Repository
+	log   *log.Logger
+	auth0 *auth0.Auth0
+}
+
+func (a *Activities) Project(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	q, err := a.query(r, []string{pid})
+	if err != nil {
+		return err
+	}
+
+	return a.respond(w, r, q)
+}
+
+// Team returns the feed of the team's projects that the caller can see.
+func (a *Activities) Team(w http.ResponseWriter, r *http.Request) error {
+	teamID := chi.URLParam(r, "teamId")
+	uid := a.auth0.UserByID(r.Context())
+
+	ps, err := projects.List(r.Context(), a.repo, uid)
+	if err != nil {
+		return errors.Wrapf(err, "listing projects of user %q", uid)
+	}
+
+	var pids []string
+	for _, p := range ps {
+		if p.TeamID == teamID {
+			pids = append(pids, p.ID)
+		}
+	}
+
+	q, err := a.query(r, pids)
+	if err != nil {
+		return err
+	}
+
+	return a.respond(w, r, q)
+}
+
+func (a *Activities) respond(w http.ResponseWriter, r *http.Request, q activities.Query) error {
+	page, err := activities.List(r.Context(), a.repo, q)
+	if err != nil {
+		switch err {
+		case activities.ErrInvalidCursor:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrap(err, "listing activities")
+		}
+	}
+
+	return web.Respond(r.Context(), w, page, http.StatusOK)
+}
+
+// query reads the feed parameters: actor, type (repeatable), cursor and limit.
+func (a *Activities) query(r *http.Request, pids []string) (activities.Query, error) {
+	v := r.URL.Query()
+
+	q := activities.Query{
+		ProjectIDs: pids,
+		ActorID:    v.Get("actor"),
+		Types:      v["type"],
+		Cursor:     v.Get("cursor"),
+		Client:     isClient(r.Context(), a.auth0),
+	}
+
+	if l := v.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > activities.MaxLimit {
+			return q, web.NewRequestError(errors.Errorf("limit must be between 1 and %d", activities.MaxLimit), http.StatusBadRequest)
+		}
+		q.Limit = n
+	}
+
+	return q, nil
+}
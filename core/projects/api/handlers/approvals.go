@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/api/publishers"
+	"github.com/devpies/devpie-client-core/projects/domain/approvals"
+	"github.com/devpies/devpie-client-core/projects/domain/permissions"
+	"github.com/devpies/devpie-client-core/projects/domain/roles"
+	"github.com/devpies/devpie-client-core/projects/domain/tasks"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+	"github.com/devpies/devpie-client-events/go/events"
+)
+
+type Approvals struct {
+	repo    *database.Repository
+	log     *log.Logger
+	auth0   *auth0.Auth0
+	nats    *events.Client
+	publish publishers.Publisher
+}
+
+func (a *Approvals) List(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+
+	if _, err := a.task(r, tid); err != nil {
+		return err
+	}
+
+	list, err := approvals.List(r.Context(), a.repo, tid)
+	if err != nil {
+		switch err {
+		case approvals.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "listing approvals of task %q", tid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (a *Approvals) Request(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+	uid := a.auth0.UserByID(r.Context())
+
+	var nr approvals.NewRequest
+	if err := web.Decode(r, &nr); err != nil {
+		return err
+	}
+
+	t, err := a.task(r, tid)
+	if err != nil {
+		return err
+	}
+
+	// approvers are clients who can see the task they approve
+	cs, err := roles.Lookup(r.Context(), a.repo, nr.Approvers)
+	if err != nil {
+		return errors.Wrap(err, "looking up approver roles")
+	}
+	for _, id := range nr.Approvers {
+		if !cs[id] {
+			return web.NewRequestError(errors.Errorf("approver %s is not a client", id), http.StatusBadRequest)
+		}
+		if _, err := permissions.Authorize(r.Context(), a.repo, t.ProjectID, id, permissions.ViewProject); err != nil {
+			switch err {
+			case permissions.ErrForbidden:
+				return web.NewRequestError(errors.Errorf("approver %s has no access to the project", id), http.StatusBadRequest)
+			default:
+				return errors.Wrapf(err, "authorizing approver %q", id)
+			}
+		}
+	}
+
+	ap, err := approvals.Request(r.Context(), a.repo, t, nr, uid, time.Now())
+	if err != nil {
+		switch err {
+		case approvals.ErrInternal:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return errors.Wrapf(err, "requesting approval of task %q", tid)
+		}
+	}
+
+	if err := notifyWatchers(a.nats, a.publish, nr.Approvers, taskWatchEvent(t, "sent for approval"), uid); err != nil {
+		return err
+	}
+
+	return web.Respond(r.Context(), w, ap, http.StatusCreated)
+}
+
+func (a *Approvals) Withdraw(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+	uid := a.auth0.UserByID(r.Context())
+
+	var nd approvals.NewDecision
+	if err := web.Decode(r, &nd); err != nil {
+		return err
+	}
+
+	t, err := a.task(r, tid)
+	if err != nil {
+		return err
+	}
+
+	ap, err := approvals.Withdraw(r.Context(), a.repo, t, nd, uid, time.Now())
+	if err != nil {
+		switch err {
+		case approvals.ErrNotPending:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return errors.Wrapf(err, "withdrawing approval of task %q", tid)
+		}
+	}
+
+	if err := notifyWatchers(a.nats, a.publish, t.Approvers, taskWatchEvent(t, "withdrawn from approval"), uid); err != nil {
+		return err
+	}
+
+	return web.Respond(r.Context(), w, ap, http.StatusCreated)
+}
+
+func (a *Approvals) Approve(w http.ResponseWriter, r *http.Request) error {
+	return a.decide(w, r, approvals.Approved)
+}
+
+func (a *Approvals) Reject(w http.ResponseWriter, r *http.Request) error {
+	return a.decide(w, r, approvals.Rejected)
+}
+
+func (a *Approvals) decide(w http.ResponseWriter, r *http.Request, action string) error {
+	tid := chi.URLParam(r, "tid")
+	uid := a.auth0.UserByID(r.Context())
+
+	if !a.auth0.HasRole(r.Context(), auth0.RoleClient) {
+		return web.NewRequestError(approvals.ErrNotApprover, http.StatusForbidden)
+	}
+
+	var nd approvals.NewDecision
+	if err := web.Decode(r, &nd); err != nil {
+		return err
+	}
+
+	t, err := a.task(r, tid)
+	if err != nil {
+		return err
+	}
+
+	ap, err := approvals.Decide(r.Context(), a.repo, t, action, nd, uid, time.Now())
+	if err != nil {
+		switch err {
+		case approvals.ErrNotApprover:
+			return web.NewRequestError(err, http.StatusForbidden)
+		case approvals.ErrNotPending:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return errors.Wrapf(err, "deciding approval of task %q", tid)
+		}
+	}
+
+	ids, err := taskRecipients(r.Context(), a.repo, t, uid)
+	if err != nil {
+		return err
+	}
+	if err := notifyWatchers(a.nats, a.publish, ids, taskWatchEvent(t, action), uid); err != nil {
+		return err
+	}
+
+	return web.Respond(r.Context(), w, ap, http.StatusCreated)
+}
+
+func (a *Approvals) task(r *http.Request, tid string) (tasks.Task, error) {
+	return visibleTask(r.Context(), a.repo, a.auth0, tid)
+}
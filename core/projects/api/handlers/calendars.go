@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/calendars"
+	"github.com/devpies/devpie-client-core/projects/domain/roles"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+type Calendars struct {
+	repo  *database.Repository
+	log   *log.Logger
+	auth0 *auth0.Auth0
+}
+
+func (c *Calendars) RetrieveToken(w http.ResponseWriter, r *http.Request) error {
+	uid := c.auth0.UserByID(r.Context())
+
+	t, err := calendars.RetrieveToken(r.Context(), c.repo, uid)
+	if err != nil {
+		switch err {
+		case calendars.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return errors.Wrapf(err, "looking for calendar token of %q", uid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, t, http.StatusOK)
+}
+
+func (c *Calendars) CreateToken(w http.ResponseWriter, r *http.Request) error {
+	uid := c.auth0.UserByID(r.Context())
+
+	t, err := calendars.CreateToken(r.Context(), c.repo, uid, time.Now())
+	if err != nil {
+		return errors.Wrapf(err, "creating calendar token of %q", uid)
+	}
+
+	return web.Respond(r.Context(), w, t, http.StatusCreated)
+}
+
+func (c *Calendars) RevokeToken(w http.ResponseWriter, r *http.Request) error {
+	uid := c.auth0.UserByID(r.Context())
+
+	if err := calendars.RevokeToken(r.Context(), c.repo, uid); err != nil {
+		switch err {
+		case calendars.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return errors.Wrapf(err, "revoking calendar token of %q", uid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+// Feed serves a user's calendar to calendar apps, which can't send a bearer token. The
+// feed token in the address identifies the user instead.
+func (c *Calendars) Feed(w http.ResponseWriter, r *http.Request) error {
+	token := chi.URLParam(r, "token")
+
+	uid, err := calendars.Authenticate(r.Context(), c.repo, token, time.Now())
+	if err != nil {
+		switch err {
+		case calendars.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return err
+		}
+	}
+
+	// feeds carry no access token, so the role recorded for the user decides, and users
+	// whose role is unknown are treated as clients
+	rs, err := roles.Lookup(r.Context(), c.repo, []string{uid})
+	if err != nil {
+		return errors.Wrapf(err, "looking up role of %q", uid)
+	}
+	client, known := rs[uid]
+
+	es, err := calendars.Events(r.Context(), c.repo, uid, client || !known)
+	if err != nil {
+		return errors.Wrapf(err, "listing calendar events of %q", uid)
+	}
+
+	var buf bytes.Buffer
+	if err := calendars.Render(&buf, "DevPie", es, time.Now()); err != nil {
+		return errors.Wrap(err, "rendering calendar")
+	}
+
+	return web.RespondDocument(r.Context(), w, buf.Bytes(), "text/calendar; charset=utf-8", http.StatusOK)
+}
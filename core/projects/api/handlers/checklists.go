@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/checklists"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+type Checklists struct {
+	repo  *database.Repository
+	log   *log.Logger
+	auth0 *auth0.Auth0
+}
+
+func (c *Checklists) List(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+
+	if _, err := visibleTask(r.Context(), c.repo, c.auth0, tid); err != nil {
+		return err
+	}
+
+	list, err := checklists.List(r.Context(), c.repo, tid)
+	if err != nil {
+		switch err {
+		case checklists.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "listing checklist of task %q", tid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (c *Checklists) Create(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+	uid := c.auth0.UserByID(r.Context())
+
+	var ni checklists.NewItem
+	if err := web.Decode(r, &ni); err != nil {
+		return err
+	}
+
+	ts, err := visibleTask(r.Context(), c.repo, c.auth0, tid)
+	if err != nil {
+		return err
+	}
+
+	if err := checkMember(r.Context(), c.repo, ts.ProjectID, ni.AssignedTo); err != nil {
+		return err
+	}
+
+	i, err := checklists.Create(r.Context(), c.repo, ni, ts.ProjectID, ts.ID, uid, time.Now())
+	if err != nil {
+		switch err {
+		case checklists.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "adding checklist item to task %q", tid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, i, http.StatusCreated)
+}
+
+func (c *Checklists) Update(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+	iid := chi.URLParam(r, "iid")
+
+	var ui checklists.UpdateItem
+	if err := web.Decode(r, &ui); err != nil {
+		return err
+	}
+
+	ts, err := visibleTask(r.Context(), c.repo, c.auth0, tid)
+	if err != nil {
+		return err
+	}
+	if ui.AssignedTo != nil {
+		if err := checkMember(r.Context(), c.repo, ts.ProjectID, *ui.AssignedTo); err != nil {
+			return err
+		}
+	}
+
+	i, err := checklists.Update(r.Context(), c.repo, tid, iid, ui, time.Now())
+	if err != nil {
+		switch err {
+		case checklists.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case checklists.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "updating checklist item %q", iid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, i, http.StatusOK)
+}
+
+func (c *Checklists) Toggle(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+	iid := chi.URLParam(r, "iid")
+
+	if _, err := visibleTask(r.Context(), c.repo, c.auth0, tid); err != nil {
+		return err
+	}
+
+	i, err := checklists.Toggle(r.Context(), c.repo, tid, iid, time.Now())
+	if err != nil {
+		switch err {
+		case checklists.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case checklists.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "toggling checklist item %q", iid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, i, http.StatusOK)
+}
+
+func (c *Checklists) Reorder(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+
+	if _, err := visibleTask(r.Context(), c.repo, c.auth0, tid); err != nil {
+		return err
+	}
+
+	var o checklists.Order
+	if err := web.Decode(r, &o); err != nil {
+		return err
+	}
+
+	list, err := checklists.Reorder(r.Context(), c.repo, tid, o, time.Now())
+	if err != nil {
+		switch err {
+		case checklists.ErrInvalidID, checklists.ErrInvalidOrder:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "reordering checklist of task %q", tid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (c *Checklists) Delete(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+	iid := chi.URLParam(r, "iid")
+
+	if _, err := visibleTask(r.Context(), c.repo, c.auth0, tid); err != nil {
+		return err
+	}
+
+	if err := checklists.Delete(r.Context(), c.repo, tid, iid); err != nil {
+		switch err {
+		case checklists.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case checklists.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "deleting checklist item %q", iid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
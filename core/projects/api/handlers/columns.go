@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"time"
@@ -8,6 +9,7 @@ import (
 	"github.com/go-chi/chi"
 
 	"github.com/devpies/devpie-client-core/projects/domain/columns"
+	"github.com/devpies/devpie-client-core/projects/domain/tasks"
 	"github.com/devpies/devpie-client-core/projects/platform/auth0"
 	"github.com/devpies/devpie-client-core/projects/platform/database"
 	"github.com/devpies/devpie-client-core/projects/platform/web"
@@ -27,6 +29,14 @@ func (c *Columns) List(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
+	if isClient(r.Context(), c.auth0) {
+		ts, err := tasks.List(r.Context(), c.repo, pid)
+		if err != nil {
+			return err
+		}
+		list = clientColumns(list, ts)
+	}
+
 	return web.Respond(r.Context(), w, list, http.StatusOK)
 }
 
@@ -99,3 +109,20 @@ func (c *Columns) Delete(w http.ResponseWriter, r *http.Request) error {
 
 	return web.Respond(r.Context(), w, nil, http.StatusOK)
 }
+
+// checkColumn makes sure a column belongs to the project.
+func checkColumn(ctx context.Context, repo *database.Repository, pid, cid string) error {
+	c, err := columns.Retrieve(ctx, repo, cid)
+	if err != nil {
+		switch err {
+		case columns.ErrNotFound, columns.ErrInvalidID:
+			return web.NewRequestError(columns.ErrNotFound, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "looking for column %q", cid)
+		}
+	}
+	if c.ProjectID != pid {
+		return web.NewRequestError(columns.ErrNotFound, http.StatusBadRequest)
+	}
+	return nil
+}
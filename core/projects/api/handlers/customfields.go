@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/customfields"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+type CustomFields struct {
+	repo  *database.Repository
+	log   *log.Logger
+	auth0 *auth0.Auth0
+}
+
+func (cf *CustomFields) List(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	list, err := customfields.List(r.Context(), cf.repo, pid)
+	if err != nil {
+		switch err {
+		case customfields.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "listing custom fields for project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (cf *CustomFields) Create(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	uid := cf.auth0.UserByID(r.Context())
+
+	var nf customfields.NewField
+	if err := web.Decode(r, &nf); err != nil {
+		return err
+	}
+
+	f, err := customfields.Create(r.Context(), cf.repo, nf, pid, uid, time.Now())
+	if err != nil {
+		switch err {
+		case customfields.ErrInvalidID, customfields.ErrMissingOptions:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case customfields.ErrNameTaken:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return errors.Wrapf(err, "creating custom field for project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, f, http.StatusCreated)
+}
+
+func (cf *CustomFields) Update(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	fid := chi.URLParam(r, "fid")
+
+	var uf customfields.UpdateField
+	if err := web.Decode(r, &uf); err != nil {
+		return err
+	}
+
+	f, err := customfields.Update(r.Context(), cf.repo, pid, fid, uf, time.Now())
+	if err != nil {
+		switch err {
+		case customfields.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case customfields.ErrInvalidID, customfields.ErrMissingOptions:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case customfields.ErrNameTaken:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return errors.Wrapf(err, "updating custom field %q", fid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, f, http.StatusOK)
+}
+
+func (cf *CustomFields) Delete(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	fid := chi.URLParam(r, "fid")
+
+	if err := customfields.Delete(r.Context(), cf.repo, pid, fid); err != nil {
+		switch err {
+		case customfields.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case customfields.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "deleting custom field %q", fid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/api/publishers"
+	"github.com/devpies/devpie-client-core/projects/domain/grants"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+	"github.com/devpies/devpie-client-events/go/events"
+)
+
+type Grants struct {
+	repo    *database.Repository
+	log     *log.Logger
+	auth0   *auth0.Auth0
+	nats    *events.Client
+	publish publishers.Publisher
+}
+
+func (g *Grants) List(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	list, err := grants.List(r.Context(), g.repo, pid)
+	if err != nil {
+		switch err {
+		case grants.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "listing grants for project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (g *Grants) Create(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	uid := g.auth0.UserByID(r.Context())
+
+	var ng grants.NewGrant
+	if err := web.Decode(r, &ng); err != nil {
+		return err
+	}
+
+	if ng.Expiration != nil && ng.Expiration.Before(time.Now()) {
+		return web.NewRequestError(errors.New("expiration must be in the future"), http.StatusBadRequest)
+	}
+
+	gr, err := grants.Create(r.Context(), g.repo, ng, pid, uid, time.Now())
+	if err != nil {
+		switch err {
+		case grants.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "granting access to project %q", pid)
+		}
+	}
+
+	if g.nats != nil {
+		if err := g.publish.GrantCreated(g.nats, gr, uid); err != nil {
+			return err
+		}
+	}
+
+	return web.Respond(r.Context(), w, gr, http.StatusCreated)
+}
+
+func (g *Grants) Delete(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	gid := chi.URLParam(r, "gid")
+	uid := g.auth0.UserByID(r.Context())
+
+	if err := grants.Delete(r.Context(), g.repo, pid, gid); err != nil {
+		switch err {
+		case grants.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case grants.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "revoking grant %q", gid)
+		}
+	}
+
+	if g.nats != nil {
+		if err := g.publish.GrantDeleted(g.nats, pid, gid, uid); err != nil {
+			return err
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/approvals"
+	"github.com/devpies/devpie-client-core/projects/domain/columns"
+	"github.com/devpies/devpie-client-core/projects/domain/integrations"
+	"github.com/devpies/devpie-client-core/projects/domain/tasks"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/githosts"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+// maxDelivery caps the size of git host deliveries read by the receiver.
+const maxDelivery = 5 << 20
+
+type Integrations struct {
+	repo  *database.Repository
+	log   *log.Logger
+	auth0 *auth0.Auth0
+	tasks *Tasks
+}
+
+func (in *Integrations) List(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	list, err := integrations.List(r.Context(), in.repo, pid)
+	if err != nil {
+		switch err {
+		case integrations.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "listing integrations for project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (in *Integrations) Create(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	uid := in.auth0.UserByID(r.Context())
+
+	var ni integrations.NewIntegration
+	if err := web.Decode(r, &ni); err != nil {
+		return err
+	}
+
+	if ni.MergeColumnID != "" {
+		if err := checkColumn(r.Context(), in.repo, pid, ni.MergeColumnID); err != nil {
+			return err
+		}
+	}
+
+	i, err := integrations.Create(r.Context(), in.repo, ni, pid, uid, time.Now())
+	if err != nil {
+		switch err {
+		case integrations.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "connecting repository to project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, i, http.StatusCreated)
+}
+
+func (in *Integrations) Update(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	iid := chi.URLParam(r, "iid")
+
+	var ui integrations.UpdateIntegration
+	if err := web.Decode(r, &ui); err != nil {
+		return err
+	}
+
+	if ui.MergeColumnID != nil && *ui.MergeColumnID != "" {
+		if err := checkColumn(r.Context(), in.repo, pid, *ui.MergeColumnID); err != nil {
+			return err
+		}
+	}
+
+	i, err := integrations.Update(r.Context(), in.repo, pid, iid, ui, time.Now())
+	if err != nil {
+		switch err {
+		case integrations.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case integrations.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "updating integration %q", iid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, i, http.StatusOK)
+}
+
+func (in *Integrations) Delete(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	iid := chi.URLParam(r, "iid")
+
+	if err := integrations.Delete(r.Context(), in.repo, pid, iid); err != nil {
+		switch err {
+		case integrations.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case integrations.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "deleting integration %q", iid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+func (in *Integrations) Links(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+
+	if _, err := visibleTask(r.Context(), in.repo, in.auth0, tid); err != nil {
+		return err
+	}
+
+	list, err := integrations.Links(r.Context(), in.repo, tid)
+	if err != nil {
+		switch err {
+		case integrations.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "listing links of task %q", tid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+// Receive handles push and pull request deliveries of a git host. Git hosts can't send an
+// Auth0 token, so deliveries are authenticated with the integration's secret instead.
+func (in *Integrations) Receive(w http.ResponseWriter, r *http.Request) error {
+	iid := chi.URLParam(r, "iid")
+
+	i, err := integrations.Retrieve(r.Context(), in.repo, iid)
+	if err != nil {
+		switch err {
+		case integrations.ErrNotFound, integrations.ErrInvalidID:
+			return web.NewRequestError(integrations.ErrNotFound, http.StatusNotFound)
+		default:
+			return errors.Wrapf(err, "looking for integration %q", iid)
+		}
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxDelivery))
+	if err != nil {
+		return errors.Wrap(err, "reading delivery")
+	}
+
+	if !githosts.Verify(i.Provider, r.Header, body, i.Secret) {
+		return web.NewRequestError(errors.New("delivery signature is invalid"), http.StatusUnauthorized)
+	}
+
+	refs, err := githosts.Parse(i.Provider, r.Header, body)
+	if err != nil {
+		switch err {
+		case githosts.ErrUnsupportedEvent:
+			return web.Respond(r.Context(), w, nil, http.StatusNoContent)
+		default:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		}
+	}
+
+	links := make([]integrations.Link, 0)
+	for _, ref := range refs {
+		ids, err := integrations.Match(r.Context(), in.repo, i.ProjectID, ref.Keys)
+		if err != nil {
+			return err
+		}
+
+		nl := integrations.NewLink{Kind: ref.Kind, URL: ref.URL, Title: ref.Title, Ref: ref.Ref, State: ref.State}
+		for _, tid := range ids {
+			l, err := integrations.Attach(r.Context(), in.repo, i, tid, nl, time.Now())
+			if err != nil {
+				return err
+			}
+			links = append(links, l)
+
+			if ref.Kind == githosts.PullRequest && ref.State == githosts.Merged && i.MergeColumnID != "" {
+				if err := in.moveMerged(r.Context(), i, tid); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return web.Respond(r.Context(), w, links, http.StatusOK)
+}
+
+// moveMerged moves a task to the integration's merge column. Tasks already there, or still
+// waiting for client approval of a move to done, stay where they are.
+func (in *Integrations) moveMerged(ctx context.Context, i integrations.Integration, tid string) error {
+	ts, err := tasks.Retrieve(ctx, in.repo, tid)
+	if err != nil {
+		return err
+	}
+
+	cT, err := columns.Retrieve(ctx, in.repo, i.MergeColumnID)
+	if err != nil {
+		if err == columns.ErrNotFound {
+			in.log.Printf("warning: merge column %s of integration %s no longer exists", i.MergeColumnID, i.ID)
+			return nil
+		}
+		return err
+	}
+
+	list, err := columns.List(ctx, in.repo, i.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	for _, cF := range list {
+		if SliceIndex(len(cF.TaskIDS), func(n int) bool { return cF.TaskIDS[n] == tid }) < 0 {
+			continue
+		}
+		if cF.ID == cT.ID {
+			return nil
+		}
+		if cT.IsDone() && !cF.IsDone() {
+			if err := approvals.CanComplete(ts); err != nil {
+				in.log.Printf("warning: task %s was merged but not moved: %v", ts.Key, err)
+				return nil
+			}
+		}
+		return in.tasks.move(ctx, ts, cF, cT, i.CreatedBy)
+	}
+
+	return nil
+}
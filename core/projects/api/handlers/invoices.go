@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/invoices"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+type Invoices struct {
+	repo  *database.Repository
+	log   *log.Logger
+	auth0 *auth0.Auth0
+}
+
+func (iv *Invoices) ListRates(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	list, err := invoices.ListRates(r.Context(), iv.repo, pid)
+	if err != nil {
+		switch err {
+		case invoices.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "listing rates for project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (iv *Invoices) SetRate(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	var nr invoices.NewRate
+	if err := web.Decode(r, &nr); err != nil {
+		return err
+	}
+
+	rt, err := invoices.SetRate(r.Context(), iv.repo, nr, pid, time.Now())
+	if err != nil {
+		switch err {
+		case invoices.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "setting rate for project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, rt, http.StatusOK)
+}
+
+func (iv *Invoices) DeleteRate(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	rid := chi.URLParam(r, "rid")
+
+	if err := invoices.DeleteRate(r.Context(), iv.repo, pid, rid); err != nil {
+		switch err {
+		case invoices.ErrRateNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case invoices.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "deleting rate %q", rid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+func (iv *Invoices) List(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	list, err := invoices.List(r.Context(), iv.repo, pid)
+	if err != nil {
+		switch err {
+		case invoices.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "listing invoices for project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (iv *Invoices) Create(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	uid := iv.auth0.UserByID(r.Context())
+
+	var ni invoices.NewInvoice
+	if err := web.Decode(r, &ni); err != nil {
+		return err
+	}
+
+	in, err := invoices.Generate(r.Context(), iv.repo, ni, pid, uid, time.Now())
+	if err != nil {
+		switch err {
+		case invoices.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case invoices.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case invoices.ErrNoRate, invoices.ErrNoEntries:
+			return web.NewRequestError(err, http.StatusUnprocessableEntity)
+		default:
+			return errors.Wrapf(err, "drafting invoice for project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, in, http.StatusCreated)
+}
+
+func (iv *Invoices) Retrieve(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	iid := chi.URLParam(r, "iid")
+
+	in, err := invoices.Retrieve(r.Context(), iv.repo, pid, iid)
+	if err != nil {
+		switch err {
+		case invoices.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case invoices.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "looking for invoice %q", iid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, in, http.StatusOK)
+}
+
+func (iv *Invoices) Document(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	iid := chi.URLParam(r, "iid")
+
+	in, err := invoices.Retrieve(r.Context(), iv.repo, pid, iid)
+	if err != nil {
+		switch err {
+		case invoices.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case invoices.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "looking for invoice %q", iid)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := invoices.Render(&buf, in); err != nil {
+		return errors.Wrapf(err, "rendering invoice %q", iid)
+	}
+
+	return web.RespondDocument(r.Context(), w, buf.Bytes(), "text/html; charset=utf-8", http.StatusOK)
+}
+
+func (iv *Invoices) Finalize(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	iid := chi.URLParam(r, "iid")
+
+	in, err := invoices.Finalize(r.Context(), iv.repo, pid, iid, time.Now())
+	if err != nil {
+		switch err {
+		case invoices.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case invoices.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case invoices.ErrNotDraft:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return errors.Wrapf(err, "finalizing invoice %q", iid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, in, http.StatusOK)
+}
+
+func (iv *Invoices) Delete(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	iid := chi.URLParam(r, "iid")
+
+	if err := invoices.Delete(r.Context(), iv.repo, pid, iid); err != nil {
+		switch err {
+		case invoices.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case invoices.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case invoices.ErrNotDraft:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return errors.Wrapf(err, "deleting invoice %q", iid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/transitions"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+type Metrics struct {
+	repo  *database.Repository
+	log   *log.Logger
+	auth0 *auth0.Auth0
+}
+
+// Retrieve returns the lead time, cycle time and column times of a project, over the last
+// 90 days unless from and to are given.
+func (m *Metrics) Retrieve(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	now := time.Now()
+	from, to, err := timeRange(r, now.AddDate(0, 0, -90), now)
+	if err != nil {
+		return err
+	}
+
+	ms, err := transitions.Compute(r.Context(), m.repo, pid, from, to, isClient(r.Context(), m.auth0))
+	if err != nil {
+		switch err {
+		case transitions.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "computing metrics of project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, ms, http.StatusOK)
+}
+
+// History returns the columns a task went through, oldest first.
+func (m *Metrics) History(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+
+	if _, err := visibleTask(r.Context(), m.repo, m.auth0, tid); err != nil {
+		return err
+	}
+
+	ts, err := transitions.List(r.Context(), m.repo, tid)
+	if err != nil {
+		switch err {
+		case transitions.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "listing transitions of task %q", tid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, ts, http.StatusOK)
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/milestones"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+type Milestones struct {
+	repo  *database.Repository
+	log   *log.Logger
+	auth0 *auth0.Auth0
+}
+
+func (m *Milestones) List(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	list, err := milestones.List(r.Context(), m.repo, pid, isClient(r.Context(), m.auth0))
+	if err != nil {
+		switch err {
+		case milestones.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "listing milestones for project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (m *Milestones) Timeline(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	list, err := milestones.Timeline(r.Context(), m.repo, pid, isClient(r.Context(), m.auth0), time.Now())
+	if err != nil {
+		switch err {
+		case milestones.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "building timeline for project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (m *Milestones) Retrieve(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	mid := chi.URLParam(r, "mid")
+
+	ms, err := milestones.Retrieve(r.Context(), m.repo, pid, mid, isClient(r.Context(), m.auth0))
+	if err != nil {
+		switch err {
+		case milestones.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case milestones.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "looking for milestone %q", mid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, ms, http.StatusOK)
+}
+
+func (m *Milestones) Create(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	uid := m.auth0.UserByID(r.Context())
+
+	var nm milestones.NewMilestone
+	if err := web.Decode(r, &nm); err != nil {
+		return err
+	}
+
+	ms, err := milestones.Create(r.Context(), m.repo, nm, pid, uid, time.Now())
+	if err != nil {
+		switch err {
+		case milestones.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "adding milestone to project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, ms, http.StatusCreated)
+}
+
+func (m *Milestones) Update(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	mid := chi.URLParam(r, "mid")
+
+	var um milestones.UpdateMilestone
+	if err := web.Decode(r, &um); err != nil {
+		return err
+	}
+
+	ms, err := milestones.Update(r.Context(), m.repo, pid, mid, um, isClient(r.Context(), m.auth0), time.Now())
+	if err != nil {
+		switch err {
+		case milestones.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case milestones.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "updating milestone %q", mid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, ms, http.StatusOK)
+}
+
+func (m *Milestones) Delete(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	mid := chi.URLParam(r, "mid")
+
+	if err := milestones.Delete(r.Context(), m.repo, pid, mid); err != nil {
+		switch err {
+		case milestones.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case milestones.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "deleting milestone %q", mid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
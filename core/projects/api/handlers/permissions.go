@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/memberships"
+	"github.com/devpies/devpie-client-core/projects/domain/permissions"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+// Permissions guards project scoped routes with the permission matrix.
+type Permissions struct {
+	repo  *database.Repository
+	auth0 *auth0.Auth0
+}
+
+// Require wraps a handler so it only runs when the authenticated user may perform the
+// action on the project behind the pid, cid or tid route parameters.
+func (p *Permissions) Require(action permissions.Action, h web.Handler) web.Handler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		uid := p.auth0.UserByID(r.Context())
+
+		s := permissions.Scope{
+			ProjectID: chi.URLParam(r, "pid"),
+			ColumnID:  chi.URLParam(r, "cid"),
+			TaskID:    chi.URLParam(r, "tid"),
+		}
+
+		pid, err := permissions.ResolveProject(r.Context(), p.repo, s)
+		if err == nil {
+			_, err = permissions.Authorize(r.Context(), p.repo, pid, uid, action)
+		}
+		if err != nil {
+			switch err {
+			case permissions.ErrNotFound:
+				return web.NewRequestError(err, http.StatusNotFound)
+			case permissions.ErrInvalidID, permissions.ErrNoResource:
+				return web.NewRequestError(err, http.StatusBadRequest)
+			case permissions.ErrForbidden:
+				return web.NewRequestError(err, http.StatusForbidden)
+			default:
+				return errors.Wrapf(err, "authorizing %q", r.URL.Path)
+			}
+		}
+
+		return h(w, r)
+	}
+}
+
+// checkTeamAdmin makes sure a user administers a team, for settings shared by all of the
+// team's projects.
+func checkTeamAdmin(ctx context.Context, repo *database.Repository, teamID, uid string) error {
+	m, err := memberships.Retrieve(ctx, repo, uid, teamID)
+	if err != nil {
+		switch err {
+		case memberships.ErrNotFound, memberships.ErrInvalidID:
+			return web.NewRequestError(permissions.ErrForbidden, http.StatusForbidden)
+		default:
+			return errors.Wrapf(err, "looking for membership of %q in team %q", uid, teamID)
+		}
+	}
+
+	if role, err := memberships.ParseRole(m.Role); err != nil || role != memberships.Administrator {
+		return web.NewRequestError(permissions.ErrForbidden, http.StatusForbidden)
+	}
+
+	return nil
+}
+
+// checkMember makes sure a user picked in a request, such as an assignee, can see the project.
+func checkMember(ctx context.Context, repo *database.Repository, pid, uid string) error {
+	if uid == "" {
+		return nil
+	}
+
+	if _, err := permissions.Authorize(ctx, repo, pid, uid, permissions.ViewProject); err != nil {
+		switch err {
+		case permissions.ErrForbidden:
+			return web.NewRequestError(errors.Errorf("user %s has no access to the project", uid), http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "authorizing user %q", uid)
+		}
+	}
+
+	return nil
+}
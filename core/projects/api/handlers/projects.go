@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -11,9 +12,26 @@ import (
 	"github.com/go-chi/chi"
 	"github.com/google/uuid"
 
+	"github.com/devpies/devpie-client-core/projects/api/publishers"
+	"github.com/devpies/devpie-client-core/projects/domain/activities"
+	"github.com/devpies/devpie-client-core/projects/domain/approvals"
+	"github.com/devpies/devpie-client-core/projects/domain/checklists"
 	"github.com/devpies/devpie-client-core/projects/domain/columns"
+	"github.com/devpies/devpie-client-core/projects/domain/customfields"
+	"github.com/devpies/devpie-client-core/projects/domain/grants"
+	"github.com/devpies/devpie-client-core/projects/domain/integrations"
+	"github.com/devpies/devpie-client-core/projects/domain/invoices"
+	"github.com/devpies/devpie-client-core/projects/domain/milestones"
+	"github.com/devpies/devpie-client-core/projects/domain/permissions"
 	"github.com/devpies/devpie-client-core/projects/domain/projects"
+	"github.com/devpies/devpie-client-core/projects/domain/recurrences"
 	"github.com/devpies/devpie-client-core/projects/domain/tasks"
+	"github.com/devpies/devpie-client-core/projects/domain/templates"
+	"github.com/devpies/devpie-client-core/projects/domain/timeentries"
+	"github.com/devpies/devpie-client-core/projects/domain/transitions"
+	"github.com/devpies/devpie-client-core/projects/domain/views"
+	"github.com/devpies/devpie-client-core/projects/domain/watchers"
+	"github.com/devpies/devpie-client-core/projects/domain/webhooks"
 	"github.com/devpies/devpie-client-core/projects/platform/auth0"
 	"github.com/devpies/devpie-client-core/projects/platform/database"
 	"github.com/devpies/devpie-client-core/projects/platform/web"
@@ -21,10 +39,11 @@ import (
 )
 
 type Projects struct {
-	repo  *database.Repository
-	log   *log.Logger
-	auth0 *auth0.Auth0
-	nats  *events.Client
+	repo    *database.Repository
+	log     *log.Logger
+	auth0   *auth0.Auth0
+	nats    *events.Client
+	publish publishers.Publisher
 }
 
 func (p *Projects) List(w http.ResponseWriter, r *http.Request) error {
@@ -100,7 +119,8 @@ func (p *Projects) Create(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
-	titles := [4]string{"To Do", "In Progress", "Review", "Done"}
+	titles := [4]string{"To Do", "In Progress", "Review", columns.Done}
+	cs := make([]columns.Column, 0, len(titles))
 
 	for i, title := range titles {
 		nt := columns.NewColumn{
@@ -108,14 +128,21 @@ func (p *Projects) Create(w http.ResponseWriter, r *http.Request) error {
 			Title:      title,
 			ColumnName: fmt.Sprintf(`column-%d`, i+1),
 		}
-		_, err := columns.Create(r.Context(), p.repo, nt, time.Now())
+		c, err := columns.Create(r.Context(), p.repo, nt, time.Now())
 		if err != nil {
 			return err
 		}
+		cs = append(cs, c)
 	}
 
 	p.nats.Publish(string(events.TypeProjectCreated), bytes)
 
+	for _, c := range cs {
+		if err := p.publish.ColumnCreated(p.nats, c, uid); err != nil {
+			return err
+		}
+	}
+
 	return web.Respond(r.Context(), w, pr, http.StatusCreated)
 }
 
@@ -130,6 +157,12 @@ func (p *Projects) Update(w http.ResponseWriter, r *http.Request) error {
 		return errors.Wrap(err, "decoding project update")
 	}
 
+	if update.TeamID != nil {
+		if err := p.checkTeamChange(r.Context(), pid, *update.TeamID, uid); err != nil {
+			return err
+		}
+	}
+
 	up, err := projects.Update(r.Context(), p.repo, pid, uid, update, time.Now())
 	if err != nil {
 		switch err {
@@ -168,26 +201,138 @@ func (p *Projects) Update(w http.ResponseWriter, r *http.Request) error {
 
 	p.nats.Publish(string(events.EventsProjectUpdated), bytes)
 
+	ids, err := projectRecipients(r.Context(), p.repo, up.ID, "", uid)
+	if err != nil {
+		return err
+	}
+	pw := publishers.WatchEventData{ProjectID: up.ID, Title: up.Name, Action: "updated"}
+	if err := notifyWatchers(p.nats, p.publish, ids, pw, uid); err != nil {
+		return err
+	}
+
 	return web.Respond(r.Context(), w, up, http.StatusOK)
 }
 
+// checkTeamChange makes sure a user moving a project to another team administers both the
+// team it leaves and the one it joins, since the move changes who inherits access to it. A
+// project without a team can only be moved by its administrators.
+func (p *Projects) checkTeamChange(ctx context.Context, pid, teamID, uid string) error {
+	current, err := projects.RetrieveTeamID(ctx, p.repo, pid)
+	if err != nil {
+		switch err {
+		case projects.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case projects.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "looking for team of project %q", pid)
+		}
+	}
+
+	if teamID == current {
+		return nil
+	}
+
+	if current == "" {
+		if _, err := permissions.Authorize(ctx, p.repo, pid, uid, permissions.ManageGrants); err != nil {
+			switch err {
+			case permissions.ErrForbidden:
+				return web.NewRequestError(err, http.StatusForbidden)
+			default:
+				return errors.Wrapf(err, "authorizing %q on project %q", uid, pid)
+			}
+		}
+	} else if err := checkTeamAdmin(ctx, p.repo, current, uid); err != nil {
+		return err
+	}
+
+	if teamID == "" {
+		return nil
+	}
+	return checkTeamAdmin(ctx, p.repo, teamID, uid)
+}
+
 func (p *Projects) Delete(w http.ResponseWriter, r *http.Request) error {
 	pid := chi.URLParam(r, "pid")
 	uid := p.auth0.UserByID(r.Context())
 
-	if _, err := projects.Retrieve(r.Context(), p.repo, pid, uid); err != nil {
-		_, err := projects.RetrieveShared(r.Context(), p.repo, pid, uid)
-		if err == nil {
-			return web.NewRequestError(err, http.StatusUnauthorized)
+	pr, err := projects.Retrieve(r.Context(), p.repo, pid, uid)
+	if err != nil {
+		pr, err = projects.RetrieveShared(r.Context(), p.repo, pid, uid)
+		if err != nil {
+			return errors.Wrapf(err, "looking for project %q", pid)
+		}
+	}
+
+	// nothing is removed when billed work would go with the project
+	if err := invoices.CheckBilled(r.Context(), p.repo, pid); err != nil {
+		switch err {
+		case invoices.ErrBilled:
+			return web.NewRequestError(err, http.StatusConflict)
+		case invoices.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return err
 		}
 	}
+
+	ids, err := projectRecipients(r.Context(), p.repo, pid, "", uid)
+	if err != nil {
+		return err
+	}
+
+	if err := watchers.DeleteAll(r.Context(), p.repo, pid); err != nil {
+		return err
+	}
+	if err := webhooks.DeleteAll(r.Context(), p.repo, pid); err != nil {
+		return err
+	}
+	if err := approvals.DeleteAll(r.Context(), p.repo, pid); err != nil {
+		return err
+	}
+	if err := checklists.DeleteAll(r.Context(), p.repo, pid); err != nil {
+		return err
+	}
+	if err := integrations.DeleteAll(r.Context(), p.repo, pid); err != nil {
+		return err
+	}
+	if err := milestones.DeleteAll(r.Context(), p.repo, pid); err != nil {
+		return err
+	}
+	if err := templates.DeleteAll(r.Context(), p.repo, pid); err != nil {
+		return err
+	}
+	if err := recurrences.DeleteAll(r.Context(), p.repo, pid); err != nil {
+		return err
+	}
+	if err := customfields.DeleteAll(r.Context(), p.repo, pid); err != nil {
+		return err
+	}
+	if err := views.DeleteAll(r.Context(), p.repo, pid); err != nil {
+		return err
+	}
+	if err := activities.DeleteAll(r.Context(), p.repo, pid); err != nil {
+		return err
+	}
+	if err := transitions.DeleteAll(r.Context(), p.repo, pid); err != nil {
+		return err
+	}
+	if err := timeentries.DeleteAll(r.Context(), p.repo, pid); err != nil {
+		return err
+	}
+	if err := invoices.DeleteAll(r.Context(), p.repo, pid); err != nil {
+		return err
+	}
 	if err := tasks.DeleteAll(r.Context(), p.repo, pid); err != nil {
 		return err
 	}
 	if err := columns.DeleteAll(r.Context(), p.repo, pid); err != nil {
 		return err
 	}
-	if err := projects.Delete(r.Context(), p.repo, pid, uid); err != nil {
+	if err := grants.DeleteAll(r.Context(), p.repo, pid); err != nil {
+		return err
+	}
+	if err := projects.Delete(r.Context(), p.repo, pid); err != nil {
 		switch err {
 		case projects.ErrInvalidID:
 			return web.NewRequestError(err, http.StatusBadRequest)
@@ -215,5 +360,10 @@ func (p *Projects) Delete(w http.ResponseWriter, r *http.Request) error {
 
 	p.nats.Publish(string(events.EventsProjectDeleted), bytes)
 
+	pw := publishers.WatchEventData{ProjectID: pid, Title: pr.Name, Action: "deleted"}
+	if err := notifyWatchers(p.nats, p.publish, ids, pw, uid); err != nil {
+		return err
+	}
+
 	return web.Respond(r.Context(), w, nil, http.StatusOK)
 }
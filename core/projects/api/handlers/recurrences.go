@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/recurrences"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+type Recurrences struct {
+	repo  *database.Repository
+	log   *log.Logger
+	auth0 *auth0.Auth0
+}
+
+func (rc *Recurrences) List(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	list, err := recurrences.List(r.Context(), rc.repo, pid)
+	if err != nil {
+		switch err {
+		case recurrences.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "listing recurrences for project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (rc *Recurrences) Retrieve(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	rid := chi.URLParam(r, "rid")
+
+	rec, err := recurrences.Retrieve(r.Context(), rc.repo, pid, rid)
+	if err != nil {
+		switch err {
+		case recurrences.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case recurrences.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "looking for recurrence %q", rid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, rec, http.StatusOK)
+}
+
+func (rc *Recurrences) Create(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+	uid := rc.auth0.UserByID(r.Context())
+
+	var nr recurrences.NewRecurrence
+	if err := web.Decode(r, &nr); err != nil {
+		return err
+	}
+
+	ts, err := visibleTask(r.Context(), rc.repo, rc.auth0, tid)
+	if err != nil {
+		return err
+	}
+
+	if err := checkColumn(r.Context(), rc.repo, ts.ProjectID, nr.ColumnID); err != nil {
+		return err
+	}
+
+	rec, err := recurrences.Create(r.Context(), rc.repo, nr, ts.ProjectID, ts.ID, uid, time.Now())
+	if err != nil {
+		switch err {
+		case recurrences.ErrInvalidID, recurrences.ErrInvalidStart, recurrences.ErrEnded:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case recurrences.ErrAlreadyRecurring:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return errors.Wrapf(err, "creating recurrence for task %q", tid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, rec, http.StatusCreated)
+}
+
+func (rc *Recurrences) Update(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	rid := chi.URLParam(r, "rid")
+
+	var ur recurrences.UpdateRecurrence
+	if err := web.Decode(r, &ur); err != nil {
+		return err
+	}
+
+	if ur.ColumnID != nil {
+		if err := checkColumn(r.Context(), rc.repo, pid, *ur.ColumnID); err != nil {
+			return err
+		}
+	}
+
+	rec, err := recurrences.Update(r.Context(), rc.repo, pid, rid, ur, time.Now())
+	if err != nil {
+		switch err {
+		case recurrences.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case recurrences.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "updating recurrence %q", rid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, rec, http.StatusOK)
+}
+
+func (rc *Recurrences) Delete(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	rid := chi.URLParam(r, "rid")
+
+	if err := recurrences.Delete(r.Context(), rc.repo, pid, rid); err != nil {
+		switch err {
+		case recurrences.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case recurrences.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "stopping recurrence %q", rid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
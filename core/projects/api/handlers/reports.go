@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/projects"
+	"github.com/devpies/devpie-client-core/projects/domain/reports"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+type Reports struct {
+	repo  *database.Repository
+	log   *log.Logger
+	auth0 *auth0.Auth0
+}
+
+func (rp *Reports) Export(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = reports.HTML
+	}
+
+	now := time.Now()
+	from, to, err := timeRange(r, now.AddDate(0, 0, -7), now)
+	if err != nil {
+		return err
+	}
+
+	teamID, err := projects.RetrieveTeamID(r.Context(), rp.repo, pid)
+	if err != nil {
+		switch err {
+		case projects.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case projects.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "looking for project %q", pid)
+		}
+	}
+
+	tmpl, err := reports.RetrieveTemplate(r.Context(), rp.repo, teamID, format)
+	if err != nil {
+		switch err {
+		case reports.ErrInvalidFormat:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "looking for %s report template", format)
+		}
+	}
+
+	report, err := reports.Build(r.Context(), rp.repo, pid, from, to, now, isClient(r.Context(), rp.auth0))
+	if err != nil {
+		switch err {
+		case reports.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case reports.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "building status report of project %q", pid)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := reports.Render(&buf, format, tmpl.Body, report); err != nil {
+		return errors.Wrapf(err, "rendering status report of project %q", pid)
+	}
+
+	// templates are written by team admins, but viewers shouldn't run whatever they contain
+	if format == reports.HTML {
+		w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; img-src data:; sandbox")
+	}
+
+	return web.RespondDocument(r.Context(), w, buf.Bytes(), reports.ContentType(format), http.StatusOK)
+}
+
+func (rp *Reports) RetrieveTemplate(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	format := chi.URLParam(r, "format")
+
+	teamID, err := projects.RetrieveTeamID(r.Context(), rp.repo, pid)
+	if err != nil {
+		return errors.Wrapf(err, "looking for project %q", pid)
+	}
+
+	tmpl, err := reports.RetrieveTemplate(r.Context(), rp.repo, teamID, format)
+	if err != nil {
+		switch err {
+		case reports.ErrInvalidFormat:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "looking for %s report template", format)
+		}
+	}
+
+	return web.Respond(r.Context(), w, tmpl, http.StatusOK)
+}
+
+func (rp *Reports) SetTemplate(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	format := chi.URLParam(r, "format")
+	uid := rp.auth0.UserByID(r.Context())
+
+	var nt reports.NewTemplate
+	if err := web.Decode(r, &nt); err != nil {
+		return err
+	}
+
+	if err := reports.Validate(format, nt.Body); err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	teamID, err := rp.adminTeam(r, pid)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := reports.SetTemplate(r.Context(), rp.repo, teamID, format, nt, uid, time.Now())
+	if err != nil {
+		switch err {
+		case reports.ErrNoTeam, reports.ErrInvalidID, reports.ErrInvalidFormat:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "saving %s report template", format)
+		}
+	}
+
+	return web.Respond(r.Context(), w, tmpl, http.StatusOK)
+}
+
+func (rp *Reports) DeleteTemplate(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	format := chi.URLParam(r, "format")
+
+	teamID, err := rp.adminTeam(r, pid)
+	if err != nil {
+		return err
+	}
+
+	if err := reports.DeleteTemplate(r.Context(), rp.repo, teamID, format); err != nil {
+		switch err {
+		case reports.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case reports.ErrNoTeam, reports.ErrInvalidFormat:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "deleting %s report template", format)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+// adminTeam returns the team of a project when the caller administers it. Templates are
+// shared by every project of the team, so project editors can't change them.
+func (rp *Reports) adminTeam(r *http.Request, pid string) (string, error) {
+	teamID, err := projects.RetrieveTeamID(r.Context(), rp.repo, pid)
+	if err != nil {
+		return "", errors.Wrapf(err, "looking for project %q", pid)
+	}
+	if teamID == "" {
+		return "", web.NewRequestError(reports.ErrNoTeam, http.StatusBadRequest)
+	}
+
+	if err := checkTeamAdmin(r.Context(), rp.repo, teamID, rp.auth0.UserByID(r.Context())); err != nil {
+		return "", err
+	}
+
+	return teamID, nil
+}
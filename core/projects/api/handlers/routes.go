@@ -4,16 +4,21 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
 
 	mid "github.com/devpies/devpie-client-core/projects/api/middleware"
+	"github.com/devpies/devpie-client-core/projects/api/publishers"
+	"github.com/devpies/devpie-client-core/projects/domain/permissions"
 	"github.com/devpies/devpie-client-core/projects/platform/auth0"
 	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/stream"
 	"github.com/devpies/devpie-client-core/projects/platform/web"
 	"github.com/devpies/devpie-client-events/go/events"
 )
 
 func API(shutdown chan os.Signal, repo *database.Repository, log *log.Logger, origins string,
-	auth0Audience, auth0Domain, auth0MAPIAudience, auth0M2MClient, auth0M2MSecret string, nats *events.Client) http.Handler {
+	auth0Audience, auth0Domain, auth0MAPIAudience, auth0M2MClient, auth0M2MSecret string, nats *events.Client,
+	hub *stream.Hub, appURL string) http.Handler {
 
 	a0 := &auth0.Auth0{
 		Repo:         repo,
@@ -24,27 +29,136 @@ func API(shutdown chan os.Signal, repo *database.Repository, log *log.Logger, or
 		MAPIAudience: auth0MAPIAudience,
 	}
 
-	app := web.NewApp(shutdown, log, mid.Logger(log), a0.Authenticate(), mid.Errors(log), mid.Panics(log))
+	rl := &Roles{repo: repo, auth0: a0}
+
+	app := web.NewApp(shutdown, log, mid.Logger(log), a0.Authenticate(), mid.Errors(log), mid.Panics(log), rl.Record())
 
 	h := HealthCheck{repo: repo}
 
 	app.Handle(http.MethodGet, "/api/v1/health", h.Health)
 
-	t := Tasks{repo: repo, log: log, auth0: a0}
+	t := Tasks{repo: repo, log: log, auth0: a0, nats: nats, publish: &publishers.Publishers{}, appURL: strings.TrimRight(appURL, "/")}
 	c := Columns{repo: repo, log: log, auth0: a0}
-	p := Projects{repo: repo, log: log, auth0: a0, nats: nats}
+	p := Projects{repo: repo, log: log, auth0: a0, nats: nats, publish: &publishers.Publishers{}}
+	st := Stream{repo: repo, log: log, auth0: a0, hub: hub}
+	g := Grants{repo: repo, log: log, auth0: a0, nats: nats, publish: &publishers.Publishers{}}
+	wt := Watchers{repo: repo, log: log, auth0: a0}
+	wh := Webhooks{repo: repo, log: log, auth0: a0}
+	ti := Time{repo: repo, log: log, auth0: a0}
+	iv := Invoices{repo: repo, log: log, auth0: a0}
+	ms := Milestones{repo: repo, log: log, auth0: a0}
+	tp := Templates{repo: repo, log: log, auth0: a0}
+	rc := Recurrences{repo: repo, log: log, auth0: a0}
+	cf := CustomFields{repo: repo, log: log, auth0: a0}
+	vw := Views{repo: repo, log: log, auth0: a0}
+	ac := Activities{repo: repo, log: log, auth0: a0}
+	sts := Stats{repo: repo, log: log, auth0: a0}
+	mt := Metrics{repo: repo, log: log, auth0: a0}
+	rp := Reports{repo: repo, log: log, auth0: a0}
+	cal := Calendars{repo: repo, log: log, auth0: a0}
+	cl := Checklists{repo: repo, log: log, auth0: a0}
+	gi := Integrations{repo: repo, log: log, auth0: a0, tasks: &t}
+	ap := Approvals{repo: repo, log: log, auth0: a0, nats: nats, publish: &publishers.Publishers{}}
+	pm := Permissions{repo: repo, auth0: a0}
 
 	app.Handle(http.MethodGet, "/api/v1/projects", p.List)
 	app.Handle(http.MethodPost, "/api/v1/projects", p.Create)
-	app.Handle(http.MethodGet, "/api/v1/projects/{pid}", p.Retrieve)
-	app.Handle(http.MethodPatch, "/api/v1/projects/{pid}", p.Update)
-	app.Handle(http.MethodDelete, "/api/v1/projects/{pid}", p.Delete)
-	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/columns", c.List)
-	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/tasks", t.List)
-	app.Handle(http.MethodPost, "/api/v1/projects/{pid}/columns/{cid}/tasks", t.Create)
-	app.Handle(http.MethodPatch, "/api/v1/projects/tasks/{tid}", t.Update)
-	app.Handle(http.MethodPatch, "/api/v1/projects/tasks/{tid}/move", t.Move)
-	app.Handle(http.MethodDelete, "/api/v1/projects/columns/{cid}/tasks/{tid}", t.Delete)
+	app.Handle(http.MethodGet, "/api/v1/projects/watching", wt.List)
+	app.Handle(http.MethodGet, "/api/v1/projects/time", ti.List)
+	app.Handle(http.MethodPatch, "/api/v1/projects/time/{eid}", ti.Update)
+	app.Handle(http.MethodDelete, "/api/v1/projects/time/{eid}", ti.Delete)
+	app.Handle(http.MethodPost, "/api/v1/projects/time/{eid}/stop", ti.Stop)
+	app.Handle(http.MethodGet, "/api/v1/projects/teams/{teamId}/activity", ac.Team)
+	app.Handle(http.MethodGet, "/api/v1/projects/calendar/token", cal.RetrieveToken)
+	app.Handle(http.MethodPost, "/api/v1/projects/calendar/token", cal.CreateToken)
+	app.Handle(http.MethodDelete, "/api/v1/projects/calendar/token", cal.RevokeToken)
+	app.HandleWith(http.MethodGet, "/api/v1/projects/calendar/{token}.ics", cal.Feed, mid.RedactedLogger(log, "token"), mid.Errors(log), mid.Panics(log))
+	app.HandleWith(http.MethodPost, "/api/v1/projects/integrations/{iid}/deliveries", gi.Receive, mid.Logger(log), mid.Errors(log), mid.Panics(log))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}", pm.Require(permissions.ViewProject, p.Retrieve))
+	app.Handle(http.MethodPatch, "/api/v1/projects/{pid}", pm.Require(permissions.UpdateProject, p.Update))
+	app.Handle(http.MethodDelete, "/api/v1/projects/{pid}", pm.Require(permissions.DeleteProject, p.Delete))
+	app.HandleWith(http.MethodGet, "/api/v1/projects/{pid}/stream", pm.Require(permissions.ViewProject, st.Subscribe),
+		mid.Logger(log), a0.AuthenticateStream(), mid.Errors(log), mid.Panics(log), rl.Record())
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/grants", pm.Require(permissions.ManageGrants, g.List))
+	app.Handle(http.MethodPost, "/api/v1/projects/{pid}/grants", pm.Require(permissions.ManageGrants, g.Create))
+	app.Handle(http.MethodDelete, "/api/v1/projects/{pid}/grants/{gid}", pm.Require(permissions.ManageGrants, g.Delete))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/webhooks", pm.Require(permissions.ManageWebhooks, wh.List))
+	app.Handle(http.MethodPost, "/api/v1/projects/{pid}/webhooks", pm.Require(permissions.ManageWebhooks, wh.Create))
+	app.Handle(http.MethodDelete, "/api/v1/projects/{pid}/webhooks/{wid}", pm.Require(permissions.ManageWebhooks, wh.Delete))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/webhooks/{wid}/deliveries", pm.Require(permissions.ManageWebhooks, wh.Deliveries))
+	app.Handle(http.MethodPost, "/api/v1/projects/{pid}/webhooks/{wid}/deliveries/{did}/redeliver", pm.Require(permissions.ManageWebhooks, wh.Redeliver))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/integrations", pm.Require(permissions.ManageWebhooks, gi.List))
+	app.Handle(http.MethodPost, "/api/v1/projects/{pid}/integrations", pm.Require(permissions.ManageWebhooks, gi.Create))
+	app.Handle(http.MethodPatch, "/api/v1/projects/{pid}/integrations/{iid}", pm.Require(permissions.ManageWebhooks, gi.Update))
+	app.Handle(http.MethodDelete, "/api/v1/projects/{pid}/integrations/{iid}", pm.Require(permissions.ManageWebhooks, gi.Delete))
+	app.Handle(http.MethodPost, "/api/v1/projects/{pid}/watch", pm.Require(permissions.ViewProject, wt.WatchProject))
+	app.Handle(http.MethodDelete, "/api/v1/projects/{pid}/watch", pm.Require(permissions.ViewProject, wt.UnwatchProject))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/time", pm.Require(permissions.ViewProject, ti.Totals))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/rates", pm.Require(permissions.ManageBilling, iv.ListRates))
+	app.Handle(http.MethodPut, "/api/v1/projects/{pid}/rates", pm.Require(permissions.ManageBilling, iv.SetRate))
+	app.Handle(http.MethodDelete, "/api/v1/projects/{pid}/rates/{rid}", pm.Require(permissions.ManageBilling, iv.DeleteRate))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/invoices", pm.Require(permissions.ManageBilling, iv.List))
+	app.Handle(http.MethodPost, "/api/v1/projects/{pid}/invoices", pm.Require(permissions.ManageBilling, iv.Create))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/invoices/{iid}", pm.Require(permissions.ManageBilling, iv.Retrieve))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/invoices/{iid}/html", pm.Require(permissions.ManageBilling, iv.Document))
+	app.Handle(http.MethodPost, "/api/v1/projects/{pid}/invoices/{iid}/finalize", pm.Require(permissions.ManageBilling, iv.Finalize))
+	app.Handle(http.MethodDelete, "/api/v1/projects/{pid}/invoices/{iid}", pm.Require(permissions.ManageBilling, iv.Delete))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/report", pm.Require(permissions.ViewProject, rp.Export))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/report/templates/{format}", pm.Require(permissions.ViewProject, rp.RetrieveTemplate))
+	app.Handle(http.MethodPut, "/api/v1/projects/{pid}/report/templates/{format}", pm.Require(permissions.UpdateProject, rp.SetTemplate))
+	app.Handle(http.MethodDelete, "/api/v1/projects/{pid}/report/templates/{format}", pm.Require(permissions.UpdateProject, rp.DeleteTemplate))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/milestones", pm.Require(permissions.ViewProject, ms.List))
+	app.Handle(http.MethodPost, "/api/v1/projects/{pid}/milestones", pm.Require(permissions.UpdateProject, ms.Create))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/milestones/timeline", pm.Require(permissions.ViewProject, ms.Timeline))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/milestones/{mid}", pm.Require(permissions.ViewProject, ms.Retrieve))
+	app.Handle(http.MethodPatch, "/api/v1/projects/{pid}/milestones/{mid}", pm.Require(permissions.UpdateProject, ms.Update))
+	app.Handle(http.MethodDelete, "/api/v1/projects/{pid}/milestones/{mid}", pm.Require(permissions.UpdateProject, ms.Delete))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/templates", pm.Require(permissions.ViewProject, tp.List))
+	app.Handle(http.MethodPost, "/api/v1/projects/{pid}/templates", pm.Require(permissions.UpdateProject, tp.Create))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/templates/{tpid}", pm.Require(permissions.ViewProject, tp.Retrieve))
+	app.Handle(http.MethodPatch, "/api/v1/projects/{pid}/templates/{tpid}", pm.Require(permissions.UpdateProject, tp.Update))
+	app.Handle(http.MethodDelete, "/api/v1/projects/{pid}/templates/{tpid}", pm.Require(permissions.UpdateProject, tp.Delete))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/fields", pm.Require(permissions.ViewProject, cf.List))
+	app.Handle(http.MethodPost, "/api/v1/projects/{pid}/fields", pm.Require(permissions.ManageFields, cf.Create))
+	app.Handle(http.MethodPatch, "/api/v1/projects/{pid}/fields/{fid}", pm.Require(permissions.ManageFields, cf.Update))
+	app.Handle(http.MethodDelete, "/api/v1/projects/{pid}/fields/{fid}", pm.Require(permissions.ManageFields, cf.Delete))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/stats", pm.Require(permissions.ViewProject, sts.Retrieve))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/metrics", pm.Require(permissions.ViewProject, mt.Retrieve))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/activity", pm.Require(permissions.ViewProject, ac.Project))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/views", pm.Require(permissions.ViewProject, vw.List))
+	app.Handle(http.MethodPost, "/api/v1/projects/{pid}/views", pm.Require(permissions.ViewProject, vw.Create))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/views/{vid}", pm.Require(permissions.ViewProject, vw.Retrieve))
+	app.Handle(http.MethodPatch, "/api/v1/projects/{pid}/views/{vid}", pm.Require(permissions.ViewProject, vw.Update))
+	app.Handle(http.MethodDelete, "/api/v1/projects/{pid}/views/{vid}", pm.Require(permissions.ViewProject, vw.Delete))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/recurrences", pm.Require(permissions.ViewProject, rc.List))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/recurrences/{rid}", pm.Require(permissions.ViewProject, rc.Retrieve))
+	app.Handle(http.MethodPatch, "/api/v1/projects/{pid}/recurrences/{rid}", pm.Require(permissions.CreateTask, rc.Update))
+	app.Handle(http.MethodDelete, "/api/v1/projects/{pid}/recurrences/{rid}", pm.Require(permissions.CreateTask, rc.Delete))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/columns", pm.Require(permissions.ViewProject, c.List))
+	app.Handle(http.MethodGet, "/api/v1/projects/{pid}/tasks", pm.Require(permissions.ViewProject, t.List))
+	app.Handle(http.MethodPost, "/api/v1/projects/{pid}/columns/{cid}/tasks", pm.Require(permissions.CreateTask, t.Create))
+	app.Handle(http.MethodPatch, "/api/v1/projects/tasks/{tid}", pm.Require(permissions.UpdateTask, t.Update))
+	app.Handle(http.MethodPost, "/api/v1/projects/tasks/{tid}/comments", pm.Require(permissions.Comment, t.Comment))
+	app.Handle(http.MethodPatch, "/api/v1/projects/tasks/{tid}/move", pm.Require(permissions.MoveTask, t.Move))
+	app.Handle(http.MethodPost, "/api/v1/projects/tasks/{tid}/recurrence", pm.Require(permissions.CreateTask, rc.Create))
+	app.Handle(http.MethodPost, "/api/v1/projects/tasks/{tid}/watch", pm.Require(permissions.ViewProject, wt.WatchTask))
+	app.Handle(http.MethodDelete, "/api/v1/projects/tasks/{tid}/watch", pm.Require(permissions.ViewProject, wt.UnwatchTask))
+	app.Handle(http.MethodGet, "/api/v1/projects/tasks/{tid}/approvals", pm.Require(permissions.ViewProject, ap.List))
+	app.Handle(http.MethodPost, "/api/v1/projects/tasks/{tid}/approvals", pm.Require(permissions.UpdateTask, ap.Request))
+	app.Handle(http.MethodPost, "/api/v1/projects/tasks/{tid}/approvals/withdraw", pm.Require(permissions.UpdateTask, ap.Withdraw))
+	app.Handle(http.MethodPost, "/api/v1/projects/tasks/{tid}/approve", pm.Require(permissions.ViewProject, ap.Approve))
+	app.Handle(http.MethodPost, "/api/v1/projects/tasks/{tid}/reject", pm.Require(permissions.ViewProject, ap.Reject))
+	app.Handle(http.MethodGet, "/api/v1/projects/tasks/{tid}/checklist", pm.Require(permissions.ViewProject, cl.List))
+	app.Handle(http.MethodPost, "/api/v1/projects/tasks/{tid}/checklist", pm.Require(permissions.UpdateTask, cl.Create))
+	app.Handle(http.MethodPut, "/api/v1/projects/tasks/{tid}/checklist/order", pm.Require(permissions.UpdateTask, cl.Reorder))
+	app.Handle(http.MethodPatch, "/api/v1/projects/tasks/{tid}/checklist/{iid}", pm.Require(permissions.UpdateTask, cl.Update))
+	app.Handle(http.MethodPost, "/api/v1/projects/tasks/{tid}/checklist/{iid}/toggle", pm.Require(permissions.UpdateTask, cl.Toggle))
+	app.Handle(http.MethodDelete, "/api/v1/projects/tasks/{tid}/checklist/{iid}", pm.Require(permissions.UpdateTask, cl.Delete))
+	app.Handle(http.MethodGet, "/api/v1/projects/tasks/{tid}/links", pm.Require(permissions.ViewProject, gi.Links))
+	app.Handle(http.MethodGet, "/api/v1/projects/tasks/{tid}/transitions", pm.Require(permissions.ViewProject, mt.History))
+	app.Handle(http.MethodGet, "/api/v1/projects/tasks/{tid}/time", pm.Require(permissions.ViewProject, ti.ListByTask))
+	app.Handle(http.MethodPost, "/api/v1/projects/tasks/{tid}/time", pm.Require(permissions.UpdateTask, ti.Create))
+	app.Handle(http.MethodDelete, "/api/v1/projects/columns/{cid}/tasks/{tid}", pm.Require(permissions.DeleteTask, t.Delete))
 
 	return Cors(origins).Handler(app)
 }
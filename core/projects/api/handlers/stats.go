@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/stats"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+type Stats struct {
+	repo  *database.Repository
+	log   *log.Logger
+	auth0 *auth0.Auth0
+}
+
+// Retrieve returns the statistics of a project. The trend covers the last 30 days unless
+// from and to are given, by day unless interval is week.
+func (s *Stats) Retrieve(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	now := time.Now()
+	from, to, err := timeRange(r, now.AddDate(0, 0, -30), now)
+	if err != nil {
+		return err
+	}
+
+	interval := r.URL.Query().Get("interval")
+	if interval == "" {
+		interval = stats.Day
+	}
+
+	st, err := stats.Compute(r.Context(), s.repo, pid, from, to, now, interval, isClient(r.Context(), s.auth0))
+	if err != nil {
+		switch err {
+		case stats.ErrInvalidID, stats.ErrInvalidInterval, stats.ErrInvalidRange:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "computing statistics of project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, st, http.StatusOK)
+}
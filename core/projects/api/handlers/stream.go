@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/permissions"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/stream"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+// heartbeat is how often an idle stream is kept alive and the caller's access rechecked.
+const heartbeat = 20 * time.Second
+
+type Stream struct {
+	repo  *database.Repository
+	log   *log.Logger
+	auth0 *auth0.Auth0
+	hub   *stream.Hub
+}
+
+// Subscribe streams the board events of a project as Server-Sent Events. Clients resume
+// after a reconnect with the Last-Event-ID header or the lastEventId query parameter.
+func (s *Stream) Subscribe(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	uid := s.auth0.UserByID(r.Context())
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("lastEventId")
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.New("streaming is not supported by the connection")
+	}
+
+	v, ok := r.Context().Value(web.KeyValues).(*web.Values)
+	if ok {
+		v.StatusCode = http.StatusOK
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+
+	client := isClient(r.Context(), s.auth0)
+
+	replay, resumed, events, cancel := s.hub.Subscribe(pid, lastID)
+	defer cancel()
+
+	if !resumed {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, e := range replay {
+		if client {
+			if e, ok = clientEvent(e); !ok {
+				continue
+			}
+		}
+		if err := stream.Encode(w, e); err != nil {
+			return nil
+		}
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+		case e, open := <-events:
+			if !open {
+				return nil
+			}
+			if client {
+				if e, ok = clientEvent(e); !ok {
+					continue
+				}
+			}
+			if err := stream.Encode(w, e); err != nil {
+				return nil
+			}
+		case <-ticker.C:
+			if _, err := permissions.Authorize(r.Context(), s.repo, pid, uid, permissions.ViewProject); err != nil {
+				fmt.Fprint(w, "event: revoked\ndata: {}\n\n")
+				flusher.Flush()
+				return nil
+			}
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return nil
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// WithWriteTimeout bounds the time taken to respond to every request but event streams,
+// which stay open for as long as the client listens. It stands in for the server's write
+// timeout, which would cut streams short.
+func WithWriteTimeout(h http.Handler, d time.Duration) http.Handler {
+	if d <= 0 {
+		return h
+	}
+	bounded := http.TimeoutHandler(h, d, "")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/stream") {
+			h.ServeHTTP(w, r)
+			return
+		}
+		bounded.ServeHTTP(w, r)
+	})
+}
@@ -1,38 +1,101 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/pkg/errors"
 
+	"github.com/devpies/devpie-client-core/projects/api/publishers"
+	"github.com/devpies/devpie-client-core/projects/domain/approvals"
+	"github.com/devpies/devpie-client-core/projects/domain/checklists"
 	"github.com/devpies/devpie-client-core/projects/domain/columns"
+	"github.com/devpies/devpie-client-core/projects/domain/customfields"
+	"github.com/devpies/devpie-client-core/projects/domain/integrations"
+	"github.com/devpies/devpie-client-core/projects/domain/milestones"
+	"github.com/devpies/devpie-client-core/projects/domain/roles"
 	"github.com/devpies/devpie-client-core/projects/domain/tasks"
+	"github.com/devpies/devpie-client-core/projects/domain/templates"
+	"github.com/devpies/devpie-client-core/projects/domain/timeentries"
+	"github.com/devpies/devpie-client-core/projects/domain/transitions"
+	"github.com/devpies/devpie-client-core/projects/domain/views"
+	"github.com/devpies/devpie-client-core/projects/domain/watchers"
 	"github.com/devpies/devpie-client-core/projects/platform/auth0"
 	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/markdown"
 	"github.com/devpies/devpie-client-core/projects/platform/web"
+	"github.com/devpies/devpie-client-events/go/events"
 )
 
 type Tasks struct {
-	repo  *database.Repository
-	log   *log.Logger
-	auth0 *auth0.Auth0
+	repo    *database.Repository
+	log     *log.Logger
+	auth0   *auth0.Auth0
+	nats    *events.Client
+	publish publishers.Publisher
+	appURL  string
 }
 
 func (t *Tasks) List(w http.ResponseWriter, r *http.Request) error {
 	pid := chi.URLParam(r, "pid")
+	uid := t.auth0.UserByID(r.Context())
+
+	q := r.URL.Query()
+	f := taskFilter(q)
+
+	// a saved view stands in for the parameters that aren't given
+	if vid := q.Get("view"); vid != "" {
+		v, err := views.Retrieve(r.Context(), t.repo, pid, vid, uid)
+		if err != nil {
+			switch err {
+			case views.ErrNotFound, views.ErrInvalidID:
+				return web.NewRequestError(err, http.StatusBadRequest)
+			default:
+				return errors.Wrapf(err, "looking for view %q", vid)
+			}
+		}
+		f = mergeFilters(v.Filter, f)
+	}
+
+	client := isClient(r.Context(), t.auth0)
+
+	// clients don't see estimates, so they don't get to order by them either
+	if client && strings.TrimPrefix(f.Sort, "-") == "points" {
+		f.Sort = ""
+	}
 
-	list, err := tasks.List(r.Context(), t.repo, pid)
+	list, err := tasks.Find(r.Context(), t.repo, pid, f)
 	if err != nil {
-		return err
+		switch err {
+		case tasks.ErrInvalidSort:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "listing tasks of project %q", pid)
+		}
 	}
 
 	if list == nil {
 		list = []tasks.Task{}
 	}
 
+	l, err := t.linker(r.Context(), pid)
+	if err != nil {
+		return err
+	}
+	for i := range list {
+		list[i].Render(l)
+	}
+
+	if client {
+		list = clientTasks(list)
+	}
+
 	return web.Respond(r.Context(), w, list, http.StatusOK)
 }
 
@@ -40,16 +103,19 @@ func (t *Tasks) Retrieve(w http.ResponseWriter, r *http.Request) error {
 
 	tid := chi.URLParam(r, "tid")
 
-	ts, err := tasks.Retrieve(r.Context(), t.repo, tid)
+	ts, err := visibleTask(r.Context(), t.repo, t.auth0, tid)
 	if err != nil {
-		switch err {
-		case tasks.ErrNotFound:
-			return web.NewRequestError(err, http.StatusNotFound)
-		case tasks.ErrInvalidID:
-			return web.NewRequestError(err, http.StatusBadRequest)
-		default:
-			return errors.Wrapf(err, "looking for tasks %q", tid)
-		}
+		return err
+	}
+
+	l, err := t.linker(r.Context(), ts.ProjectID)
+	if err != nil {
+		return err
+	}
+	ts.Render(l)
+
+	if isClient(r.Context(), t.auth0) {
+		ts = ts.ClientView()
 	}
 
 	return web.Respond(r.Context(), w, ts, http.StatusOK)
@@ -66,11 +132,36 @@ func (t *Tasks) Create(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
+	var checklist []string
+	if nt.TemplateID != "" {
+		tpl, err := templates.Retrieve(r.Context(), t.repo, pid, nt.TemplateID)
+		if err != nil {
+			switch err {
+			case templates.ErrNotFound, templates.ErrInvalidID:
+				return web.NewRequestError(err, http.StatusBadRequest)
+			default:
+				return errors.Wrapf(err, "looking for task template %q", nt.TemplateID)
+			}
+		}
+		if nt, err = tpl.Apply(nt, time.Now()); err != nil {
+			return web.NewRequestError(err, http.StatusBadRequest)
+		}
+		checklist = tpl.Checklist
+	}
+
 	ts, err := tasks.Create(r.Context(), t.repo, nt, pid, uid, time.Now())
 	if err != nil {
 		return err
 	}
 
+	for _, text := range checklist {
+		ni := checklists.NewItem{Text: text}
+		if _, err := checklists.Create(r.Context(), t.repo, ni, pid, ts.ID, uid, time.Now()); err != nil {
+			return errors.Wrapf(err, "adding template checklist to task %q", ts.ID)
+		}
+	}
+	ts.Checklist.Total = len(checklist)
+
 	c, err := columns.Retrieve(r.Context(), t.repo, cid)
 	if err != nil {
 		return err
@@ -86,17 +177,79 @@ func (t *Tasks) Create(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
+	if _, err := transitions.Enter(r.Context(), t.repo, ts.ID, c, time.Now()); err != nil {
+		return err
+	}
+
+	if t.nats != nil {
+		if err := t.publish.TaskCreated(t.nats, ts, cid, uid); err != nil {
+			return err
+		}
+
+		ids, err := taskRecipients(r.Context(), t.repo, ts, uid)
+		if err != nil {
+			return err
+		}
+		if err := notifyWatchers(t.nats, t.publish, ids, taskWatchEvent(ts, "created"), uid); err != nil {
+			return err
+		}
+	}
+
+	l, err := t.linker(r.Context(), pid)
+	if err != nil {
+		return err
+	}
+	ts.Render(l)
+
 	return web.Respond(r.Context(), w, ts, http.StatusCreated)
 }
 
 func (t *Tasks) Update(w http.ResponseWriter, r *http.Request) error {
 	tid := chi.URLParam(r, "tid")
+	uid := t.auth0.UserByID(r.Context())
 
 	var ut tasks.UpdateTask
 	if err := web.Decode(r, &ut); err != nil {
 		return errors.Wrap(err, "decoding task update")
 	}
 
+	prev, err := visibleTask(r.Context(), t.repo, t.auth0, tid)
+	if err != nil {
+		return err
+	}
+
+	if isClient(r.Context(), t.auth0) && (ut.Internal != nil || ut.InternalComments != nil) {
+		return web.NewRequestError(tasks.ErrTeamOnly, http.StatusForbidden)
+	}
+
+	if ut.MilestoneID != nil && *ut.MilestoneID != "" {
+		if _, err := milestones.Retrieve(r.Context(), t.repo, prev.ProjectID, *ut.MilestoneID, false); err != nil {
+			switch err {
+			case milestones.ErrNotFound, milestones.ErrInvalidID:
+				return web.NewRequestError(err, http.StatusBadRequest)
+			default:
+				return errors.Wrapf(err, "looking for milestone %q", *ut.MilestoneID)
+			}
+		}
+	}
+
+	if len(ut.Fields) > 0 {
+		fs, err := customfields.List(r.Context(), t.repo, prev.ProjectID)
+		if err != nil {
+			return err
+		}
+		values, err := customfields.Validate(fs, ut.Fields)
+		if err != nil {
+			return web.NewRequestError(err, http.StatusBadRequest)
+		}
+		for _, id := range customfields.Users(fs, values) {
+			if err := checkMember(r.Context(), t.repo, prev.ProjectID, id); err != nil {
+				return err
+			}
+		}
+		ut.Fields = values
+	}
+
 	update, err := tasks.Update(r.Context(), t.repo, tid, ut, time.Now())
 	if err != nil {
 		switch err {
@@ -104,26 +257,171 @@ func (t *Tasks) Update(w http.ResponseWriter, r *http.Request) error {
 			return web.NewRequestError(err, http.StatusNotFound)
 		case tasks.ErrInvalidID:
 			return web.NewRequestError(err, http.StatusBadRequest)
+		case tasks.ErrApproval, tasks.ErrAwaiting:
+			return web.NewRequestError(err, http.StatusConflict)
 		default:
 			return errors.Wrapf(err, "updating task %v", ut)
 		}
 	}
 
+	// assignees and commenters follow the task from now on
+	if update.AssignedTo != "" && update.AssignedTo != prev.AssignedTo {
+		if _, err := watchers.Create(r.Context(), t.repo, update.ProjectID, update.ID, update.AssignedTo, time.Now()); err != nil {
+			return err
+		}
+	}
+	if len(update.Comments) > len(prev.Comments) || len(update.InternalComments) > len(prev.InternalComments) {
+		if _, err := watchers.Create(r.Context(), t.repo, update.ProjectID, update.ID, uid, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if t.nats != nil {
+		if update.Internal && !prev.Internal {
+			cid, err := taskColumn(r.Context(), t.repo, update)
+			if err != nil {
+				return err
+			}
+			if err := t.publish.TaskHidden(t.nats, update, cid, uid); err != nil {
+				return err
+			}
+		} else if err := t.publish.TaskUpdated(t.nats, update, uid); err != nil {
+			return err
+		}
+
+		if update.AssignedTo != "" && update.AssignedTo != prev.AssignedTo {
+			if err := t.publish.TaskAssigned(t.nats, update, uid); err != nil {
+				return err
+			}
+		}
+
+		if len(update.Comments) > len(prev.Comments) {
+			if err := t.publishCommented(r.Context(), update, uid); err != nil {
+				return err
+			}
+		}
+
+		ids, err := taskRecipients(r.Context(), t.repo, update, uid)
+		if err != nil {
+			return err
+		}
+		if err := notifyWatchers(t.nats, t.publish, ids, taskWatchEvent(update, "updated"), uid); err != nil {
+			return err
+		}
+	}
+
+	l, err := t.linker(r.Context(), update.ProjectID)
+	if err != nil {
+		return err
+	}
+	update.Render(l)
+
+	if isClient(r.Context(), t.auth0) {
+		update = update.ClientView()
+	}
+
 	return web.Respond(r.Context(), w, update, http.StatusOK)
 }
 
+// Comment adds a comment to a task. Clients can't add internal comments.
+func (t *Tasks) Comment(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+	uid := t.auth0.UserByID(r.Context())
+	client := isClient(r.Context(), t.auth0)
+
+	var nc tasks.NewComment
+	if err := web.Decode(r, &nc); err != nil {
+		return err
+	}
+
+	if _, err := visibleTask(r.Context(), t.repo, t.auth0, tid); err != nil {
+		return err
+	}
+	if client && nc.Internal {
+		return web.NewRequestError(tasks.ErrTeamOnly, http.StatusForbidden)
+	}
+
+	ts, err := tasks.AddComment(r.Context(), t.repo, tid, nc, time.Now())
+	if err != nil {
+		return errors.Wrapf(err, "commenting on task %q", tid)
+	}
+
+	if _, err := watchers.Create(r.Context(), t.repo, ts.ProjectID, ts.ID, uid, time.Now()); err != nil {
+		return err
+	}
+
+	if t.nats != nil {
+		if err := t.publish.TaskUpdated(t.nats, ts, uid); err != nil {
+			return err
+		}
+		if !nc.Internal {
+			if err := t.publishCommented(r.Context(), ts, uid); err != nil {
+				return err
+			}
+		}
+
+		ids, err := taskRecipients(r.Context(), t.repo, ts, uid)
+		if err != nil {
+			return err
+		}
+		// an internal comment on a task clients see is still only news to the team
+		if nc.Internal {
+			if ids, err = roles.Team(r.Context(), t.repo, ids); err != nil {
+				return err
+			}
+		}
+		if err := notifyWatchers(t.nats, t.publish, ids, taskWatchEvent(ts, "commented"), uid); err != nil {
+			return err
+		}
+	}
+
+	l, err := t.linker(r.Context(), ts.ProjectID)
+	if err != nil {
+		return err
+	}
+	ts.Render(l)
+
+	if client {
+		ts = ts.ClientView()
+	}
+
+	return web.Respond(r.Context(), w, ts, http.StatusCreated)
+}
+
 func (t *Tasks) Delete(w http.ResponseWriter, r *http.Request) error {
 	cid := chi.URLParam(r, "cid")
 	tid := chi.URLParam(r, "tid")
+	uid := t.auth0.UserByID(r.Context())
 
 	c, err := columns.Retrieve(r.Context(), t.repo, cid)
 	if err != nil {
 		return err
 	}
 
+	ts, err := visibleTask(r.Context(), t.repo, t.auth0, tid)
+	if err != nil {
+		return err
+	}
+
+	// watchers are looked up before the task and its subscriptions are gone
+	ids, err := taskRecipients(r.Context(), t.repo, ts, uid)
+	if err != nil {
+		return err
+	}
+
 	i := SliceIndex(len(c.TaskIDS), func(i int) bool { return c.TaskIDS[i] == tid })
 
 	if i >= 0 {
+		// billed time keeps the task, so it goes before anything else is removed
+		if err := timeentries.DeleteByTask(r.Context(), t.repo, tid); err != nil {
+			switch err {
+			case timeentries.ErrLocked:
+				return web.NewRequestError(err, http.StatusConflict)
+			default:
+				return err
+			}
+		}
+
 		newTaskIds := append(c.TaskIDS[:i], c.TaskIDS[i+1:]...)
 		uc := columns.UpdateColumn{TaskIDS: &newTaskIds}
 
@@ -131,6 +429,22 @@ func (t *Tasks) Delete(w http.ResponseWriter, r *http.Request) error {
 			return err
 		}
 
+		if err := watchers.DeleteByTask(r.Context(), t.repo, tid); err != nil {
+			return err
+		}
+		if err := approvals.DeleteByTask(r.Context(), t.repo, tid); err != nil {
+			return err
+		}
+		if err := integrations.DeleteByTask(r.Context(), t.repo, tid); err != nil {
+			return err
+		}
+		if err := checklists.DeleteByTask(r.Context(), t.repo, tid); err != nil {
+			return err
+		}
+		if err := transitions.DeleteByTask(r.Context(), t.repo, tid); err != nil {
+			return err
+		}
+
 		if err := tasks.Delete(r.Context(), t.repo, tid); err != nil {
 			switch err {
 			case tasks.ErrInvalidID:
@@ -139,6 +453,15 @@ func (t *Tasks) Delete(w http.ResponseWriter, r *http.Request) error {
 				return errors.Wrapf(err, "deleting task %q", tid)
 			}
 		}
+
+		if t.nats != nil {
+			if err := t.publish.TaskDeleted(t.nats, ts, cid, uid); err != nil {
+				return err
+			}
+			if err := notifyWatchers(t.nats, t.publish, ids, taskWatchEvent(ts, "deleted"), uid); err != nil {
+				return err
+			}
+		}
 	}
 
 	return web.Respond(r.Context(), w, nil, http.StatusOK)
@@ -146,6 +469,7 @@ func (t *Tasks) Delete(w http.ResponseWriter, r *http.Request) error {
 
 func (t *Tasks) Move(w http.ResponseWriter, r *http.Request) error {
 	tid := chi.URLParam(r, "tid")
+	uid := t.auth0.UserByID(r.Context())
 
 	var mt tasks.MoveTask
 	if err := web.Decode(r, &mt); err != nil {
@@ -162,16 +486,42 @@ func (t *Tasks) Move(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
-	i := SliceIndex(len(cF.TaskIDS), func(i int) bool { return cF.TaskIDS[i] == tid })
+	ts, err := visibleTask(r.Context(), t.repo, t.auth0, tid)
+	if err != nil {
+		return err
+	}
+
+	// both columns must belong to the task's project
+	if cF.ProjectID != ts.ProjectID || cT.ProjectID != ts.ProjectID {
+		return web.NewRequestError(columns.ErrNotFound, http.StatusNotFound)
+	}
+
+	if cT.IsDone() && !cF.IsDone() {
+		if err := approvals.CanComplete(ts); err != nil {
+			return web.NewRequestError(err, http.StatusConflict)
+		}
+	}
+
+	if err := t.move(r.Context(), ts, cF, cT, uid); err != nil {
+		return err
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+// move takes a task from one column of its board to another. Nothing happens when the task
+// isn't in the column it's taken from.
+func (t *Tasks) move(ctx context.Context, ts tasks.Task, cF, cT columns.Column, uid string) error {
+	i := SliceIndex(len(cF.TaskIDS), func(i int) bool { return cF.TaskIDS[i] == ts.ID })
 
 	if i >= 0 {
 		newFromTaskIds := append(cF.TaskIDS[:i], cF.TaskIDS[i+1:]...)
 		foc := columns.UpdateColumn{TaskIDS: &newFromTaskIds}
 
-		newToTaskIds := append(cT.TaskIDS, tid)
+		newToTaskIds := append(cT.TaskIDS, ts.ID)
 		toc := columns.UpdateColumn{TaskIDS: &newToTaskIds}
 
-		err = columns.Update(r.Context(), t.repo, mt.From, foc, time.Now())
+		err := columns.Update(ctx, t.repo, cF.ID, foc, time.Now())
 		if err != nil {
 			switch err {
 			case tasks.ErrNotFound:
@@ -179,11 +529,11 @@ func (t *Tasks) Move(w http.ResponseWriter, r *http.Request) error {
 			case tasks.ErrInvalidID:
 				return web.NewRequestError(err, http.StatusBadRequest)
 			default:
-				return errors.Wrapf(err, "updating column taskIds from:%q, to:%q", mt.From, mt.To)
+				return errors.Wrapf(err, "updating column taskIds from:%q, to:%q", cF.ID, cT.ID)
 			}
 		}
 
-		err = columns.Update(r.Context(), t.repo, mt.To, toc, time.Now())
+		err = columns.Update(ctx, t.repo, cT.ID, toc, time.Now())
 		if err != nil {
 			switch err {
 			case tasks.ErrNotFound:
@@ -191,12 +541,83 @@ func (t *Tasks) Move(w http.ResponseWriter, r *http.Request) error {
 			case tasks.ErrInvalidID:
 				return web.NewRequestError(err, http.StatusBadRequest)
 			default:
-				return errors.Wrapf(err, "updating column taskIds from:%q, to:%q", mt.From, mt.To)
+				return errors.Wrapf(err, "updating column taskIds from:%q, to:%q", cF.ID, cT.ID)
+			}
+		}
+
+		if _, err := transitions.Enter(ctx, t.repo, ts.ID, cT, time.Now()); err != nil {
+			return err
+		}
+
+		if cT.IsDone() != cF.IsDone() {
+			var at *time.Time
+			if cT.IsDone() {
+				now := time.Now()
+				at = &now
+			}
+			if err := tasks.Complete(ctx, t.repo, ts.ID, at); err != nil {
+				return err
+			}
+		}
+
+		if t.nats != nil {
+			if err := t.publish.TaskMoved(t.nats, ts, cF.ID, cT, uid); err != nil {
+				return err
+			}
+
+			ids, err := taskRecipients(ctx, t.repo, ts, uid)
+			if err != nil {
+				return err
+			}
+			if err := notifyWatchers(t.nats, t.publish, ids, taskWatchEvent(ts, "moved"), uid); err != nil {
+				return err
 			}
 		}
 	}
 
-	return web.Respond(r.Context(), w, nil, http.StatusOK)
+	return nil
+}
+
+// publishCommented publishes the TaskCommented event of a task with its comments rendered,
+// so digests show them as the web app does. Clients read the event too, so only the keys
+// they can see are linked.
+func (t *Tasks) publishCommented(ctx context.Context, ts tasks.Task, uid string) error {
+	l, err := t.linkerFor(ctx, ts.ProjectID, true)
+	if err != nil {
+		return err
+	}
+	ts.Render(l)
+
+	return t.publish.TaskCommented(t.nats, ts, uid)
+}
+
+// linker links the task keys of a project and @mentions in task content to the web app.
+func (t *Tasks) linker(ctx context.Context, pid string) (markdown.Linker, error) {
+	return t.linkerFor(ctx, pid, isClient(ctx, t.auth0))
+}
+
+// linkerFor is linker for a reader who may or may not be a client.
+func (t *Tasks) linkerFor(ctx context.Context, pid string, client bool) (markdown.Linker, error) {
+	keys, err := tasks.Keys(ctx, t.repo, pid, client)
+	if err != nil {
+		return markdown.Linker{}, err
+	}
+
+	members, err := roles.Members(ctx, t.repo, pid)
+	if err != nil {
+		return markdown.Linker{}, err
+	}
+
+	return markdown.Linker{
+		Task: func(key string) (string, bool) {
+			k, ok := keys[key]
+			return fmt.Sprintf("%s/projects/%s/tasks/%s", t.appURL, pid, url.PathEscape(k)), ok
+		},
+		Mention: func(handle string) (string, bool) {
+			_, ok := roles.Resolve(handle, members)
+			return fmt.Sprintf("%s/people/%s", t.appURL, url.PathEscape(handle)), ok
+		},
+	}, nil
 }
 
 func SliceIndex(limit int, predicate func(i int) bool) int {
@@ -207,3 +628,52 @@ func SliceIndex(limit int, predicate func(i int) bool) int {
 	}
 	return -1
 }
+
+// taskFilter reads the filters of the task list: assignee, label (repeatable), milestone,
+// field.<field id> for custom fields and sort.
+func taskFilter(q url.Values) tasks.Filter {
+	f := tasks.Filter{
+		AssignedTo:  q.Get("assignee"),
+		Labels:      q["label"],
+		MilestoneID: q.Get("milestone"),
+		Sort:        q.Get("sort"),
+	}
+
+	for k, v := range q {
+		if id := strings.TrimPrefix(k, "field."); id != k && len(v) > 0 {
+			if f.Fields == nil {
+				f.Fields = make(map[string]string)
+			}
+			f.Fields[id] = v[0]
+		}
+	}
+
+	return f
+}
+
+// mergeFilters returns a saved filter with the parts set in another filter replaced.
+func mergeFilters(saved, f tasks.Filter) tasks.Filter {
+	if f.AssignedTo != "" {
+		saved.AssignedTo = f.AssignedTo
+	}
+	if len(f.Labels) > 0 {
+		saved.Labels = f.Labels
+	}
+	if f.MilestoneID != "" {
+		saved.MilestoneID = f.MilestoneID
+	}
+	if f.Sort != "" {
+		saved.Sort = f.Sort
+	}
+
+	fields := make(map[string]string, len(saved.Fields)+len(f.Fields))
+	for id, v := range saved.Fields {
+		fields[id] = v
+	}
+	for id, v := range f.Fields {
+		fields[id] = v
+	}
+	saved.Fields = fields
+
+	return saved
+}
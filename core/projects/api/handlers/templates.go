@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/templates"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+type Templates struct {
+	repo  *database.Repository
+	log   *log.Logger
+	auth0 *auth0.Auth0
+}
+
+func (tp *Templates) List(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	list, err := templates.List(r.Context(), tp.repo, pid)
+	if err != nil {
+		switch err {
+		case templates.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "listing task templates for project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (tp *Templates) Retrieve(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	tid := chi.URLParam(r, "tpid")
+
+	t, err := templates.Retrieve(r.Context(), tp.repo, pid, tid)
+	if err != nil {
+		switch err {
+		case templates.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case templates.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "looking for task template %q", tid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, t, http.StatusOK)
+}
+
+func (tp *Templates) Create(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	uid := tp.auth0.UserByID(r.Context())
+
+	var nt templates.NewTemplate
+	if err := web.Decode(r, &nt); err != nil {
+		return err
+	}
+
+	t, err := templates.Create(r.Context(), tp.repo, nt, pid, uid, time.Now())
+	if err != nil {
+		switch err {
+		case templates.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case templates.ErrNameTaken:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return errors.Wrapf(err, "creating task template for project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, t, http.StatusCreated)
+}
+
+func (tp *Templates) Update(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	tid := chi.URLParam(r, "tpid")
+
+	var ut templates.UpdateTemplate
+	if err := web.Decode(r, &ut); err != nil {
+		return err
+	}
+
+	t, err := templates.Update(r.Context(), tp.repo, pid, tid, ut, time.Now())
+	if err != nil {
+		switch err {
+		case templates.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case templates.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case templates.ErrNameTaken:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return errors.Wrapf(err, "updating task template %q", tid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, t, http.StatusOK)
+}
+
+func (tp *Templates) Delete(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	tid := chi.URLParam(r, "tpid")
+
+	if err := templates.Delete(r.Context(), tp.repo, pid, tid); err != nil {
+		switch err {
+		case templates.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case templates.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "deleting task template %q", tid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/timeentries"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+type Time struct {
+	repo  *database.Repository
+	log   *log.Logger
+	auth0 *auth0.Auth0
+}
+
+func (ti *Time) List(w http.ResponseWriter, r *http.Request) error {
+	uid := ti.auth0.UserByID(r.Context())
+
+	from, to, err := timeRange(r, time.Now().AddDate(0, 0, -30), time.Now().AddDate(0, 0, 1))
+	if err != nil {
+		return err
+	}
+
+	list, err := timeentries.List(r.Context(), ti.repo, uid, from, to)
+	if err != nil {
+		return errors.Wrapf(err, "listing time entries of %q", uid)
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (ti *Time) ListByTask(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+
+	if _, err := visibleTask(r.Context(), ti.repo, ti.auth0, tid); err != nil {
+		return err
+	}
+
+	list, err := timeentries.ListByTask(r.Context(), ti.repo, tid)
+	if err != nil {
+		switch err {
+		case timeentries.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "listing time entries of task %q", tid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (ti *Time) Create(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+	uid := ti.auth0.UserByID(r.Context())
+
+	var ne timeentries.NewEntry
+	if err := web.Decode(r, &ne); err != nil {
+		return err
+	}
+
+	ts, err := visibleTask(r.Context(), ti.repo, ti.auth0, tid)
+	if err != nil {
+		return err
+	}
+
+	e, err := timeentries.Create(r.Context(), ti.repo, ne, ts.ProjectID, ts.ID, uid, time.Now())
+	if err != nil {
+		switch err {
+		case timeentries.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case timeentries.ErrTimerRunning:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return errors.Wrapf(err, "recording time on task %q", tid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, e, http.StatusCreated)
+}
+
+func (ti *Time) Update(w http.ResponseWriter, r *http.Request) error {
+	eid := chi.URLParam(r, "eid")
+	uid := ti.auth0.UserByID(r.Context())
+
+	var ue timeentries.UpdateEntry
+	if err := web.Decode(r, &ue); err != nil {
+		return err
+	}
+
+	e, err := timeentries.Update(r.Context(), ti.repo, eid, uid, ue, time.Now())
+	if err != nil {
+		switch err {
+		case timeentries.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case timeentries.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case timeentries.ErrTimerRunning, timeentries.ErrLocked:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return errors.Wrapf(err, "updating time entry %q", eid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, e, http.StatusOK)
+}
+
+func (ti *Time) Stop(w http.ResponseWriter, r *http.Request) error {
+	eid := chi.URLParam(r, "eid")
+	uid := ti.auth0.UserByID(r.Context())
+
+	e, err := timeentries.Stop(r.Context(), ti.repo, eid, uid, time.Now())
+	if err != nil {
+		switch err {
+		case timeentries.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case timeentries.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case timeentries.ErrNotRunning:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return errors.Wrapf(err, "stopping time entry %q", eid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, e, http.StatusOK)
+}
+
+func (ti *Time) Delete(w http.ResponseWriter, r *http.Request) error {
+	eid := chi.URLParam(r, "eid")
+	uid := ti.auth0.UserByID(r.Context())
+
+	if err := timeentries.Delete(r.Context(), ti.repo, eid, uid); err != nil {
+		switch err {
+		case timeentries.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case timeentries.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case timeentries.ErrLocked:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return errors.Wrapf(err, "deleting time entry %q", eid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+func (ti *Time) Totals(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	group := r.URL.Query().Get("by")
+	if group == "" {
+		group = "task"
+	}
+
+	from, to, err := timeRange(r, time.Unix(0, 0), time.Now().AddDate(0, 0, 1))
+	if err != nil {
+		return err
+	}
+
+	list, err := timeentries.Totals(r.Context(), ti.repo, pid, group, from, to, isClient(r.Context(), ti.auth0))
+	if err != nil {
+		switch err {
+		case timeentries.ErrInvalidID, timeentries.ErrInvalidGroup:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "reporting time of project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+// timeRange reads the from and to query parameters as dates or RFC 3339 timestamps.
+func timeRange(r *http.Request, from, to time.Time) (time.Time, time.Time, error) {
+	parse := func(param string, def time.Time) (time.Time, error) {
+		v := r.URL.Query().Get(param)
+		if v == "" {
+			return def, nil
+		}
+		if t, err := time.Parse("2006-01-02", v); err == nil {
+			return t, nil
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return t, web.NewRequestError(errors.Errorf("%s must be a date or an RFC 3339 timestamp", param), http.StatusBadRequest)
+		}
+		return t, nil
+	}
+
+	f, err := parse("from", from)
+	if err != nil {
+		return f, to, err
+	}
+	t, err := parse("to", to)
+
+	return f, t, err
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/tasks"
+	"github.com/devpies/devpie-client-core/projects/domain/views"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+type Views struct {
+	repo  *database.Repository
+	log   *log.Logger
+	auth0 *auth0.Auth0
+}
+
+func (v *Views) List(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	uid := v.auth0.UserByID(r.Context())
+
+	list, err := views.List(r.Context(), v.repo, pid, uid)
+	if err != nil {
+		switch err {
+		case views.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "listing views for project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (v *Views) Retrieve(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	vid := chi.URLParam(r, "vid")
+	uid := v.auth0.UserByID(r.Context())
+
+	view, err := views.Retrieve(r.Context(), v.repo, pid, vid, uid)
+	if err != nil {
+		switch err {
+		case views.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case views.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "looking for view %q", vid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, view, http.StatusOK)
+}
+
+func (v *Views) Create(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	uid := v.auth0.UserByID(r.Context())
+
+	var nv views.NewView
+	if err := web.Decode(r, &nv); err != nil {
+		return err
+	}
+
+	view, err := views.Create(r.Context(), v.repo, nv, pid, uid, time.Now())
+	if err != nil {
+		switch err {
+		case views.ErrInvalidID, views.ErrInvalidGroup, tasks.ErrInvalidSort:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "saving view for project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, view, http.StatusCreated)
+}
+
+func (v *Views) Update(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	vid := chi.URLParam(r, "vid")
+	uid := v.auth0.UserByID(r.Context())
+
+	var uv views.UpdateView
+	if err := web.Decode(r, &uv); err != nil {
+		return err
+	}
+
+	view, err := views.Update(r.Context(), v.repo, pid, vid, uid, uv, time.Now())
+	if err != nil {
+		switch err {
+		case views.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case views.ErrInvalidID, views.ErrInvalidGroup, tasks.ErrInvalidSort:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case views.ErrForbidden:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return errors.Wrapf(err, "updating view %q", vid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, view, http.StatusOK)
+}
+
+func (v *Views) Delete(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	vid := chi.URLParam(r, "vid")
+	uid := v.auth0.UserByID(r.Context())
+
+	if err := views.Delete(r.Context(), v.repo, pid, vid, uid); err != nil {
+		switch err {
+		case views.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case views.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case views.ErrForbidden:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return errors.Wrapf(err, "deleting view %q", vid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/api/publishers"
+	"github.com/devpies/devpie-client-core/projects/domain/columns"
+	"github.com/devpies/devpie-client-core/projects/domain/roles"
+	"github.com/devpies/devpie-client-core/projects/domain/tasks"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/stream"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+// isClient reports whether the caller was assigned the client role by the
+// set-claim--assigned-role rule. Clients only see client-visible tasks.
+func isClient(ctx context.Context, a0 *auth0.Auth0) bool {
+	return a0.HasRole(ctx, auth0.RoleClient)
+}
+
+// visibleTask returns a task the caller may see. Internal tasks don't exist for clients.
+func visibleTask(ctx context.Context, repo *database.Repository, a0 *auth0.Auth0, tid string) (tasks.Task, error) {
+	t, err := tasks.Retrieve(ctx, repo, tid)
+	if err == nil && t.Internal && isClient(ctx, a0) {
+		err = tasks.ErrNotFound
+	}
+	if err != nil {
+		switch err {
+		case tasks.ErrNotFound:
+			return t, web.NewRequestError(err, http.StatusNotFound)
+		case tasks.ErrInvalidID:
+			return t, web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return t, errors.Wrapf(err, "looking for task %q", tid)
+		}
+	}
+	return t, nil
+}
+
+// Roles remembers who holds the client role, so what only the team may see is kept from
+// clients outside of their own requests too, such as in notifications.
+type Roles struct {
+	repo  *database.Repository
+	auth0 *auth0.Auth0
+	seen  sync.Map
+}
+
+// Record is middleware recording the role and profile of authenticated callers. They are
+// only written again when they change.
+func (rs *Roles) Record() web.Middleware {
+	return func(after web.Handler) web.Handler {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			p := roles.Profile{
+				UserID:   rs.auth0.UserByID(r.Context()),
+				Client:   isClient(r.Context(), rs.auth0),
+				Email:    rs.auth0.UserEmail(r.Context()),
+				Nickname: rs.auth0.UserNickname(r.Context()),
+			}
+
+			if seen, ok := rs.seen.Load(p.UserID); p.UserID != "" && (!ok || seen.(roles.Profile) != p) {
+				if err := roles.Record(r.Context(), rs.repo, p, time.Now()); err != nil {
+					return err
+				}
+				rs.seen.Store(p.UserID, p)
+			}
+
+			return after(w, r)
+		}
+	}
+}
+
+// clientTasks drops internal tasks and strips what clients must not see from the rest.
+func clientTasks(list []tasks.Task) []tasks.Task {
+	visible := make([]tasks.Task, 0, len(list))
+	for _, t := range list {
+		if !t.Internal {
+			visible = append(visible, t.ClientView())
+		}
+	}
+	return visible
+}
+
+// clientColumns removes internal tasks from the columns of a board.
+func clientColumns(list []columns.Column, ts []tasks.Task) []columns.Column {
+	internal := make(map[string]bool)
+	for _, t := range ts {
+		if t.Internal {
+			internal[t.ID] = true
+		}
+	}
+
+	for i := range list {
+		ids := make([]string, 0, len(list[i].TaskIDS))
+		for _, id := range list[i].TaskIDS {
+			if !internal[id] {
+				ids = append(ids, id)
+			}
+		}
+		list[i].TaskIDS = ids
+	}
+	return list
+}
+
+// taskColumn returns the id of the column holding a task, or "" when it isn't on the board.
+func taskColumn(ctx context.Context, repo *database.Repository, t tasks.Task) (string, error) {
+	cs, err := columns.List(ctx, repo, t.ProjectID)
+	if err != nil {
+		return "", errors.Wrapf(err, "listing columns of project %q", t.ProjectID)
+	}
+	for _, c := range cs {
+		for _, id := range c.TaskIDS {
+			if id == t.ID {
+				return c.ID, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// clientEvent prepares a board event for a client. Events about internal tasks are dropped,
+// except that a task that was just made internal is deleted from the client's board.
+// Estimates and internal comments are removed from the rest.
+func clientEvent(e stream.Event) (stream.Event, bool) {
+	var data map[string]interface{}
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return e, false
+	}
+
+	if internal, _ := data["internal"].(bool); internal {
+		if hidden, _ := data["hidden"].(bool); !hidden || e.Type != publishers.EventsTaskUpdated {
+			return e, false
+		}
+
+		raw, err := json.Marshal(map[string]interface{}{
+			"taskId":       data["taskId"],
+			"projectId":    data["projectId"],
+			"fromColumnId": data["fromColumnId"],
+			"updatedAt":    data["updatedAt"],
+		})
+		if err != nil {
+			return e, false
+		}
+		e.Type = publishers.EventsTaskDeleted
+		e.Data = raw
+
+		return e, true
+	}
+	_, points := data["points"]
+	_, comments := data["internalComments"]
+	if !points && !comments {
+		return e, true
+	}
+	delete(data, "points")
+	delete(data, "internalComments")
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return e, false
+	}
+	e.Data = raw
+
+	return e, true
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/api/publishers"
+	"github.com/devpies/devpie-client-core/projects/domain/permissions"
+	"github.com/devpies/devpie-client-core/projects/domain/roles"
+	"github.com/devpies/devpie-client-core/projects/domain/tasks"
+	"github.com/devpies/devpie-client-core/projects/domain/watchers"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+	"github.com/devpies/devpie-client-events/go/events"
+)
+
+type Watchers struct {
+	repo  *database.Repository
+	log   *log.Logger
+	auth0 *auth0.Auth0
+}
+
+func (wt *Watchers) List(w http.ResponseWriter, r *http.Request) error {
+	uid := wt.auth0.UserByID(r.Context())
+
+	list, err := watchers.List(r.Context(), wt.repo, uid)
+	if err != nil {
+		return errors.Wrapf(err, "listing watched items of %q", uid)
+	}
+
+	// watches outlive grants and memberships, so access is checked again on the way out
+	access := make(map[string]bool)
+	visible := make([]watchers.Watcher, 0, len(list))
+	for _, wa := range list {
+		ok, seen := access[wa.ProjectID]
+		if !seen {
+			_, err := permissions.Authorize(r.Context(), wt.repo, wa.ProjectID, uid, permissions.ViewProject)
+			if err != nil && err != permissions.ErrForbidden && err != permissions.ErrNotFound {
+				return errors.Wrapf(err, "authorizing watched project %q", wa.ProjectID)
+			}
+			ok = err == nil
+			access[wa.ProjectID] = ok
+		}
+		if ok {
+			visible = append(visible, wa)
+		}
+	}
+	list = visible
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (wt *Watchers) WatchProject(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	uid := wt.auth0.UserByID(r.Context())
+
+	wa, err := watchers.Create(r.Context(), wt.repo, pid, "", uid, time.Now())
+	if err != nil {
+		switch err {
+		case watchers.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "watching project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, wa, http.StatusCreated)
+}
+
+func (wt *Watchers) UnwatchProject(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	uid := wt.auth0.UserByID(r.Context())
+
+	if err := watchers.Delete(r.Context(), wt.repo, pid, "", uid); err != nil {
+		switch err {
+		case watchers.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case watchers.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "unwatching project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+func (wt *Watchers) WatchTask(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+	uid := wt.auth0.UserByID(r.Context())
+
+	ts, err := visibleTask(r.Context(), wt.repo, wt.auth0, tid)
+	if err != nil {
+		return err
+	}
+
+	wa, err := watchers.Create(r.Context(), wt.repo, ts.ProjectID, ts.ID, uid, time.Now())
+	if err != nil {
+		switch err {
+		case watchers.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "watching task %q", tid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, wa, http.StatusCreated)
+}
+
+func (wt *Watchers) UnwatchTask(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+	uid := wt.auth0.UserByID(r.Context())
+
+	ts, err := visibleTask(r.Context(), wt.repo, wt.auth0, tid)
+	if err != nil {
+		return err
+	}
+
+	if err := watchers.Delete(r.Context(), wt.repo, ts.ProjectID, ts.ID, uid); err != nil {
+		switch err {
+		case watchers.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case watchers.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "unwatching task %q", tid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+// projectRecipients returns who watches a project or, when tid is not empty, one of its
+// tasks, except the user who changed it. Watchers who have since lost access to the project
+// are left out.
+func projectRecipients(ctx context.Context, repo *database.Repository, pid, tid, uid string) ([]string, error) {
+	ids, err := watchers.Recipients(ctx, repo, pid, tid, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, err := permissions.Authorize(ctx, repo, pid, id, permissions.ViewProject); err != nil {
+			switch err {
+			case permissions.ErrForbidden:
+				continue
+			default:
+				return nil, errors.Wrapf(err, "authorizing watcher %q", id)
+			}
+		}
+		allowed = append(allowed, id)
+	}
+
+	return allowed, nil
+}
+
+// taskRecipients returns who watches a task or its project, except the user who changed it.
+// Only the team hears about internal tasks, so clients and users of unknown role are left out.
+func taskRecipients(ctx context.Context, repo *database.Repository, t tasks.Task, uid string) ([]string, error) {
+	ids, err := projectRecipients(ctx, repo, t.ProjectID, t.ID, uid)
+	if err != nil || !t.Internal {
+		return ids, err
+	}
+	return roles.Team(ctx, repo, ids)
+}
+
+// notifyWatchers tells everyone watching the changed project or task, except the user who
+// made the change, what happened.
+func notifyWatchers(nats *events.Client, publish publishers.Publisher, recipients []string,
+	data publishers.WatchEventData, uid string) error {
+	if nats == nil || len(recipients) == 0 {
+		return nil
+	}
+
+	data.Recipients = recipients
+
+	return publish.WatchersNotified(nats, data, uid)
+}
+
+func taskWatchEvent(t tasks.Task, action string) publishers.WatchEventData {
+	return publishers.WatchEventData{
+		ProjectID: t.ProjectID,
+		TaskID:    t.ID,
+		Key:       t.Key,
+		Title:     t.Title,
+		Action:    action,
+	}
+}
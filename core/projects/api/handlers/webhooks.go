@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/webhooks"
+	"github.com/devpies/devpie-client-core/projects/platform/auth0"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/web"
+)
+
+type Webhooks struct {
+	repo  *database.Repository
+	log   *log.Logger
+	auth0 *auth0.Auth0
+}
+
+func (wh *Webhooks) List(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	list, err := webhooks.List(r.Context(), wh.repo, pid)
+	if err != nil {
+		switch err {
+		case webhooks.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "listing webhooks for project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (wh *Webhooks) Create(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	uid := wh.auth0.UserByID(r.Context())
+
+	var nw webhooks.NewWebhook
+	if err := web.Decode(r, &nw); err != nil {
+		return err
+	}
+
+	hook, err := webhooks.Create(r.Context(), wh.repo, nw, pid, uid, time.Now())
+	if err != nil {
+		switch err {
+		case webhooks.ErrInvalidID, webhooks.ErrInvalidURL:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "registering webhook for project %q", pid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, hook, http.StatusCreated)
+}
+
+func (wh *Webhooks) Delete(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	wid := chi.URLParam(r, "wid")
+
+	if err := webhooks.Delete(r.Context(), wh.repo, pid, wid); err != nil {
+		switch err {
+		case webhooks.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case webhooks.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "deleting webhook %q", wid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+func (wh *Webhooks) Deliveries(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	wid := chi.URLParam(r, "wid")
+
+	list, err := webhooks.Deliveries(r.Context(), wh.repo, pid, wid, 100)
+	if err != nil {
+		switch err {
+		case webhooks.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "listing deliveries for webhook %q", wid)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+func (wh *Webhooks) Redeliver(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	wid := chi.URLParam(r, "wid")
+	did := chi.URLParam(r, "did")
+
+	d, err := webhooks.Redeliver(r.Context(), wh.repo, pid, wid, did, time.Now())
+	if err != nil {
+		switch err {
+		case webhooks.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case webhooks.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return errors.Wrapf(err, "redelivering %q", did)
+		}
+	}
+
+	return web.Respond(r.Context(), w, d, http.StatusAccepted)
+}
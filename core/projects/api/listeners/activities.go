@@ -0,0 +1,141 @@
+package listeners
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/nats-io/stan.go"
+
+	"github.com/devpies/devpie-client-core/projects/api/publishers"
+	"github.com/devpies/devpie-client-core/projects/domain/activities"
+	"github.com/devpies/devpie-client-events/go/events"
+)
+
+// activitySubjects lists the events recorded in project activity feeds.
+var activitySubjects = []string{
+	publishers.EventsTaskCreated,
+	publishers.EventsTaskMoved,
+	publishers.EventsTaskAssigned,
+	publishers.EventsTaskCommented,
+	publishers.EventsTaskDeleted,
+	string(events.EventsProjectCreated),
+	string(events.EventsProjectUpdated),
+}
+
+// excerpt is the longest comment kept in the feed, in characters.
+const excerpt = 280
+
+// RegisterActivities records events in the activity feeds. It uses its own durable queue
+// group so the feeds catch up on events published while the service was down.
+func (l *Listeners) RegisterActivities(nats *events.Client, queueGrp string) {
+	grp := queueGrp + "-activities"
+	for _, subj := range activitySubjects {
+		nats.Listen(subj, grp, l.handleActivityEvent, stan.DeliverAllAvailable(),
+			stan.SetManualAckMode(), stan.AckWait(l.dur), stan.DurableName(grp))
+	}
+}
+
+func (l *Listeners) handleActivityEvent(m *stan.Msg) {
+	a, err := activity(m.Data)
+	if err != nil || a.ProjectID == "" {
+		l.log.Printf("warning: failed to unmarshal activity event \n %v", err)
+		if err := m.Ack(); err != nil {
+			l.log.Printf("failed to Acknowledge message \n %v", err)
+		}
+		return
+	}
+	a.OccurredAt = time.Unix(0, m.Timestamp)
+
+	if err := activities.Record(context.Background(), l.repo, a); err != nil {
+		l.log.Printf("failed to record activity: %s \n %v", a.ID, err)
+		return
+	}
+
+	if err := m.Ack(); err != nil {
+		l.log.Printf("failed to Acknowledge message \n %v", err)
+	}
+}
+
+// activity turns a task or project event into a feed entry with the details worth showing.
+func activity(msg []byte) (activities.Activity, error) {
+	var a activities.Activity
+	var e struct {
+		ID       string          `json:"id"`
+		Type     string          `json:"type"`
+		Data     json.RawMessage `json:"data"`
+		Metadata events.Metadata `json:"metadata"`
+	}
+
+	if err := json.Unmarshal(msg, &e); err != nil {
+		return a, err
+	}
+
+	a = activities.Activity{ID: e.ID, Type: e.Type, ActorID: e.Metadata.UserID}
+
+	var data interface{}
+	switch e.Type {
+	case string(events.TypeProjectCreated):
+		var p events.ProjectCreatedEventData
+		if err := json.Unmarshal(e.Data, &p); err != nil {
+			return a, err
+		}
+		a.ProjectID = p.ProjectID
+		if a.ActorID == "" {
+			a.ActorID = p.UserID
+		}
+		data = struct {
+			Name string `json:"name"`
+		}{p.Name}
+	case string(events.TypeProjectUpdated):
+		var p events.ProjectUpdatedEventData
+		if err := json.Unmarshal(e.Data, &p); err != nil {
+			return a, err
+		}
+		a.ProjectID = p.ProjectID
+		data = struct {
+			Name        *string `json:"name,omitempty"`
+			Description *string `json:"description,omitempty"`
+			Active      *bool   `json:"active,omitempty"`
+			Public      *bool   `json:"public,omitempty"`
+		}{p.Name, p.Description, p.Active, p.Public}
+	default:
+		var t publishers.TaskEventData
+		if err := json.Unmarshal(e.Data, &t); err != nil {
+			return a, err
+		}
+		a.ProjectID = t.ProjectID
+		a.TaskID = t.TaskID
+		a.Internal = t.Internal
+
+		var comment string
+		if e.Type == publishers.EventsTaskCommented && len(t.Comments) > 0 {
+			comment = truncate(t.Comments[len(t.Comments)-1], excerpt)
+		}
+		data = struct {
+			Key          string `json:"key,omitempty"`
+			Title        string `json:"title,omitempty"`
+			AssignedTo   string `json:"assignedTo,omitempty"`
+			ColumnID     string `json:"columnId,omitempty"`
+			ColumnTitle  string `json:"columnTitle,omitempty"`
+			FromColumnID string `json:"fromColumnId,omitempty"`
+			Comment      string `json:"comment,omitempty"`
+		}{t.Key, t.Title, t.AssignedTo, t.ColumnID, t.ColumnTitle, t.FromColumnID, comment}
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return a, err
+	}
+	a.Data = b
+
+	return a, nil
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
@@ -0,0 +1,65 @@
+package listeners
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/nats-io/stan.go"
+
+	"github.com/devpies/devpie-client-core/projects/api/publishers"
+	"github.com/devpies/devpie-client-core/projects/platform/stream"
+	"github.com/devpies/devpie-client-events/go/events"
+)
+
+// streamSubjects lists the board events pushed to streaming clients.
+var streamSubjects = []string{
+	publishers.EventsTaskCreated,
+	publishers.EventsTaskUpdated,
+	publishers.EventsTaskMoved,
+	publishers.EventsTaskDeleted,
+	publishers.EventsColumnCreated,
+	string(events.EventsProjectUpdated),
+	string(events.EventsProjectDeleted),
+}
+
+// RegisterStream feeds board events into the hub. The queue group must be unique to the
+// replica so that every replica receives every event. Recent history is replayed on start
+// so clients can resume on any replica.
+func (l *Listeners) RegisterStream(nats *events.Client, hub *stream.Hub, replica string, window time.Duration) {
+	for _, subj := range streamSubjects {
+		nats.Listen(subj, replica, l.handleStreamEvent(hub), stan.StartAtTimeDelta(window))
+	}
+}
+
+func (l *Listeners) handleStreamEvent(hub *stream.Hub) stan.MsgHandler {
+	return func(m *stan.Msg) {
+		var msg struct {
+			ID   string          `json:"id"`
+			Type string          `json:"type"`
+			Data json.RawMessage `json:"data"`
+		}
+		var data struct {
+			ProjectID string `json:"projectId"`
+		}
+
+		if err := json.Unmarshal(m.Data, &msg); err != nil {
+			l.log.Printf("warning: failed to unmarshal stream event \n %v", err)
+			return
+		}
+		if err := json.Unmarshal(msg.Data, &data); err != nil || data.ProjectID == "" {
+			l.log.Printf("warning: stream event %s has no project \n %v", msg.ID, err)
+			return
+		}
+
+		hub.Publish(stream.Event{
+			ID:        msg.ID,
+			ProjectID: data.ProjectID,
+			Type:      msg.Type,
+			Data:      msg.Data,
+		})
+
+		if msg.Type == string(events.TypeProjectDeleted) {
+			hub.Forget(data.ProjectID)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package listeners
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/nats-io/stan.go"
+
+	"github.com/devpies/devpie-client-core/projects/api/publishers"
+	"github.com/devpies/devpie-client-core/projects/domain/webhooks"
+	"github.com/devpies/devpie-client-events/go/events"
+)
+
+// webhookSubjects lists the events sent to project webhooks. Project deletion is not
+// among them because a project's webhooks are removed along with it.
+var webhookSubjects = []string{
+	publishers.EventsTaskCreated,
+	publishers.EventsTaskUpdated,
+	publishers.EventsTaskMoved,
+	publishers.EventsTaskDeleted,
+	publishers.EventsColumnCreated,
+	string(events.EventsProjectUpdated),
+}
+
+// RegisterWebhooks queues a delivery for every webhook subscribed to an event. It uses its
+// own queue group so it doesn't compete with the other listeners on the same subjects.
+func (l *Listeners) RegisterWebhooks(nats *events.Client, queueGrp string) {
+	grp := queueGrp + "-webhooks"
+	for _, subj := range webhookSubjects {
+		nats.Listen(subj, grp, l.handleWebhookEvent, stan.DeliverAllAvailable(),
+			stan.SetManualAckMode(), stan.AckWait(l.dur), stan.DurableName(grp))
+	}
+}
+
+func (l *Listeners) handleWebhookEvent(m *stan.Msg) {
+	var msg struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			ProjectID string `json:"projectId"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(m.Data, &msg); err != nil || msg.Data.ProjectID == "" {
+		l.log.Printf("warning: failed to unmarshal webhook event \n %v", err)
+		if err := m.Ack(); err != nil {
+			l.log.Printf("failed to Acknowledge message \n %v", err)
+		}
+		return
+	}
+
+	ctx := context.Background()
+
+	ids, err := webhooks.Subscribed(ctx, l.repo, msg.Data.ProjectID, msg.Type)
+	if err != nil {
+		l.log.Printf("failed to find webhooks for event: %s \n %v", msg.ID, err)
+		return
+	}
+
+	for _, wid := range ids {
+		if err := webhooks.CreateDelivery(ctx, l.repo, wid, msg.ID, msg.Type, string(m.Data), time.Now()); err != nil {
+			l.log.Printf("failed to queue delivery of event: %s \n %v", msg.ID, err)
+			return
+		}
+	}
+
+	if err := m.Ack(); err != nil {
+		l.log.Printf("failed to Acknowledge message \n %v", err)
+	}
+}
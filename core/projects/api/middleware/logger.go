@@ -4,14 +4,32 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/go-chi/chi"
+
 	"github.com/devpies/devpie-client-core/projects/platform/web"
 )
 
 // Logger writes some information about the request to the logs in the
 // format: (200) GET /foo -> IP ADDR (latency)
 func Logger(log *log.Logger) web.Middleware {
+	return logger(log, func(r *http.Request) string { return r.URL.Path })
+}
+
+// RedactedLogger is Logger for routes with a secret in their path, such as calendar feed
+// tokens. The value of the named route parameter is left out of the logs.
+func RedactedLogger(log *log.Logger, param string) web.Middleware {
+	return logger(log, func(r *http.Request) string {
+		if secret := chi.URLParam(r, param); secret != "" {
+			return strings.Replace(r.URL.Path, secret, "[redacted]", 1)
+		}
+		return r.URL.Path
+	})
+}
+
+func logger(log *log.Logger, path func(r *http.Request) string) web.Middleware {
 
 	// This is the actual middleware function to be executed.
 	f := func(before web.Handler) web.Handler {
@@ -27,7 +45,7 @@ func Logger(log *log.Logger) web.Middleware {
 
 			log.Printf("(%d) : %s %s -> %s (%s)",
 				v.StatusCode,
-				r.Method, r.URL.Path,
+				r.Method, path(r),
 				r.RemoteAddr, time.Since(v.Start),
 			)
 
@@ -0,0 +1,107 @@
+package publishers
+
+import "github.com/devpies/devpie-client-events/go/events"
+
+// Subjects for board events that are not defined by the events library yet.
+const (
+	EventsTaskCreated   = "TaskCreated"
+	EventsTaskUpdated   = "TaskUpdated"
+	EventsTaskMoved     = "TaskMoved"
+	EventsTaskDeleted   = "TaskDeleted"
+	EventsTaskAssigned  = "TaskAssigned"
+	EventsTaskCommented = "TaskCommented"
+	EventsColumnCreated = "ColumnCreated"
+	EventsGrantCreated  = "GrantCreated"
+	EventsGrantDeleted  = "GrantDeleted"
+
+	EventsWatchersNotified = "WatchersNotified"
+)
+
+// TaskEvent is published whenever a task is created, changed, moved or deleted.
+type TaskEvent struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	Data     TaskEventData   `json:"data"`
+	Metadata events.Metadata `json:"metadata"`
+}
+
+// TaskEventData describes the task after the change. Column ids are set when the
+// change affects the task's position on the board.
+type TaskEventData struct {
+	TaskID    string   `json:"taskId"`
+	ProjectID string   `json:"projectId"`
+	Key       string   `json:"key,omitempty"`
+	Title     string   `json:"title,omitempty"`
+	Points    int      `json:"points"`
+	Internal  bool     `json:"internal,omitempty"`
+	Content   string   `json:"content,omitempty"`
+	Comments  []string `json:"comments,omitempty"`
+	// CommentsHTML are the comments rendered as sanitized HTML. Only TaskCommented sets them.
+	CommentsHTML []string `json:"commentsHtml,omitempty"`
+	// InternalComments are only meant for the team. They are dropped from client streams.
+	InternalComments []string `json:"internalComments,omitempty"`
+	AssignedTo       string   `json:"assignedTo,omitempty"`
+	ColumnID         string   `json:"columnId,omitempty"`
+	ColumnTitle      string   `json:"columnTitle,omitempty"`
+	FromColumnID     string   `json:"fromColumnId,omitempty"`
+	// Hidden is set on the update that made the task internal. Clients are told the task
+	// was deleted instead.
+	Hidden    bool   `json:"hidden,omitempty"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// ColumnEvent is published whenever a column is added to a board.
+type ColumnEvent struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	Data     ColumnEventData `json:"data"`
+	Metadata events.Metadata `json:"metadata"`
+}
+
+// ColumnEventData describes the column after the change.
+type ColumnEventData struct {
+	ColumnID   string   `json:"columnId"`
+	ProjectID  string   `json:"projectId"`
+	Title      string   `json:"title"`
+	ColumnName string   `json:"columnName"`
+	TaskIDS    []string `json:"taskIds"`
+	UpdatedAt  string   `json:"updatedAt"`
+}
+
+// WatchEvent is published whenever a watched project or task changes. It carries the
+// users to notify so consumers don't need their own copy of the watchers.
+type WatchEvent struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	Data     WatchEventData  `json:"data"`
+	Metadata events.Metadata `json:"metadata"`
+}
+
+// WatchEventData describes what changed. TaskID is empty when the project itself changed.
+type WatchEventData struct {
+	ProjectID  string   `json:"projectId"`
+	TaskID     string   `json:"taskId,omitempty"`
+	Key        string   `json:"key,omitempty"`
+	Title      string   `json:"title"`
+	Action     string   `json:"action"`
+	Recipients []string `json:"recipients"`
+}
+
+// GrantEvent is published whenever a project grant is given or revoked, so other services
+// know who has access to a project without a team membership.
+type GrantEvent struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	Data     GrantEventData  `json:"data"`
+	Metadata events.Metadata `json:"metadata"`
+}
+
+// GrantEventData describes the grant. Only the ids are set when it was revoked.
+type GrantEventData struct {
+	GrantID    string `json:"grantId"`
+	ProjectID  string `json:"projectId"`
+	UserID     string `json:"userId,omitempty"`
+	Role       string `json:"role,omitempty"`
+	Expiration string `json:"expiration,omitempty"`
+	CreatedAt  string `json:"createdAt,omitempty"`
+}
@@ -0,0 +1,212 @@
+package publishers
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+
+	"github.com/devpies/devpie-client-core/projects/domain/columns"
+	"github.com/devpies/devpie-client-core/projects/domain/grants"
+	"github.com/devpies/devpie-client-core/projects/domain/tasks"
+	"github.com/devpies/devpie-client-events/go/events"
+)
+
+// Publisher describes the behavior required for publishing events
+type Publisher interface {
+	TaskCreated(nats *events.Client, t tasks.Task, cid, uid string) error
+	TaskUpdated(nats *events.Client, t tasks.Task, uid string) error
+	TaskMoved(nats *events.Client, t tasks.Task, from string, to columns.Column, uid string) error
+	TaskDeleted(nats *events.Client, t tasks.Task, cid, uid string) error
+	TaskHidden(nats *events.Client, t tasks.Task, cid, uid string) error
+	TaskAssigned(nats *events.Client, t tasks.Task, uid string) error
+	TaskCommented(nats *events.Client, t tasks.Task, uid string) error
+	ColumnCreated(nats *events.Client, c columns.Column, uid string) error
+	WatchersNotified(nats *events.Client, data WatchEventData, uid string) error
+	GrantCreated(nats *events.Client, g grants.Grant, uid string) error
+	GrantDeleted(nats *events.Client, pid, gid, uid string) error
+}
+
+// Publishers defines handlers that trigger events
+type Publishers struct{}
+
+// TaskCreated publishes a TaskCreated event
+func (p *Publishers) TaskCreated(nats *events.Client, t tasks.Task, cid, uid string) error {
+	data := taskEventData(t)
+	data.ColumnID = cid
+
+	return publishTask(nats, EventsTaskCreated, data, uid)
+}
+
+// TaskUpdated publishes a TaskUpdated event
+func (p *Publishers) TaskUpdated(nats *events.Client, t tasks.Task, uid string) error {
+	return publishTask(nats, EventsTaskUpdated, taskEventData(t), uid)
+}
+
+// TaskMoved publishes a TaskMoved event
+func (p *Publishers) TaskMoved(nats *events.Client, t tasks.Task, from string, to columns.Column, uid string) error {
+	data := taskEventData(t)
+	data.FromColumnID = from
+	data.ColumnID = to.ID
+	data.ColumnTitle = to.Title
+
+	return publishTask(nats, EventsTaskMoved, data, uid)
+}
+
+// TaskDeleted publishes a TaskDeleted event
+func (p *Publishers) TaskDeleted(nats *events.Client, t tasks.Task, cid, uid string) error {
+	data := taskEventData(t)
+	data.FromColumnID = cid
+
+	return publishTask(nats, EventsTaskDeleted, data, uid)
+}
+
+// TaskHidden publishes the TaskUpdated event of a task that was just made internal. cid is
+// the column the task sits in, so clients can take it off their board.
+func (p *Publishers) TaskHidden(nats *events.Client, t tasks.Task, cid, uid string) error {
+	data := taskEventData(t)
+	data.FromColumnID = cid
+	data.Hidden = true
+
+	return publishTask(nats, EventsTaskUpdated, data, uid)
+}
+
+// TaskAssigned publishes a TaskAssigned event
+func (p *Publishers) TaskAssigned(nats *events.Client, t tasks.Task, uid string) error {
+	return publishTask(nats, EventsTaskAssigned, taskEventData(t), uid)
+}
+
+// TaskCommented publishes a TaskCommented event
+func (p *Publishers) TaskCommented(nats *events.Client, t tasks.Task, uid string) error {
+	return publishTask(nats, EventsTaskCommented, taskEventData(t), uid)
+}
+
+// ColumnCreated publishes a ColumnCreated event
+func (p *Publishers) ColumnCreated(nats *events.Client, c columns.Column, uid string) error {
+	e := ColumnEvent{
+		ID:   uuid.New().String(),
+		Type: EventsColumnCreated,
+		Data: ColumnEventData{
+			ColumnID:   c.ID,
+			ProjectID:  c.ProjectID,
+			Title:      c.Title,
+			ColumnName: c.ColumnName,
+			TaskIDS:    c.TaskIDS,
+			UpdatedAt:  c.UpdatedAt.String(),
+		},
+		Metadata: events.Metadata{
+			TraceID: uuid.New().String(),
+			UserID:  uid,
+		},
+	}
+
+	bytes, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	nats.Publish(EventsColumnCreated, bytes)
+
+	return nil
+}
+
+// WatchersNotified publishes a WatchersNotified event
+func (p *Publishers) WatchersNotified(nats *events.Client, data WatchEventData, uid string) error {
+	e := WatchEvent{
+		ID:   uuid.New().String(),
+		Type: EventsWatchersNotified,
+		Data: data,
+		Metadata: events.Metadata{
+			TraceID: uuid.New().String(),
+			UserID:  uid,
+		},
+	}
+
+	bytes, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	nats.Publish(EventsWatchersNotified, bytes)
+
+	return nil
+}
+
+// GrantCreated publishes a GrantCreated event
+func (p *Publishers) GrantCreated(nats *events.Client, g grants.Grant, uid string) error {
+	data := GrantEventData{
+		GrantID:   g.ID,
+		ProjectID: g.ProjectID,
+		UserID:    g.UserID,
+		Role:      g.Role,
+		CreatedAt: g.CreatedAt.String(),
+	}
+	if g.Expiration != nil {
+		data.Expiration = g.Expiration.String()
+	}
+
+	return publishGrant(nats, EventsGrantCreated, data, uid)
+}
+
+// GrantDeleted publishes a GrantDeleted event
+func (p *Publishers) GrantDeleted(nats *events.Client, pid, gid, uid string) error {
+	return publishGrant(nats, EventsGrantDeleted, GrantEventData{GrantID: gid, ProjectID: pid}, uid)
+}
+
+func publishGrant(nats *events.Client, subject string, data GrantEventData, uid string) error {
+	e := GrantEvent{
+		ID:   uuid.New().String(),
+		Type: subject,
+		Data: data,
+		Metadata: events.Metadata{
+			TraceID: uuid.New().String(),
+			UserID:  uid,
+		},
+	}
+
+	bytes, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	nats.Publish(subject, bytes)
+
+	return nil
+}
+
+func taskEventData(t tasks.Task) TaskEventData {
+	return TaskEventData{
+		TaskID:           t.ID,
+		ProjectID:        t.ProjectID,
+		Key:              t.Key,
+		Title:            t.Title,
+		Points:           t.Points,
+		Internal:         t.Internal,
+		Content:          t.Content,
+		Comments:         t.Comments,
+		CommentsHTML:     t.CommentsHTML,
+		InternalComments: t.InternalComments,
+		AssignedTo:       t.AssignedTo,
+		UpdatedAt:        t.UpdatedAt.String(),
+	}
+}
+
+func publishTask(nats *events.Client, subject string, data TaskEventData, uid string) error {
+	e := TaskEvent{
+		ID:   uuid.New().String(),
+		Type: subject,
+		Data: data,
+		Metadata: events.Metadata{
+			TraceID: uuid.New().String(),
+			UserID:  uid,
+		},
+	}
+
+	bytes, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	nats.Publish(subject, bytes)
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package schedulers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/api/publishers"
+	"github.com/devpies/devpie-client-core/projects/domain/checklists"
+	"github.com/devpies/devpie-client-core/projects/domain/columns"
+	"github.com/devpies/devpie-client-core/projects/domain/recurrences"
+	"github.com/devpies/devpie-client-core/projects/domain/tasks"
+	"github.com/devpies/devpie-client-core/projects/domain/transitions"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-events/go/events"
+)
+
+// recurrenceLease is how long a claimed series waits before another attempt when creating
+// its task fails
+const recurrenceLease = 5 * time.Minute
+
+// Recurrer creates the tasks of recurring series
+type Recurrer struct {
+	log     *log.Logger
+	repo    *database.Repository
+	nats    *events.Client
+	publish publishers.Publisher
+}
+
+// NewRecurrer creates a new Recurrer. Created tasks are announced over nats when it is set.
+func NewRecurrer(log *log.Logger, repo *database.Repository, nats *events.Client) *Recurrer {
+	return &Recurrer{log, repo, nats, &publishers.Publishers{}}
+}
+
+// Start creates due tasks every interval until stop is closed. Every replica may run it:
+// series are claimed in the database before their tasks are created.
+func (rc *Recurrer) Start(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			if err := rc.CreateDue(context.Background(), now); err != nil {
+				rc.log.Printf("failed to create recurring tasks \n %v", err)
+			}
+		}
+	}
+}
+
+// CreateDue creates the next task of every series that is due. A series whose latest task
+// was deleted is stopped.
+func (rc *Recurrer) CreateDue(ctx context.Context, now time.Time) error {
+	rs, err := recurrences.Claim(ctx, rc.repo, now, recurrenceLease, batch)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range rs {
+		if err := rc.create(ctx, r, now); err != nil {
+			if errors.Cause(err) == tasks.ErrNotFound {
+				rc.log.Printf("stopping recurrence %s: task %s no longer exists", r.ID, r.TaskID)
+				if err := recurrences.Delete(ctx, rc.repo, r.ProjectID, r.ID); err != nil {
+					rc.log.Printf("failed to stop recurrence: %s \n %v", r.ID, err)
+				}
+				continue
+			}
+			rc.log.Printf("failed to create task for recurrence: %s \n %v", r.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// create copies the latest task of a series into its column, along with its assignee and
+// an unchecked copy of its checklist. Every step can be repeated: when an earlier attempt
+// failed part way, the task it created for the occurrence is completed instead of copied
+// again.
+func (rc *Recurrer) create(ctx context.Context, r recurrences.Recurrence, now time.Time) error {
+	prev, err := tasks.Retrieve(ctx, rc.repo, r.TaskID)
+	if err != nil {
+		return errors.Wrapf(err, "looking for task %q", r.TaskID)
+	}
+
+	c, err := columns.Retrieve(ctx, rc.repo, r.ColumnID)
+	if err != nil {
+		return errors.Wrapf(err, "looking for column %q", r.ColumnID)
+	}
+
+	occurrence := r.Occurrences + 1
+
+	t, err := tasks.RetrieveOccurrence(ctx, rc.repo, r.ID, occurrence)
+	resumed := err == nil
+	switch {
+	case err == tasks.ErrNotFound:
+		nt := tasks.NewTask{
+			Title:        prev.Title,
+			Content:      prev.Content,
+			Labels:       prev.Labels,
+			Points:       prev.Points,
+			Internal:     prev.Internal,
+			RecurrenceID: r.ID,
+			Occurrence:   occurrence,
+		}
+
+		if t, err = tasks.Create(ctx, rc.repo, nt, r.ProjectID, r.CreatedBy, now); err != nil {
+			return errors.Wrapf(err, "creating task for recurrence %q", r.ID)
+		}
+	case err != nil:
+		return err
+	}
+
+	if prev.AssignedTo != "" && t.AssignedTo != prev.AssignedTo {
+		ut := tasks.UpdateTask{AssignedTo: &prev.AssignedTo}
+		if t, err = tasks.Update(ctx, rc.repo, t.ID, ut, now); err != nil {
+			return err
+		}
+	}
+
+	items, err := checklists.List(ctx, rc.repo, prev.ID)
+	if err != nil {
+		return err
+	}
+	if resumed {
+		if err := checklists.DeleteByTask(ctx, rc.repo, t.ID); err != nil {
+			return err
+		}
+	}
+	for _, i := range items {
+		ni := checklists.NewItem{Text: i.Text, AssignedTo: i.AssignedTo}
+		if _, err := checklists.Create(ctx, rc.repo, ni, r.ProjectID, t.ID, r.CreatedBy, now); err != nil {
+			return err
+		}
+	}
+	t.Checklist.Total = len(items)
+
+	cs, err := columns.List(ctx, rc.repo, r.ProjectID)
+	if err != nil {
+		return err
+	}
+	if !onBoard(cs, t.ID) {
+		ids := append(c.TaskIDS, t.ID)
+		if err := columns.Update(ctx, rc.repo, c.ID, columns.UpdateColumn{TaskIDS: &ids}, now); err != nil {
+			return err
+		}
+	}
+
+	ts, err := transitions.List(ctx, rc.repo, t.ID)
+	if err != nil {
+		return err
+	}
+	if len(ts) == 0 {
+		if _, err := transitions.Enter(ctx, rc.repo, t.ID, c, now); err != nil {
+			return err
+		}
+	}
+
+	if err := recurrences.Advance(ctx, rc.repo, r, t.ID, now); err != nil {
+		return err
+	}
+
+	if rc.nats != nil {
+		if err := rc.publish.TaskCreated(rc.nats, t, c.ID, r.CreatedBy); err != nil {
+			rc.log.Printf("failed to publish recurring task: %s \n %v", t.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// onBoard reports whether a task sits in one of the columns.
+func onBoard(cs []columns.Column, tid string) bool {
+	for _, c := range cs {
+		for _, id := range c.TaskIDS {
+			if id == tid {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package schedulers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/devpies/devpie-client-core/projects/domain/webhooks"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	wh "github.com/devpies/devpie-client-core/projects/platform/webhooks"
+)
+
+// batch is the number of deliveries claimed per tick
+const batch = 50
+
+// Deliverer sends queued webhook deliveries
+type Deliverer struct {
+	log    *log.Logger
+	repo   *database.Repository
+	client *http.Client
+}
+
+// NewDeliverer creates a new Deliverer. Requests to endpoints give up after timeout.
+func NewDeliverer(log *log.Logger, repo *database.Repository, timeout time.Duration) *Deliverer {
+	return &Deliverer{log, repo, wh.NewClient(timeout)}
+}
+
+// Start sends due deliveries every interval until stop is closed. Every replica may run
+// it: deliveries are claimed in the database before they are sent.
+func (d *Deliverer) Start(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			if err := d.SendDue(context.Background(), now); err != nil {
+				d.log.Printf("failed to send webhook deliveries \n %v", err)
+			}
+		}
+	}
+}
+
+// SendDue sends every delivery that is due. Failed attempts are retried with exponential
+// backoff until they run out of attempts.
+func (d *Deliverer) SendDue(ctx context.Context, now time.Time) error {
+	// the lease outlives a batch of requests that all time out
+	lease := d.client.Timeout*batch + time.Minute
+
+	ts, err := webhooks.Claim(ctx, d.repo, now, lease, batch)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range ts {
+		code, err := wh.Send(ctx, d.client, wh.Message{
+			URL:      t.URL,
+			Secret:   t.Secret,
+			Event:    t.EventType,
+			Delivery: t.ID,
+			Payload:  []byte(t.Payload),
+		})
+		if err != nil {
+			if err := webhooks.Fail(ctx, d.repo, t.Delivery, code, err.Error(), time.Now()); err != nil {
+				d.log.Printf("failed to record webhook delivery: %s \n %v", t.ID, err)
+			}
+			continue
+		}
+
+		if err := webhooks.Complete(ctx, d.repo, t.Delivery, code, time.Now()); err != nil {
+			d.log.Printf("failed to record webhook delivery: %s \n %v", t.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package activities
+
+import (
+	"context"
+	"strconv"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrInvalidID     = errors.New("id provided was not a valid UUID")
+	ErrInvalidCursor = errors.New("cursor provided was not valid")
+)
+
+// Page sizes of a feed.
+const (
+	DefaultLimit = 50
+	MaxLimit     = 100
+)
+
+// Record adds an event to the feed of its project. Events are recorded once, so a
+// redelivered event is ignored. Nothing is recorded for projects that no longer exist.
+func Record(ctx context.Context, repo database.Storer, a Activity) error {
+	if _, err := uuid.Parse(a.ProjectID); err != nil {
+		return ErrInvalidID
+	}
+	if a.Data == nil {
+		a.Data = []byte("{}")
+	}
+
+	q := `INSERT INTO activities (activity_id, project_id, team_id, actor_id, type, task_id, internal, data,
+		occurred_at)
+		SELECT :activity_id, p.project_id, COALESCE(p.team_id, ''), :actor_id, :type, :task_id, :internal, :data,
+		:occurred_at FROM projects p WHERE p.project_id = :project_id
+		ON CONFLICT (activity_id) DO NOTHING`
+
+	if _, err := repo.NamedExecContext(ctx, q, map[string]interface{}{
+		"activity_id": a.ID,
+		"project_id":  a.ProjectID,
+		"actor_id":    a.ActorID,
+		"type":        a.Type,
+		"task_id":     a.TaskID,
+		"internal":    a.Internal,
+		"data":        string(a.Data),
+		"occurred_at": a.OccurredAt.UTC(),
+	}); err != nil {
+		return errors.Wrapf(err, "recording activity %s", a.ID)
+	}
+
+	return nil
+}
+
+// List returns a page of the feed of some projects. Client feeds leave out internal tasks.
+func List(ctx context.Context, repo database.Storer, q Query) (Page, error) {
+	p := Page{Items: make([]Activity, 0)}
+
+	if len(q.ProjectIDs) == 0 {
+		return p, nil
+	}
+	if q.Limit <= 0 || q.Limit > MaxLimit {
+		q.Limit = DefaultLimit
+	}
+
+	stmt := repo.Select(
+		"activity_id",
+		"seq",
+		"project_id",
+		"team_id",
+		"actor_id",
+		"type",
+		"task_id",
+		"internal",
+		"data",
+		"occurred_at",
+	).From(
+		"activities",
+	).Where("project_id = ANY(?)", pq.Array(q.ProjectIDs))
+
+	if q.Cursor != "" {
+		seq, err := strconv.ParseInt(q.Cursor, 10, 64)
+		if err != nil {
+			return p, ErrInvalidCursor
+		}
+		stmt = stmt.Where(sq.Lt{"seq": seq})
+	}
+	if q.ActorID != "" {
+		stmt = stmt.Where(sq.Eq{"actor_id": q.ActorID})
+	}
+	if len(q.Types) > 0 {
+		stmt = stmt.Where(sq.Eq{"type": q.Types})
+	}
+	if q.Client {
+		stmt = stmt.Where("NOT internal")
+	}
+
+	// one extra row tells whether there is a next page
+	stmt = stmt.OrderBy("seq DESC").Limit(uint64(q.Limit + 1))
+
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return p, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.SelectContext(ctx, &p.Items, query, args...); err != nil {
+		return p, errors.Wrap(err, "selecting activities")
+	}
+
+	if len(p.Items) > q.Limit {
+		p.Items = p.Items[:q.Limit]
+		p.NextCursor = strconv.FormatInt(p.Items[q.Limit-1].Seq, 10)
+	}
+
+	return p, nil
+}
+
+// DeleteAll removes the feed of a project.
+func DeleteAll(ctx context.Context, repo database.Storer, pid string) error {
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"activities",
+	).Where(sq.Eq{"project_id": pid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting activities")
+	}
+
+	return nil
+}
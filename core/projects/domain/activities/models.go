@@ -0,0 +1,39 @@
+package activities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Activity is an entry of a project's activity feed, recorded from a published event. Seq
+// orders the feed and Data holds the details of the event, such as the task's key and
+// title or the column it moved to.
+type Activity struct {
+	ID         string          `db:"activity_id" json:"id"`
+	Seq        int64           `db:"seq" json:"-"`
+	ProjectID  string          `db:"project_id" json:"projectId"`
+	TeamID     string          `db:"team_id" json:"teamId"`
+	ActorID    string          `db:"actor_id" json:"actorId"`
+	Type       string          `db:"type" json:"type"`
+	TaskID     string          `db:"task_id" json:"taskId"`
+	Internal   bool            `db:"internal" json:"-"`
+	Data       json.RawMessage `db:"data" json:"data"`
+	OccurredAt time.Time       `db:"occurred_at" json:"occurredAt"`
+}
+
+// Query selects a page of a feed, newest first. A feed covers the projects in ProjectIDs.
+// Cursor continues a previous page, and ActorID and Types narrow the feed down.
+type Query struct {
+	ProjectIDs []string
+	ActorID    string
+	Types      []string
+	Cursor     string
+	Limit      int
+	Client     bool
+}
+
+// Page is a page of a feed. NextCursor is empty on the last page.
+type Page struct {
+	Items      []Activity `json:"items"`
+	NextCursor string     `json:"nextCursor"`
+}
@@ -0,0 +1,245 @@
+package approvals
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/tasks"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrInvalidID   = errors.New("id provided was not a valid UUID")
+	ErrNotPending  = errors.New("task is not awaiting approval")
+	ErrNotApprover = errors.New("user was not asked to approve this task")
+	ErrRequired    = errors.New("task needs client approval before it is done")
+	ErrInternal    = errors.New("internal tasks can't be sent for client approval")
+)
+
+// Request marks a task as needing approval and asks the approvers for it. A new request
+// replaces any earlier decision.
+func Request(ctx context.Context, repo database.Storer, t tasks.Task, nr NewRequest, uid string, now time.Time) (Approval, error) {
+	// clients never see internal tasks, so nobody could ever decide on them
+	if t.Internal {
+		return Approval{}, ErrInternal
+	}
+
+	a := Approval{
+		ID:        uuid.New().String(),
+		TaskID:    t.ID,
+		ProjectID: t.ProjectID,
+		Action:    Requested,
+		ActorID:   uid,
+		Approvers: nr.Approvers,
+		Comment:   nr.Comment,
+		CreatedAt: now.UTC(),
+	}
+
+	stmt := repo.Update(
+		"tasks",
+	).SetMap(map[string]interface{}{
+		"needs_approval":  true,
+		"approval_status": Requested,
+		"approvers":       pq.Array(a.Approvers),
+		"updated_at":      a.CreatedAt,
+	}).Where(sq.Eq{"task_id": t.ID})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return a, errors.Wrapf(err, "requesting approval of task %s", t.ID)
+	}
+
+	return a, record(ctx, repo, a)
+}
+
+// Decide records an approver's decision on a task awaiting approval.
+func Decide(ctx context.Context, repo database.Storer, t tasks.Task, action string, nd NewDecision, uid string, now time.Time) (Approval, error) {
+	a := Approval{
+		ID:        uuid.New().String(),
+		TaskID:    t.ID,
+		ProjectID: t.ProjectID,
+		Action:    action,
+		ActorID:   uid,
+		Approvers: make([]string, 0),
+		Comment:   nd.Comment,
+		CreatedAt: now.UTC(),
+	}
+
+	if !t.NeedsApproval || t.ApprovalStatus != Requested {
+		return a, ErrNotPending
+	}
+	if !contains(t.Approvers, uid) {
+		return a, ErrNotApprover
+	}
+
+	stmt := repo.Update(
+		"tasks",
+	).SetMap(map[string]interface{}{
+		"approval_status": action,
+		"updated_at":      a.CreatedAt,
+	}).Where(sq.Eq{"task_id": t.ID, "approval_status": Requested})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return a, errors.Wrapf(err, "deciding approval of task %s", t.ID)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return a, ErrNotPending
+	}
+
+	return a, record(ctx, repo, a)
+}
+
+// Withdraw takes back an approval request that is pending or was rejected, so the task no
+// longer needs approval. Approved tasks keep their approval.
+func Withdraw(ctx context.Context, repo database.Storer, t tasks.Task, nd NewDecision, uid string, now time.Time) (Approval, error) {
+	a := Approval{
+		ID:        uuid.New().String(),
+		TaskID:    t.ID,
+		ProjectID: t.ProjectID,
+		Action:    Withdrawn,
+		ActorID:   uid,
+		Approvers: make([]string, 0),
+		Comment:   nd.Comment,
+		CreatedAt: now.UTC(),
+	}
+
+	if !t.NeedsApproval || (t.ApprovalStatus != Requested && t.ApprovalStatus != Rejected) {
+		return a, ErrNotPending
+	}
+
+	stmt := repo.Update(
+		"tasks",
+	).SetMap(map[string]interface{}{
+		"needs_approval":  false,
+		"approval_status": "",
+		"approvers":       pq.Array(a.Approvers),
+		"updated_at":      a.CreatedAt,
+	}).Where(sq.Eq{"task_id": t.ID, "approval_status": t.ApprovalStatus})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return a, errors.Wrapf(err, "withdrawing approval of task %s", t.ID)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return a, ErrNotPending
+	}
+
+	return a, record(ctx, repo, a)
+}
+
+func record(ctx context.Context, repo database.Storer, a Approval) error {
+	stmt := repo.Insert(
+		"approvals",
+	).SetMap(map[string]interface{}{
+		"approval_id": a.ID,
+		"task_id":     a.TaskID,
+		"project_id":  a.ProjectID,
+		"action":      a.Action,
+		"actor_id":    a.ActorID,
+		"approvers":   pq.Array(a.Approvers),
+		"comment":     a.Comment,
+		"created_at":  a.CreatedAt,
+	})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "inserting approval: %v", a)
+	}
+
+	return nil
+}
+
+// List returns the approval history of a task, oldest first.
+func List(ctx context.Context, repo database.Storer, tid string) ([]Approval, error) {
+	var as = make([]Approval, 0)
+
+	if _, err := uuid.Parse(tid); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	stmt := repo.Select(
+		"approval_id",
+		"task_id",
+		"project_id",
+		"action",
+		"actor_id",
+		"approvers",
+		"comment",
+		"created_at",
+	).From(
+		"approvals",
+	).Where(sq.Eq{"task_id": "?"}).OrderBy("created_at")
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	rows, err := repo.QueryxContext(ctx, q, tid)
+	if err != nil {
+		return nil, errors.Wrap(err, "selecting approvals")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a Approval
+		err = rows.Scan(&a.ID, &a.TaskID, &a.ProjectID, &a.Action, &a.ActorID, (*pq.StringArray)(&a.Approvers), &a.Comment, &a.CreatedAt)
+		if err != nil {
+			return nil, errors.Wrap(err, "scanning approval")
+		}
+		as = append(as, a)
+	}
+
+	return as, rows.Err()
+}
+
+// CanComplete reports whether a task may be moved to the done column.
+func CanComplete(t tasks.Task) error {
+	if t.NeedsApproval && t.ApprovalStatus != Approved {
+		return ErrRequired
+	}
+	return nil
+}
+
+// DeleteByTask removes the approval history of a task.
+func DeleteByTask(ctx context.Context, repo database.Storer, tid string) error {
+	stmt := repo.Delete(
+		"approvals",
+	).Where(sq.Eq{"task_id": tid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting approvals of task %s", tid)
+	}
+
+	return nil
+}
+
+// DeleteAll removes the approval history of a project.
+func DeleteAll(ctx context.Context, repo database.Storer, pid string) error {
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"approvals",
+	).Where(sq.Eq{"project_id": pid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting all approvals")
+	}
+
+	return nil
+}
+
+func contains(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package approvals
+
+import "time"
+
+// Approval actions. All but Withdrawn double as the approval status of a task.
+const (
+	Requested = "requested"
+	Approved  = "approved"
+	Rejected  = "rejected"
+	Withdrawn = "withdrawn"
+)
+
+// Approval is an entry in the approval history of a task.
+type Approval struct {
+	ID        string    `db:"approval_id" json:"id"`
+	TaskID    string    `db:"task_id" json:"taskId"`
+	ProjectID string    `db:"project_id" json:"projectId"`
+	Action    string    `db:"action" json:"action"`
+	ActorID   string    `db:"actor_id" json:"actorId"`
+	Approvers []string  `db:"approvers" json:"approvers,omitempty"`
+	Comment   string    `db:"comment" json:"comment"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+// NewRequest asks client users to approve a task.
+type NewRequest struct {
+	Approvers []string `json:"approvers" validate:"required,min=1,dive,uuid"`
+	Comment   string   `json:"comment"`
+}
+
+// NewDecision approves or rejects a task.
+type NewDecision struct {
+	Comment string `json:"comment"`
+}
@@ -0,0 +1,185 @@
+package calendars
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/projects"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrNotFound = errors.New("calendar token not found")
+)
+
+// domain qualifies event UIDs so they stay unique across calendars.
+const domain = "devpie.io"
+
+// CreateToken issues a new feed token for a user. An existing token is replaced, so
+// subscribed calendars stop updating until they use the new feed address.
+func CreateToken(ctx context.Context, repo database.Storer, uid string, now time.Time) (Token, error) {
+	var t Token
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return t, errors.Wrap(err, "generating calendar token")
+	}
+
+	t = Token{
+		UserID:    uid,
+		Secret:    hex.EncodeToString(b),
+		CreatedAt: now.UTC(),
+	}
+
+	stmt := repo.Insert(
+		"calendar_tokens",
+	).SetMap(map[string]interface{}{
+		"user_id":      t.UserID,
+		"token_hash":   hash(t.Secret),
+		"last_used_at": nil,
+		"created_at":   t.CreatedAt,
+	}).Suffix(
+		"ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, " +
+			"last_used_at = NULL, created_at = EXCLUDED.created_at",
+	)
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return t, errors.Wrapf(err, "saving calendar token of user %s", uid)
+	}
+
+	return t, nil
+}
+
+// RetrieveToken returns the feed token of a user, without its secret.
+func RetrieveToken(ctx context.Context, repo database.Storer, uid string) (Token, error) {
+	var t Token
+
+	stmt := repo.Select(
+		"user_id",
+		"last_used_at",
+		"created_at",
+	).From("calendar_tokens").Where(sq.Eq{"user_id": "?"})
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return t, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.QueryRowxContext(ctx, q, uid).StructScan(&t); err != nil {
+		if err == sql.ErrNoRows {
+			return t, ErrNotFound
+		}
+		return t, err
+	}
+
+	return t, nil
+}
+
+// RevokeToken removes the feed token of a user.
+func RevokeToken(ctx context.Context, repo database.Storer, uid string) error {
+	stmt := repo.Delete(
+		"calendar_tokens",
+	).Where(sq.Eq{"user_id": uid})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "deleting calendar token of user %s", uid)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Authenticate returns the user a feed token belongs to and records its use.
+func Authenticate(ctx context.Context, repo database.Storer, secret string, now time.Time) (string, error) {
+	var uid string
+
+	q := repo.Rebind(`UPDATE calendar_tokens SET last_used_at = ? WHERE token_hash = ? RETURNING user_id`)
+
+	if err := repo.QueryRowxContext(ctx, q, now.UTC(), hash(secret)).Scan(&uid); err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrNotFound
+		}
+		return "", errors.Wrap(err, "looking up calendar token")
+	}
+
+	return uid, nil
+}
+
+// Events returns the due dates of the tasks assigned to a user and the milestones of the
+// projects the user can see. Clients don't get internal tasks.
+func Events(ctx context.Context, repo database.Storer, uid string, client bool) ([]Event, error) {
+	var es = make([]Event, 0)
+
+	ps, err := projects.List(ctx, repo, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(ps))
+	pids := make([]string, 0, len(ps))
+	for _, p := range ps {
+		names[p.ID] = p.Name
+		pids = append(pids, p.ID)
+	}
+
+	var rows []struct {
+		ID        string    `db:"id"`
+		Kind      string    `db:"kind"`
+		Key       string    `db:"key"`
+		Title     string    `db:"title"`
+		Details   string    `db:"details"`
+		ProjectID string    `db:"project_id"`
+		Due       time.Time `db:"due"`
+		UpdatedAt time.Time `db:"updated_at"`
+	}
+
+	q := repo.Rebind(`SELECT task_id AS id, 'task' AS kind, COALESCE(key, '') AS key, title,
+		COALESCE(content, '') AS details, project_id, due_date AS due, updated_at
+		FROM tasks WHERE assigned_to = ? AND project_id = ANY(?) AND due_date IS NOT NULL
+		AND NOT (? AND internal)
+		UNION ALL
+		SELECT milestone_id AS id, 'milestone' AS kind, '' AS key, title,
+		description AS details, project_id, due_date AS due, updated_at
+		FROM milestones WHERE project_id = ANY(?) AND due_date IS NOT NULL
+		ORDER BY due`)
+
+	if err := repo.SelectContext(ctx, &rows, q, uid, pq.Array(pids), client, pq.Array(pids)); err != nil {
+		return nil, errors.Wrap(err, "selecting calendar events")
+	}
+
+	for _, r := range rows {
+		e := Event{
+			UID:         fmt.Sprintf("%s-%s@%s", r.Kind, r.ID, domain),
+			Description: r.Details,
+			Date:        r.Due,
+			UpdatedAt:   r.UpdatedAt,
+		}
+		switch r.Kind {
+		case "task":
+			e.Summary = fmt.Sprintf("%s %s", r.Key, r.Title)
+		default:
+			e.Summary = fmt.Sprintf("%s: %s", names[r.ProjectID], r.Title)
+		}
+		es = append(es, e)
+	}
+
+	return es, nil
+}
+
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
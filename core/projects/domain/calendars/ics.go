@@ -0,0 +1,63 @@
+package calendars
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// escaper escapes text values as required by RFC 5545.
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// Render writes events as an iCalendar document. UIDs are derived from the task or
+// milestone id, so calendar apps update entries in place when a due date changes.
+func Render(w io.Writer, name string, es []Event, now time.Time) error {
+	bw := bufio.NewWriter(w)
+
+	line := func(s string) {
+		// lines longer than 75 octets are folded onto continuation lines
+		for len(s) > 75 {
+			cut := 75
+			for cut > 0 && !isBoundary(s, cut) {
+				cut--
+			}
+			bw.WriteString(s[:cut] + "\r\n ")
+			s = s[cut:]
+		}
+		bw.WriteString(s + "\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//devpie//projects//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escaper.Replace(name))
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, e := range es {
+		day := e.Date.UTC()
+		line("BEGIN:VEVENT")
+		line("UID:" + e.UID)
+		line("DTSTAMP:" + stamp)
+		line("LAST-MODIFIED:" + e.UpdatedAt.UTC().Format("20060102T150405Z"))
+		line("DTSTART;VALUE=DATE:" + day.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + day.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:" + escaper.Replace(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:" + escaper.Replace(e.Description))
+		}
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+
+	return bw.Flush()
+}
+
+// isBoundary reports whether i doesn't split a UTF-8 sequence.
+func isBoundary(s string, i int) bool {
+	return s[i]&0xC0 != 0x80
+}
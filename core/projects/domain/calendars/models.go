@@ -0,0 +1,23 @@
+package calendars
+
+import (
+	"time"
+)
+
+// Token grants read access to a user's calendar feed. The secret is only known when the
+// token is created, only its hash is stored.
+type Token struct {
+	UserID     string     `db:"user_id" json:"userId"`
+	Secret     string     `db:"-" json:"token,omitempty"`
+	LastUsedAt *time.Time `db:"last_used_at" json:"lastUsedAt"`
+	CreatedAt  time.Time  `db:"created_at" json:"createdAt"`
+}
+
+// Event is an all-day entry of a calendar feed.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Date        time.Time
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,250 @@
+package checklists
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrNotFound     = errors.New("checklist item not found")
+	ErrInvalidID    = errors.New("id provided was not a valid UUID")
+	ErrInvalidOrder = errors.New("order must list every item of the checklist once")
+)
+
+var columns = []string{
+	"item_id",
+	"task_id",
+	"project_id",
+	"position",
+	"text",
+	"done",
+	"assigned_to",
+	"created_by",
+	"updated_at",
+	"created_at",
+}
+
+// Create appends an item to the checklist of a task.
+func Create(ctx context.Context, repo database.Storer, ni NewItem, pid, tid, uid string, now time.Time) (Item, error) {
+	var i Item
+
+	if _, err := uuid.Parse(tid); err != nil {
+		return i, ErrInvalidID
+	}
+
+	i = Item{
+		ID:         uuid.New().String(),
+		TaskID:     tid,
+		ProjectID:  pid,
+		Text:       ni.Text,
+		AssignedTo: ni.AssignedTo,
+		CreatedBy:  uid,
+		UpdatedAt:  now.UTC(),
+		CreatedAt:  now.UTC(),
+	}
+
+	q := repo.Rebind(`INSERT INTO checklist_items (item_id, task_id, project_id, position, text, assigned_to,
+		created_by, updated_at, created_at)
+		SELECT ?, ?, ?, COALESCE(MAX(position), 0) + 1, ?, ?, ?, ?, ? FROM checklist_items WHERE task_id = ?
+		RETURNING position`)
+
+	if err := repo.QueryRowxContext(ctx, q, i.ID, i.TaskID, i.ProjectID, i.Text, i.AssignedTo, i.CreatedBy,
+		i.UpdatedAt, i.CreatedAt, tid).Scan(&i.Position); err != nil {
+		return i, errors.Wrapf(err, "inserting checklist item: %v", ni)
+	}
+
+	return i, nil
+}
+
+// List returns the checklist of a task in order.
+func List(ctx context.Context, repo database.Storer, tid string) ([]Item, error) {
+	var is = make([]Item, 0)
+
+	if _, err := uuid.Parse(tid); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	stmt := repo.Select(columns...).From("checklist_items").Where(sq.Eq{"task_id": "?"}).OrderBy("position")
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.SelectContext(ctx, &is, q, tid); err != nil {
+		return nil, errors.Wrap(err, "selecting checklist items")
+	}
+
+	return is, nil
+}
+
+// Retrieve returns an item of a task's checklist.
+func Retrieve(ctx context.Context, repo database.Storer, tid, iid string) (Item, error) {
+	var i Item
+
+	if _, err := uuid.Parse(iid); err != nil {
+		return i, ErrInvalidID
+	}
+
+	stmt := repo.Select(columns...).From("checklist_items").Where(sq.Eq{"task_id": "?", "item_id": "?"})
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return i, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.QueryRowxContext(ctx, q, tid, iid).StructScan(&i); err != nil {
+		if err == sql.ErrNoRows {
+			return i, ErrNotFound
+		}
+		return i, err
+	}
+
+	return i, nil
+}
+
+// Update edits the text, done flag or assignee of an item.
+func Update(ctx context.Context, repo database.Storer, tid, iid string, update UpdateItem, now time.Time) (Item, error) {
+	i, err := Retrieve(ctx, repo, tid, iid)
+	if err != nil {
+		return i, err
+	}
+
+	if update.Text != nil {
+		i.Text = *update.Text
+	}
+	if update.Done != nil {
+		i.Done = *update.Done
+	}
+	if update.AssignedTo != nil {
+		i.AssignedTo = *update.AssignedTo
+	}
+	i.UpdatedAt = now.UTC()
+
+	stmt := repo.Update(
+		"checklist_items",
+	).SetMap(map[string]interface{}{
+		"text":        i.Text,
+		"done":        i.Done,
+		"assigned_to": i.AssignedTo,
+		"updated_at":  i.UpdatedAt,
+	}).Where(sq.Eq{"task_id": tid, "item_id": iid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return i, errors.Wrapf(err, "updating checklist item: %s", iid)
+	}
+
+	return i, nil
+}
+
+// Toggle flips the done flag of an item.
+func Toggle(ctx context.Context, repo database.Storer, tid, iid string, now time.Time) (Item, error) {
+	i, err := Retrieve(ctx, repo, tid, iid)
+	if err != nil {
+		return i, err
+	}
+
+	done := !i.Done
+
+	return Update(ctx, repo, tid, iid, UpdateItem{Done: &done}, now)
+}
+
+// Reorder puts the items of a checklist in the given order.
+func Reorder(ctx context.Context, repo database.Storer, tid string, o Order, now time.Time) ([]Item, error) {
+	is, err := List(ctx, repo, tid)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(o.ItemIDs) != len(is) {
+		return nil, ErrInvalidOrder
+	}
+	known := make(map[string]bool, len(is))
+	for _, i := range is {
+		known[i.ID] = true
+	}
+	for _, id := range o.ItemIDs {
+		if !known[id] {
+			return nil, ErrInvalidOrder
+		}
+		delete(known, id)
+	}
+
+	tx, err := repo.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "beginning transaction")
+	}
+	defer tx.Rollback()
+
+	q := `UPDATE checklist_items SET position = $1, updated_at = $2 WHERE task_id = $3 AND item_id = $4`
+	for n, id := range o.ItemIDs {
+		if _, err := tx.ExecContext(ctx, q, n+1, now.UTC(), tid, id); err != nil {
+			return nil, errors.Wrapf(err, "moving checklist item %s", id)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "committing checklist order")
+	}
+
+	return List(ctx, repo, tid)
+}
+
+// Delete removes an item from a task's checklist.
+func Delete(ctx context.Context, repo database.Storer, tid, iid string) error {
+	if _, err := uuid.Parse(iid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"checklist_items",
+	).Where(sq.Eq{"task_id": tid, "item_id": iid})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "deleting checklist item %s", iid)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteByTask removes the checklist of a task.
+func DeleteByTask(ctx context.Context, repo database.Storer, tid string) error {
+	stmt := repo.Delete(
+		"checklist_items",
+	).Where(sq.Eq{"task_id": tid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting checklist of task %s", tid)
+	}
+
+	return nil
+}
+
+// DeleteAll removes every checklist item of a project.
+func DeleteAll(ctx context.Context, repo database.Storer, pid string) error {
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"checklist_items",
+	).Where(sq.Eq{"project_id": pid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting all checklist items")
+	}
+
+	return nil
+}
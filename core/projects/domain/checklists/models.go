@@ -0,0 +1,35 @@
+package checklists
+
+import (
+	"time"
+)
+
+// Item is an entry of a task's checklist.
+type Item struct {
+	ID         string    `db:"item_id" json:"id"`
+	TaskID     string    `db:"task_id" json:"taskId"`
+	ProjectID  string    `db:"project_id" json:"projectId"`
+	Position   int       `db:"position" json:"position"`
+	Text       string    `db:"text" json:"text"`
+	Done       bool      `db:"done" json:"done"`
+	AssignedTo string    `db:"assigned_to" json:"assignedTo"`
+	CreatedBy  string    `db:"created_by" json:"createdBy"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updatedAt"`
+	CreatedAt  time.Time `db:"created_at" json:"createdAt"`
+}
+
+type NewItem struct {
+	Text       string `json:"text" validate:"required"`
+	AssignedTo string `json:"assignedTo" validate:"omitempty,uuid"`
+}
+
+type UpdateItem struct {
+	Text       *string `json:"text" validate:"omitempty,min=1"`
+	Done       *bool   `json:"done"`
+	AssignedTo *string `json:"assignedTo" validate:"omitempty,uuid"`
+}
+
+// Order lists every item of a checklist in its new order.
+type Order struct {
+	ItemIDs []string `json:"itemIds" validate:"required,dive,uuid"`
+}
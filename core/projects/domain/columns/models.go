@@ -1,9 +1,13 @@
 package columns
 
 import (
+	"strings"
 	"time"
 )
 
+// Done is the title of the column holding finished tasks.
+const Done = "Done"
+
 type Column struct {
 	ID         string    `db:"column_id" json:"id"`
 	Title      string    `db:"title" json:"title"`
@@ -25,3 +29,8 @@ type UpdateColumn struct {
 	TaskIDS   *[]string `json:"taskIds"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
+
+// IsDone reports whether tasks in the column are finished.
+func (c Column) IsDone() bool {
+	return strings.EqualFold(c.Title, Done)
+}
@@ -0,0 +1,359 @@
+package customfields
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrNotFound       = errors.New("custom field not found")
+	ErrInvalidID      = errors.New("id provided was not a valid UUID")
+	ErrNameTaken      = errors.New("a custom field with this name already exists in the project")
+	ErrMissingOptions = errors.New("select fields need at least one option")
+)
+
+// maxText is the longest value a text field holds, in characters.
+const maxText = 1000
+
+// uniqueViolation is the Postgres error code for a broken unique constraint.
+const uniqueViolation = "23505"
+
+// fields lists the columns of a custom field.
+const fields = `field_id, project_id, name, type, options, position, created_by, updated_at, created_at`
+
+// Create adds a custom field after the existing fields of a project.
+func Create(ctx context.Context, repo database.Storer, nf NewField, pid, uid string, now time.Time) (Field, error) {
+	var f Field
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return f, ErrInvalidID
+	}
+
+	f = Field{
+		ID:        uuid.New().String(),
+		ProjectID: pid,
+		Name:      nf.Name,
+		Type:      nf.Type,
+		Options:   options(nf.Type, nf.Options),
+		CreatedBy: uid,
+		UpdatedAt: now.UTC(),
+		CreatedAt: now.UTC(),
+	}
+
+	if hasOptions(f.Type) && len(f.Options) == 0 {
+		return f, ErrMissingOptions
+	}
+
+	q := repo.Rebind(`INSERT INTO custom_fields (` + fields + `)
+		SELECT ?, ?, ?, ?, ?, COALESCE(MAX(position), 0) + 1, ?, ?, ? FROM custom_fields WHERE project_id = ?
+		RETURNING position`)
+
+	if err := repo.QueryRowxContext(ctx, q, f.ID, f.ProjectID, f.Name, f.Type, f.Options, f.CreatedBy,
+		f.UpdatedAt, f.CreatedAt, pid).Scan(&f.Position); err != nil {
+		if isUniqueViolation(err) {
+			return f, ErrNameTaken
+		}
+		return f, errors.Wrapf(err, "inserting custom field: %v", nf)
+	}
+
+	return f, nil
+}
+
+// List returns the custom fields of a project in order.
+func List(ctx context.Context, repo database.Storer, pid string) ([]Field, error) {
+	var fs = make([]Field, 0)
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	q := repo.Rebind(`SELECT ` + fields + ` FROM custom_fields WHERE project_id = ? ORDER BY position`)
+
+	if err := repo.SelectContext(ctx, &fs, q, pid); err != nil {
+		return nil, errors.Wrap(err, "selecting custom fields")
+	}
+
+	return fs, nil
+}
+
+// Retrieve returns a custom field of a project.
+func Retrieve(ctx context.Context, repo database.Storer, pid, fid string) (Field, error) {
+	var f Field
+
+	if _, err := uuid.Parse(fid); err != nil {
+		return f, ErrInvalidID
+	}
+
+	q := repo.Rebind(`SELECT ` + fields + ` FROM custom_fields WHERE project_id = ? AND field_id = ?`)
+
+	if err := repo.QueryRowxContext(ctx, q, pid, fid).StructScan(&f); err != nil {
+		if err == sql.ErrNoRows {
+			return f, ErrNotFound
+		}
+		return f, err
+	}
+
+	return f, nil
+}
+
+// Update renames a custom field or replaces its options. Values already stored on tasks are
+// kept even when their option is removed; they are checked again on the next change.
+func Update(ctx context.Context, repo database.Storer, pid, fid string, update UpdateField, now time.Time) (Field, error) {
+	f, err := Retrieve(ctx, repo, pid, fid)
+	if err != nil {
+		return f, err
+	}
+
+	if update.Name != nil {
+		f.Name = *update.Name
+	}
+	if update.Options != nil {
+		f.Options = options(f.Type, update.Options)
+		if hasOptions(f.Type) && len(f.Options) == 0 {
+			return f, ErrMissingOptions
+		}
+	}
+	f.UpdatedAt = now.UTC()
+
+	stmt := repo.Update(
+		"custom_fields",
+	).SetMap(map[string]interface{}{
+		"name":       f.Name,
+		"options":    f.Options,
+		"updated_at": f.UpdatedAt,
+	}).Where(sq.Eq{"project_id": pid, "field_id": fid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		if isUniqueViolation(err) {
+			return f, ErrNameTaken
+		}
+		return f, errors.Wrapf(err, "updating custom field: %s", fid)
+	}
+
+	return f, nil
+}
+
+// Delete removes a custom field of a project along with its values on every task.
+func Delete(ctx context.Context, repo database.Storer, pid, fid string) error {
+	if _, err := uuid.Parse(fid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"custom_fields",
+	).Where(sq.Eq{"project_id": pid, "field_id": fid})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "deleting custom field %s", fid)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+
+	strip := repo.Update(
+		"tasks",
+	).Set("custom_fields", sq.Expr("custom_fields - ?", fid)).Where(sq.Eq{"project_id": pid})
+
+	if _, err := strip.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "removing values of custom field %s", fid)
+	}
+
+	return nil
+}
+
+// DeleteAll removes every custom field of a project.
+func DeleteAll(ctx context.Context, repo database.Storer, pid string) error {
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"custom_fields",
+	).Where(sq.Eq{"project_id": pid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting all custom fields")
+	}
+
+	return nil
+}
+
+// Validate checks values keyed by field ID against the fields of a project and returns them
+// in their stored form: numbers as floats, dates as YYYY-MM-DD and multiselect values as
+// lists without duplicates. A nil value clears the field and is kept as is.
+func Validate(fs []Field, values map[string]interface{}) (map[string]interface{}, error) {
+	byID := make(map[string]Field, len(fs))
+	for _, f := range fs {
+		byID[f.ID] = f
+	}
+
+	out := make(map[string]interface{}, len(values))
+	for id, v := range values {
+		f, ok := byID[id]
+		if !ok {
+			return nil, errors.Errorf("unknown custom field %s", id)
+		}
+		if v == nil {
+			out[id] = nil
+			continue
+		}
+
+		nv, err := f.normalize(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value for %s", f.Name)
+		}
+		out[id] = nv
+	}
+
+	return out, nil
+}
+
+// Users returns the user IDs set on user fields.
+func Users(fs []Field, values map[string]interface{}) []string {
+	var ids []string
+	for _, f := range fs {
+		if s, ok := values[f.ID].(string); ok && f.Type == User {
+			ids = append(ids, s)
+		}
+	}
+	return ids
+}
+
+// Format returns the values set on a task in field order, formatted for display.
+func Format(fs []Field, values map[string]interface{}) []Value {
+	vs := make([]Value, 0)
+	for _, f := range fs {
+		v, ok := values[f.ID]
+		if !ok || v == nil {
+			continue
+		}
+
+		var s string
+		switch x := v.(type) {
+		case float64:
+			s = strconv.FormatFloat(x, 'f', -1, 64)
+		case []interface{}:
+			parts := make([]string, len(x))
+			for i, p := range x {
+				parts[i] = fmt.Sprint(p)
+			}
+			s = strings.Join(parts, ", ")
+		default:
+			s = fmt.Sprint(x)
+		}
+		vs = append(vs, Value{Name: f.Name, Value: s})
+	}
+	return vs
+}
+
+func (f Field) normalize(v interface{}) (interface{}, error) {
+	switch f.Type {
+	case Number:
+		n, ok := v.(float64)
+		if !ok {
+			return nil, errors.New("expected a number")
+		}
+		return n, nil
+	case Date:
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("expected a date")
+		}
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			if d, err = time.Parse(time.RFC3339, s); err != nil {
+				return nil, errors.New("expected a date as YYYY-MM-DD")
+			}
+		}
+		return d.Format("2006-01-02"), nil
+	case MultiSelect:
+		list, ok := v.([]interface{})
+		if !ok {
+			return nil, errors.New("expected a list of options")
+		}
+		seen := make(map[string]bool)
+		out := make([]interface{}, 0, len(list))
+		for _, item := range list {
+			s, ok := item.(string)
+			if !ok || !f.hasOption(s) {
+				return nil, errors.Errorf("%v is not an option", item)
+			}
+			if !seen[s] {
+				seen[s] = true
+				out = append(out, s)
+			}
+		}
+		return out, nil
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return nil, errors.New("expected a string")
+	}
+
+	switch f.Type {
+	case Select:
+		if !f.hasOption(s) {
+			return nil, errors.Errorf("%s is not an option", s)
+		}
+	case User:
+		if _, err := uuid.Parse(s); err != nil {
+			return nil, errors.New("expected a user id")
+		}
+	default:
+		if utf8.RuneCountInString(s) > maxText {
+			return nil, errors.Errorf("text is longer than %d characters", maxText)
+		}
+	}
+
+	return s, nil
+}
+
+func (f Field) hasOption(s string) bool {
+	for _, o := range f.Options {
+		if o == s {
+			return true
+		}
+	}
+	return false
+}
+
+func hasOptions(typ string) bool {
+	return typ == Select || typ == MultiSelect
+}
+
+// options drops duplicate options, and every option of fields that don't take any.
+func options(typ string, opts []string) pq.StringArray {
+	out := make(pq.StringArray, 0, len(opts))
+	if !hasOptions(typ) {
+		return out
+	}
+
+	seen := make(map[string]bool)
+	for _, o := range opts {
+		if !seen[o] {
+			seen[o] = true
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+func isUniqueViolation(err error) bool {
+	pqErr, ok := errors.Cause(err).(*pq.Error)
+	return ok && pqErr.Code == uniqueViolation
+}
@@ -0,0 +1,49 @@
+package customfields
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Types of custom fields.
+const (
+	Text        = "text"
+	Number      = "number"
+	Date        = "date"
+	Select      = "select"
+	MultiSelect = "multiselect"
+	User        = "user"
+)
+
+// Field is a custom field defined on the tasks of a project. Options lists the choices of
+// select and multiselect fields.
+type Field struct {
+	ID        string         `db:"field_id" json:"id"`
+	ProjectID string         `db:"project_id" json:"projectId"`
+	Name      string         `db:"name" json:"name"`
+	Type      string         `db:"type" json:"type"`
+	Options   pq.StringArray `db:"options" json:"options"`
+	Position  int            `db:"position" json:"position"`
+	CreatedBy string         `db:"created_by" json:"createdBy"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updatedAt"`
+	CreatedAt time.Time      `db:"created_at" json:"createdAt"`
+}
+
+type NewField struct {
+	Name    string   `json:"name" validate:"required,max=48"`
+	Type    string   `json:"type" validate:"required,oneof=text number date select multiselect user"`
+	Options []string `json:"options" validate:"omitempty,dive,min=1,max=64"`
+}
+
+// UpdateField renames a field or changes its options. The type of a field can't change.
+type UpdateField struct {
+	Name    *string  `json:"name" validate:"omitempty,min=1,max=48"`
+	Options []string `json:"options" validate:"omitempty,dive,min=1,max=64"`
+}
+
+// Value is a custom field value formatted for display.
+type Value struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
@@ -0,0 +1,178 @@
+package grants
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrNotFound  = errors.New("grant not found")
+	ErrInvalidID = errors.New("id provided was not a valid UUID")
+)
+
+// Create grants a user a role on a project. An existing grant for the same user is replaced.
+func Create(ctx context.Context, repo database.Storer, ng NewGrant, pid, uid string, now time.Time) (Grant, error) {
+	var g Grant
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return g, ErrInvalidID
+	}
+
+	g = Grant{
+		ID:         uuid.New().String(),
+		ProjectID:  pid,
+		UserID:     ng.UserID,
+		Role:       ng.Role,
+		Expiration: ng.Expiration,
+		CreatedBy:  uid,
+		UpdatedAt:  now.UTC(),
+		CreatedAt:  now.UTC(),
+	}
+
+	if g.Expiration != nil {
+		exp := g.Expiration.UTC()
+		g.Expiration = &exp
+	}
+
+	stmt := repo.Insert(
+		"grants",
+	).SetMap(map[string]interface{}{
+		"grant_id":   g.ID,
+		"project_id": g.ProjectID,
+		"user_id":    g.UserID,
+		"role":       g.Role,
+		"expiration": g.Expiration,
+		"created_by": g.CreatedBy,
+		"updated_at": g.UpdatedAt,
+		"created_at": g.CreatedAt,
+	}).Suffix(
+		"ON CONFLICT (project_id, user_id) DO UPDATE SET role = EXCLUDED.role, expiration = EXCLUDED.expiration, " +
+			"created_by = EXCLUDED.created_by, updated_at = EXCLUDED.updated_at RETURNING grant_id, created_at",
+	)
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return g, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.QueryRowxContext(ctx, q, args...).Scan(&g.ID, &g.CreatedAt); err != nil {
+		return g, errors.Wrapf(err, "inserting grant: %v", ng)
+	}
+
+	return g, nil
+}
+
+// List returns every grant on a project, including expired ones.
+func List(ctx context.Context, repo database.Storer, pid string) ([]Grant, error) {
+	var gs = make([]Grant, 0)
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	stmt := repo.Select(
+		"grant_id",
+		"project_id",
+		"user_id",
+		"role",
+		"expiration",
+		"created_by",
+		"updated_at",
+		"created_at",
+	).From(
+		"grants",
+	).Where(sq.Eq{"project_id": "?"}).OrderBy("created_at")
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.SelectContext(ctx, &gs, q, pid); err != nil {
+		return nil, errors.Wrap(err, "selecting grants")
+	}
+
+	return gs, nil
+}
+
+// RetrieveActive returns the unexpired grant a user holds on a project.
+func RetrieveActive(ctx context.Context, repo database.Storer, pid, uid string) (Grant, error) {
+	var g Grant
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return g, ErrInvalidID
+	}
+
+	stmt := repo.Select(
+		"grant_id",
+		"project_id",
+		"user_id",
+		"role",
+		"expiration",
+		"created_by",
+		"updated_at",
+		"created_at",
+	).From(
+		"grants",
+	).Where(sq.Eq{"project_id": "?", "user_id": "?"}).Where("(expiration IS NULL OR expiration > (NOW() AT TIME ZONE 'utc'))")
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return g, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.QueryRowxContext(ctx, q, pid, uid).StructScan(&g); err != nil {
+		if err == sql.ErrNoRows {
+			return g, ErrNotFound
+		}
+		return g, err
+	}
+
+	return g, nil
+}
+
+// Delete revokes a grant on a project.
+func Delete(ctx context.Context, repo database.Storer, pid, gid string) error {
+	if _, err := uuid.Parse(gid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"grants",
+	).Where(sq.Eq{"project_id": pid, "grant_id": gid})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "deleting grant %s", gid)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteAll revokes every grant on a project.
+func DeleteAll(ctx context.Context, repo database.Storer, pid string) error {
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"grants",
+	).Where(sq.Eq{"project_id": pid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting all grants")
+	}
+
+	return nil
+}
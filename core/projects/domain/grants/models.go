@@ -0,0 +1,21 @@
+package grants
+
+import "time"
+
+// Grant gives a user a role on a single project without a team membership.
+type Grant struct {
+	ID         string     `db:"grant_id" json:"id"`
+	ProjectID  string     `db:"project_id" json:"projectId"`
+	UserID     string     `db:"user_id" json:"userId"`
+	Role       string     `db:"role" json:"role"`
+	Expiration *time.Time `db:"expiration" json:"expiration"`
+	CreatedBy  string     `db:"created_by" json:"createdBy"`
+	UpdatedAt  time.Time  `db:"updated_at" json:"updatedAt"`
+	CreatedAt  time.Time  `db:"created_at" json:"createdAt"`
+}
+
+type NewGrant struct {
+	UserID     string     `json:"userId" validate:"required,uuid"`
+	Role       string     `json:"role" validate:"required,oneof=administrator editor commenter viewer"`
+	Expiration *time.Time `json:"expiration"`
+}
@@ -0,0 +1,316 @@
+package integrations
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrNotFound  = errors.New("integration not found")
+	ErrInvalidID = errors.New("id provided was not a valid UUID")
+)
+
+var columns = []string{
+	"integration_id",
+	"project_id",
+	"provider",
+	"secret",
+	"merge_column_id",
+	"created_by",
+	"updated_at",
+	"created_at",
+}
+
+var linkColumns = []string{
+	"link_id",
+	"task_id",
+	"project_id",
+	"integration_id",
+	"kind",
+	"url",
+	"title",
+	"ref",
+	"state",
+	"updated_at",
+	"created_at",
+}
+
+// Create connects a git repository to a project. The generated secret is configured on the
+// git host to sign or authenticate its deliveries.
+func Create(ctx context.Context, repo database.Storer, ni NewIntegration, pid, uid string, now time.Time) (Integration, error) {
+	var in Integration
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return in, ErrInvalidID
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return in, errors.Wrap(err, "generating integration secret")
+	}
+
+	in = Integration{
+		ID:            uuid.New().String(),
+		ProjectID:     pid,
+		Provider:      ni.Provider,
+		Secret:        hex.EncodeToString(b),
+		MergeColumnID: ni.MergeColumnID,
+		CreatedBy:     uid,
+		UpdatedAt:     now.UTC(),
+		CreatedAt:     now.UTC(),
+	}
+
+	stmt := repo.Insert(
+		"git_integrations",
+	).SetMap(map[string]interface{}{
+		"integration_id":  in.ID,
+		"project_id":      in.ProjectID,
+		"provider":        in.Provider,
+		"secret":          in.Secret,
+		"merge_column_id": in.MergeColumnID,
+		"created_by":      in.CreatedBy,
+		"updated_at":      in.UpdatedAt,
+		"created_at":      in.CreatedAt,
+	})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return in, errors.Wrapf(err, "inserting integration: %v", ni)
+	}
+
+	return in, nil
+}
+
+// List returns the integrations of a project without their secrets.
+func List(ctx context.Context, repo database.Storer, pid string) ([]Integration, error) {
+	var ins = make([]Integration, 0)
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	stmt := repo.Select(columns...).From("git_integrations").Where(sq.Eq{"project_id": "?"}).OrderBy("created_at")
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.SelectContext(ctx, &ins, q, pid); err != nil {
+		return nil, errors.Wrap(err, "selecting integrations")
+	}
+
+	for i := range ins {
+		ins[i].Secret = ""
+	}
+
+	return ins, nil
+}
+
+// Retrieve returns an integration with its secret.
+func Retrieve(ctx context.Context, repo database.Storer, iid string) (Integration, error) {
+	var in Integration
+
+	if _, err := uuid.Parse(iid); err != nil {
+		return in, ErrInvalidID
+	}
+
+	stmt := repo.Select(columns...).From("git_integrations").Where(sq.Eq{"integration_id": "?"})
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return in, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.QueryRowxContext(ctx, q, iid).StructScan(&in); err != nil {
+		if err == sql.ErrNoRows {
+			return in, ErrNotFound
+		}
+		return in, err
+	}
+
+	return in, nil
+}
+
+// Update changes the column tasks move to when a pull request mentioning them is merged.
+// An empty column id turns moving off.
+func Update(ctx context.Context, repo database.Storer, pid, iid string, update UpdateIntegration, now time.Time) (Integration, error) {
+	in, err := Retrieve(ctx, repo, iid)
+	if err != nil {
+		return in, err
+	}
+	if in.ProjectID != pid {
+		return in, ErrNotFound
+	}
+
+	if update.MergeColumnID != nil {
+		in.MergeColumnID = *update.MergeColumnID
+	}
+	in.UpdatedAt = now.UTC()
+
+	stmt := repo.Update(
+		"git_integrations",
+	).SetMap(map[string]interface{}{
+		"merge_column_id": in.MergeColumnID,
+		"updated_at":      in.UpdatedAt,
+	}).Where(sq.Eq{"project_id": pid, "integration_id": iid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return in, errors.Wrapf(err, "updating integration: %s", iid)
+	}
+
+	in.Secret = ""
+
+	return in, nil
+}
+
+// Delete disconnects a repository from a project. Links it created are kept.
+func Delete(ctx context.Context, repo database.Storer, pid, iid string) error {
+	if _, err := uuid.Parse(iid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"git_integrations",
+	).Where(sq.Eq{"project_id": pid, "integration_id": iid})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "deleting integration %s", iid)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteAll removes every integration and link of a project.
+func DeleteAll(ctx context.Context, repo database.Storer, pid string) error {
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	for _, table := range []string{"task_links", "git_integrations"} {
+		stmt := repo.Delete(table).Where(sq.Eq{"project_id": pid})
+
+		if _, err := stmt.ExecContext(ctx); err != nil {
+			return errors.Wrapf(err, "deleting all %s", table)
+		}
+	}
+
+	return nil
+}
+
+// Match returns the ids of a project's tasks with the given keys. Keys are compared
+// without regard to case.
+func Match(ctx context.Context, repo database.Storer, pid string, keys []string) ([]string, error) {
+	var ids = make([]string, 0)
+
+	upper := make([]string, len(keys))
+	for i, k := range keys {
+		upper[i] = strings.ToUpper(k)
+	}
+
+	q := repo.Rebind(`SELECT task_id FROM tasks WHERE project_id = ? AND UPPER(key) = ANY(?) ORDER BY seq`)
+
+	if err := repo.SelectContext(ctx, &ids, q, pid, pq.Array(upper)); err != nil {
+		return nil, errors.Wrap(err, "matching task keys")
+	}
+
+	return ids, nil
+}
+
+// Attach links a task to a commit, branch or pull request. Linking the same address again
+// refreshes its title and state.
+func Attach(ctx context.Context, repo database.Storer, in Integration, tid string, nl NewLink, now time.Time) (Link, error) {
+	l := Link{
+		ID:            uuid.New().String(),
+		TaskID:        tid,
+		ProjectID:     in.ProjectID,
+		IntegrationID: in.ID,
+		Kind:          nl.Kind,
+		URL:           nl.URL,
+		Title:         nl.Title,
+		Ref:           nl.Ref,
+		State:         nl.State,
+		UpdatedAt:     now.UTC(),
+		CreatedAt:     now.UTC(),
+	}
+
+	stmt := repo.Insert(
+		"task_links",
+	).SetMap(map[string]interface{}{
+		"link_id":        l.ID,
+		"task_id":        l.TaskID,
+		"project_id":     l.ProjectID,
+		"integration_id": l.IntegrationID,
+		"kind":           l.Kind,
+		"url":            l.URL,
+		"title":          l.Title,
+		"ref":            l.Ref,
+		"state":          l.State,
+		"updated_at":     l.UpdatedAt,
+		"created_at":     l.CreatedAt,
+	}).Suffix(
+		"ON CONFLICT (task_id, url) DO UPDATE SET title = EXCLUDED.title, " +
+			"state = EXCLUDED.state, updated_at = EXCLUDED.updated_at RETURNING link_id, created_at",
+	)
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return l, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.QueryRowxContext(ctx, q, args...).Scan(&l.ID, &l.CreatedAt); err != nil {
+		return l, errors.Wrapf(err, "linking task %s to %s", tid, nl.URL)
+	}
+
+	return l, nil
+}
+
+// Links returns the commits, branches and pull requests linked to a task, newest first.
+func Links(ctx context.Context, repo database.Storer, tid string) ([]Link, error) {
+	var ls = make([]Link, 0)
+
+	if _, err := uuid.Parse(tid); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	stmt := repo.Select(linkColumns...).From("task_links").Where(sq.Eq{"task_id": "?"}).OrderBy("created_at DESC")
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.SelectContext(ctx, &ls, q, tid); err != nil {
+		return nil, errors.Wrap(err, "selecting task links")
+	}
+
+	return ls, nil
+}
+
+// DeleteByTask removes the links of a task.
+func DeleteByTask(ctx context.Context, repo database.Storer, tid string) error {
+	stmt := repo.Delete(
+		"task_links",
+	).Where(sq.Eq{"task_id": tid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting links of task %s", tid)
+	}
+
+	return nil
+}
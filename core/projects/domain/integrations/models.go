@@ -0,0 +1,49 @@
+package integrations
+
+import (
+	"time"
+)
+
+// Integration receives the webhooks of a GitHub or GitLab repository for a project.
+type Integration struct {
+	ID            string    `db:"integration_id" json:"id"`
+	ProjectID     string    `db:"project_id" json:"projectId"`
+	Provider      string    `db:"provider" json:"provider"`
+	Secret        string    `db:"secret" json:"secret,omitempty"`
+	MergeColumnID string    `db:"merge_column_id" json:"mergeColumnId"`
+	CreatedBy     string    `db:"created_by" json:"createdBy"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updatedAt"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
+}
+
+type NewIntegration struct {
+	Provider      string `json:"provider" validate:"required,oneof=github gitlab"`
+	MergeColumnID string `json:"mergeColumnId" validate:"omitempty,uuid"`
+}
+
+type UpdateIntegration struct {
+	MergeColumnID *string `json:"mergeColumnId" validate:"omitempty,uuid"`
+}
+
+// Link is a commit, branch or pull request mentioning a task.
+type Link struct {
+	ID            string    `db:"link_id" json:"id"`
+	TaskID        string    `db:"task_id" json:"taskId"`
+	ProjectID     string    `db:"project_id" json:"projectId"`
+	IntegrationID string    `db:"integration_id" json:"integrationId"`
+	Kind          string    `db:"kind" json:"kind"`
+	URL           string    `db:"url" json:"url"`
+	Title         string    `db:"title" json:"title"`
+	Ref           string    `db:"ref" json:"ref"`
+	State         string    `db:"state" json:"state"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updatedAt"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
+}
+
+type NewLink struct {
+	Kind  string
+	URL   string
+	Title string
+	Ref   string
+	State string
+}
@@ -0,0 +1,52 @@
+package invoices
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+var document = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"money": money,
+	"hours": func(s int) string { return fmt.Sprintf("%.2f", float64(s)/3600) },
+	"date":  func(t time.Time) string { return t.Format("January 2, 2006") },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Invoice {{.Number}} - {{.ProjectName}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; color: #222; margin: 40px; }
+table { width: 100%; border-collapse: collapse; margin-top: 24px; }
+th, td { padding: 8px; border-bottom: 1px solid #ddd; text-align: left; }
+.num { text-align: right; }
+.draft { color: #c00; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>Invoice #{{.Number}}{{if eq .Status "draft"}} <span class="draft">(draft)</span>{{end}}</h1>
+<p>{{.ProjectName}}<br>
+Period: {{date .PeriodStart}} &ndash; {{date .PeriodEnd}}<br>
+{{with .FinalizedAt}}Issued: {{date .}}{{end}}</p>
+<table>
+<thead><tr><th>Description</th><th class="num">Hours</th><th class="num">Rate</th><th class="num">Amount</th></tr></thead>
+<tbody>
+{{range .Items}}<tr><td>{{.Description}}</td><td class="num">{{hours .Seconds}}</td><td class="num">{{money .Rate $.Currency}}</td><td class="num">{{money .Amount $.Currency}}</td></tr>
+{{end}}</tbody>
+<tfoot><tr><th colspan="3">Total</th><th class="num">{{money .Total .Currency}}</th></tr></tfoot>
+</table>
+</body>
+</html>
+`))
+
+// money formats an amount in cents.
+func money(cents int, currency string) string {
+	return fmt.Sprintf("%s %d.%02d", currency, cents/100, cents%100)
+}
+
+// Render writes an invoice as a printable HTML document.
+func Render(w io.Writer, i Invoice) error {
+	return document.Execute(w, i)
+}
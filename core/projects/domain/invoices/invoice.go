@@ -0,0 +1,455 @@
+package invoices
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrNotFound     = errors.New("invoice not found")
+	ErrInvalidID    = errors.New("id provided was not a valid UUID")
+	ErrNoRate       = errors.New("project has no hourly rate")
+	ErrNoEntries    = errors.New("no unbilled time in this period")
+	ErrNotDraft     = errors.New("invoice is already finalized")
+	ErrRateNotFound = errors.New("rate not found")
+	ErrBilled       = errors.New("project has finalized invoices")
+)
+
+// groupings maps line item groups to the column they aggregate on, and the description of the item.
+var groupings = map[string][2]string{
+	"task":  {"e.task_id", "MAX(COALESCE(t.key, '') || ' ' || t.title)"},
+	"label": {"e.label", "COALESCE(NULLIF(e.label, ''), 'Unlabeled')"},
+}
+
+const invoiceColumns = `i.invoice_id, i.project_id, p.name AS project_name, i.number, i.status, i.group_by,
+	i.period_start, i.period_end, i.currency, i.total, i.created_by, i.finalized_at, i.updated_at, i.created_at`
+
+// SetRate sets the hourly rate of a project, or of a member of the project when the rate has a UserID.
+func SetRate(ctx context.Context, repo database.Storer, nr NewRate, pid string, now time.Time) (Rate, error) {
+	var r Rate
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return r, ErrInvalidID
+	}
+
+	r = Rate{
+		ID:         uuid.New().String(),
+		ProjectID:  pid,
+		UserID:     nr.UserID,
+		HourlyRate: nr.HourlyRate,
+		UpdatedAt:  now.UTC(),
+		CreatedAt:  now.UTC(),
+	}
+
+	if r.UserID == "" {
+		r.Currency = nr.Currency
+		if r.Currency == "" {
+			r.Currency = "USD"
+		}
+	}
+
+	stmt := repo.Insert(
+		"rates",
+	).SetMap(map[string]interface{}{
+		"rate_id":     r.ID,
+		"project_id":  r.ProjectID,
+		"user_id":     r.UserID,
+		"hourly_rate": r.HourlyRate,
+		"currency":    r.Currency,
+		"updated_at":  r.UpdatedAt,
+		"created_at":  r.CreatedAt,
+	}).Suffix(
+		"ON CONFLICT (project_id, user_id) DO UPDATE SET hourly_rate = EXCLUDED.hourly_rate, " +
+			"currency = EXCLUDED.currency, updated_at = EXCLUDED.updated_at RETURNING rate_id, created_at",
+	)
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return r, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.QueryRowxContext(ctx, q, args...).Scan(&r.ID, &r.CreatedAt); err != nil {
+		return r, errors.Wrapf(err, "inserting rate: %v", nr)
+	}
+
+	return r, nil
+}
+
+// ListRates returns the project's rate followed by the member overrides.
+func ListRates(ctx context.Context, repo database.Storer, pid string) ([]Rate, error) {
+	var rs = make([]Rate, 0)
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	stmt := repo.Select(
+		"rate_id",
+		"project_id",
+		"user_id",
+		"hourly_rate",
+		"currency",
+		"updated_at",
+		"created_at",
+	).From(
+		"rates",
+	).Where(sq.Eq{"project_id": "?"}).OrderBy("user_id")
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.SelectContext(ctx, &rs, q, pid); err != nil {
+		return nil, errors.Wrap(err, "selecting rates")
+	}
+
+	return rs, nil
+}
+
+// DeleteRate removes a rate of a project.
+func DeleteRate(ctx context.Context, repo database.Storer, pid, rid string) error {
+	if _, err := uuid.Parse(rid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"rates",
+	).Where(sq.Eq{"project_id": pid, "rate_id": rid})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "deleting rate %s", rid)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrRateNotFound
+	}
+
+	return nil
+}
+
+// Generate drafts an invoice for the billable time of a project started within the period
+// that isn't on another invoice yet. The time stays reserved for the draft until the draft
+// is deleted.
+func Generate(ctx context.Context, repo database.Storer, ni NewInvoice, pid, uid string, now time.Time) (Invoice, error) {
+	var i Invoice
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return i, ErrInvalidID
+	}
+
+	i = Invoice{
+		ID:          uuid.New().String(),
+		ProjectID:   pid,
+		Status:      Draft,
+		GroupBy:     ni.GroupBy,
+		PeriodStart: ni.From.UTC(),
+		PeriodEnd:   ni.To.UTC(),
+		CreatedBy:   uid,
+		UpdatedAt:   now.UTC(),
+		CreatedAt:   now.UTC(),
+	}
+
+	if i.GroupBy == "" {
+		i.GroupBy = "task"
+	}
+
+	tx, err := repo.BeginTxx(ctx, nil)
+	if err != nil {
+		return i, errors.Wrap(err, "beginning transaction")
+	}
+	defer tx.Rollback()
+
+	// locking the project serializes invoice numbering and the reservation of its time
+	if err := tx.QueryRowxContext(ctx, `SELECT name FROM projects WHERE project_id = $1 FOR UPDATE`, pid).Scan(&i.ProjectName); err != nil {
+		if err == sql.ErrNoRows {
+			return i, ErrNotFound
+		}
+		return i, errors.Wrapf(err, "locking project %s", pid)
+	}
+
+	q := `SELECT currency FROM rates WHERE project_id = $1 AND user_id = ''`
+	if err := tx.QueryRowxContext(ctx, q, pid).Scan(&i.Currency); err != nil {
+		if err == sql.ErrNoRows {
+			return i, ErrNoRate
+		}
+		return i, errors.Wrap(err, "selecting project rate")
+	}
+
+	q = `SELECT COALESCE(MAX(number), 0) + 1 FROM invoices WHERE project_id = $1`
+	if err := tx.QueryRowxContext(ctx, q, pid).Scan(&i.Number); err != nil {
+		return i, errors.Wrap(err, "numbering invoice")
+	}
+
+	q = `INSERT INTO invoices (invoice_id, project_id, number, status, group_by, period_start, period_end, currency,
+		created_by, updated_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	if _, err := tx.ExecContext(ctx, q, i.ID, i.ProjectID, i.Number, i.Status, i.GroupBy, i.PeriodStart, i.PeriodEnd,
+		i.Currency, i.CreatedBy, i.UpdatedAt, i.CreatedAt); err != nil {
+		return i, errors.Wrapf(err, "inserting invoice: %v", ni)
+	}
+
+	q = `UPDATE time_entries SET invoice_id = $1 WHERE project_id = $2 AND invoice_id IS NULL AND billable
+		AND stopped_at IS NOT NULL AND started_at >= $3 AND started_at < $4`
+	res, err := tx.ExecContext(ctx, q, i.ID, pid, i.PeriodStart, i.PeriodEnd)
+	if err != nil {
+		return i, errors.Wrap(err, "reserving time entries")
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return i, ErrNoEntries
+	}
+
+	if err := price(ctx, tx, &i); err != nil {
+		return i, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return i, errors.Wrap(err, "committing invoice")
+	}
+
+	return i, nil
+}
+
+// price replaces the line items of an invoice with the time reserved for it, billing each
+// member at their own rate or at the project's rate.
+func price(ctx context.Context, tx *sqlx.Tx, i *Invoice) error {
+	g := groupings[i.GroupBy]
+
+	q := fmt.Sprintf(`SELECT %s AS description, COALESCE(r.hourly_rate, d.hourly_rate, 0) AS rate, SUM(e.duration) AS seconds
+		FROM time_entries e
+		JOIN tasks t ON t.task_id = e.task_id
+		LEFT JOIN rates r ON r.project_id = e.project_id AND r.user_id = e.user_id
+		LEFT JOIN rates d ON d.project_id = e.project_id AND d.user_id = ''
+		WHERE e.invoice_id = $1
+		GROUP BY %s, COALESCE(r.hourly_rate, d.hourly_rate, 0)
+		ORDER BY description, rate DESC`, g[1], g[0])
+
+	var items []Item
+	if err := tx.SelectContext(ctx, &items, q, i.ID); err != nil {
+		return errors.Wrapf(err, "pricing invoice %s", i.ID)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM invoice_items WHERE invoice_id = $1`, i.ID); err != nil {
+		return errors.Wrapf(err, "deleting items of invoice %s", i.ID)
+	}
+
+	i.Total = 0
+	i.Items = make([]Item, 0, len(items))
+
+	for n, it := range items {
+		it.ID = uuid.New().String()
+		it.Position = n + 1
+		// amounts are rounded to the nearest cent
+		it.Amount = int((int64(it.Seconds)*int64(it.Rate) + 1800) / 3600)
+
+		q = `INSERT INTO invoice_items (item_id, invoice_id, position, description, seconds, rate, amount)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`
+		if _, err := tx.ExecContext(ctx, q, it.ID, i.ID, it.Position, it.Description, it.Seconds, it.Rate, it.Amount); err != nil {
+			return errors.Wrapf(err, "inserting item of invoice %s", i.ID)
+		}
+
+		i.Total += it.Amount
+		i.Items = append(i.Items, it)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE invoices SET total = $1, updated_at = $2 WHERE invoice_id = $3`,
+		i.Total, i.UpdatedAt, i.ID); err != nil {
+		return errors.Wrapf(err, "updating total of invoice %s", i.ID)
+	}
+
+	return nil
+}
+
+// List returns the invoices of a project without their items, most recent first.
+func List(ctx context.Context, repo database.Storer, pid string) ([]Invoice, error) {
+	var is = make([]Invoice, 0)
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	q := `SELECT ` + invoiceColumns + ` FROM invoices i JOIN projects p ON p.project_id = i.project_id
+		WHERE i.project_id = $1 ORDER BY i.number DESC`
+
+	if err := repo.SelectContext(ctx, &is, q, pid); err != nil {
+		return nil, errors.Wrap(err, "selecting invoices")
+	}
+
+	return is, nil
+}
+
+// Retrieve returns an invoice of a project with its items.
+func Retrieve(ctx context.Context, repo database.Storer, pid, iid string) (Invoice, error) {
+	var i Invoice
+
+	if _, err := uuid.Parse(iid); err != nil {
+		return i, ErrInvalidID
+	}
+
+	q := `SELECT ` + invoiceColumns + ` FROM invoices i JOIN projects p ON p.project_id = i.project_id
+		WHERE i.project_id = $1 AND i.invoice_id = $2`
+
+	if err := repo.QueryRowxContext(ctx, q, pid, iid).StructScan(&i); err != nil {
+		if err == sql.ErrNoRows {
+			return i, ErrNotFound
+		}
+		return i, err
+	}
+
+	i.Items = make([]Item, 0)
+
+	q = `SELECT item_id, position, description, seconds, rate, amount FROM invoice_items
+		WHERE invoice_id = $1 ORDER BY position`
+
+	if err := repo.SelectContext(ctx, &i.Items, q, iid); err != nil {
+		return i, errors.Wrapf(err, "selecting items of invoice %s", iid)
+	}
+
+	return i, nil
+}
+
+// Finalize prices a draft again with its current time and rates, then locks the invoice and its
+// time entries. Entries that were made non-billable since the draft are released.
+func Finalize(ctx context.Context, repo database.Storer, pid, iid string, now time.Time) (Invoice, error) {
+	i, err := Retrieve(ctx, repo, pid, iid)
+	if err != nil {
+		return i, err
+	}
+
+	if i.Status != Draft {
+		return i, ErrNotDraft
+	}
+
+	tx, err := repo.BeginTxx(ctx, nil)
+	if err != nil {
+		return i, errors.Wrap(err, "beginning transaction")
+	}
+	defer tx.Rollback()
+
+	q := `UPDATE invoices SET status = $1 WHERE invoice_id = $2 AND status = $3`
+	res, err := tx.ExecContext(ctx, q, Finalized, iid, Draft)
+	if err != nil {
+		return i, errors.Wrapf(err, "finalizing invoice %s", iid)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return i, ErrNotDraft
+	}
+
+	q = `UPDATE time_entries SET invoice_id = NULL WHERE invoice_id = $1 AND NOT billable`
+	if _, err := tx.ExecContext(ctx, q, iid); err != nil {
+		return i, errors.Wrapf(err, "releasing time entries of invoice %s", iid)
+	}
+
+	i.UpdatedAt = now.UTC()
+	if err := price(ctx, tx, &i); err != nil {
+		return i, err
+	}
+
+	q = `UPDATE time_entries SET locked = TRUE WHERE invoice_id = $1`
+	if _, err := tx.ExecContext(ctx, q, iid); err != nil {
+		return i, errors.Wrapf(err, "locking time entries of invoice %s", iid)
+	}
+
+	fin := now.UTC()
+	i.Status = Finalized
+	i.FinalizedAt = &fin
+
+	q = `UPDATE invoices SET finalized_at = $1 WHERE invoice_id = $2`
+	if _, err := tx.ExecContext(ctx, q, i.FinalizedAt, iid); err != nil {
+		return i, errors.Wrapf(err, "finalizing invoice %s", iid)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return i, errors.Wrap(err, "committing invoice")
+	}
+
+	return i, nil
+}
+
+// Delete removes a draft and releases its time.
+func Delete(ctx context.Context, repo database.Storer, pid, iid string) error {
+	i, err := Retrieve(ctx, repo, pid, iid)
+	if err != nil {
+		return err
+	}
+
+	if i.Status != Draft {
+		return ErrNotDraft
+	}
+
+	tx, err := repo.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "beginning transaction")
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM invoices WHERE invoice_id = $1 AND status = $2`, iid, Draft)
+	if err != nil {
+		return errors.Wrapf(err, "deleting invoice %s", iid)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotDraft
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE time_entries SET invoice_id = NULL WHERE invoice_id = $1`, iid); err != nil {
+		return errors.Wrapf(err, "releasing time entries of invoice %s", iid)
+	}
+
+	return tx.Commit()
+}
+
+// CheckBilled returns ErrBilled when a project has finalized invoices or time locked by them.
+// Billed work must be kept, so such a project can't be deleted.
+func CheckBilled(ctx context.Context, repo database.Storer, pid string) error {
+	var billed bool
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	q := repo.Rebind(`SELECT EXISTS (SELECT 1 FROM invoices WHERE project_id = ? AND status <> ?)
+		OR EXISTS (SELECT 1 FROM time_entries WHERE project_id = ? AND locked)`)
+
+	if err := repo.QueryRowxContext(ctx, q, pid, Draft, pid).Scan(&billed); err != nil {
+		return errors.Wrapf(err, "looking for finalized invoices of project %s", pid)
+	}
+	if billed {
+		return ErrBilled
+	}
+
+	return nil
+}
+
+// DeleteAll removes every invoice and rate of a project.
+func DeleteAll(ctx context.Context, repo database.Storer, pid string) error {
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"invoices",
+	).Where(sq.Eq{"project_id": pid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting all invoices")
+	}
+
+	stmt = repo.Delete(
+		"rates",
+	).Where(sq.Eq{"project_id": pid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting all rates")
+	}
+
+	return nil
+}
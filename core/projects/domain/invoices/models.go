@@ -0,0 +1,63 @@
+package invoices
+
+import "time"
+
+// Invoice statuses.
+const (
+	Draft     = "draft"
+	Finalized = "finalized"
+)
+
+// Rate is an hourly rate in cents. The project's rate has no UserID and sets the currency
+// of its invoices; rates with a UserID override it for that member.
+type Rate struct {
+	ID         string    `db:"rate_id" json:"id"`
+	ProjectID  string    `db:"project_id" json:"projectId"`
+	UserID     string    `db:"user_id" json:"userId,omitempty"`
+	HourlyRate int       `db:"hourly_rate" json:"hourlyRate"`
+	Currency   string    `db:"currency" json:"currency,omitempty"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updatedAt"`
+	CreatedAt  time.Time `db:"created_at" json:"createdAt"`
+}
+
+type NewRate struct {
+	UserID     string `json:"userId" validate:"omitempty,uuid"`
+	HourlyRate int    `json:"hourlyRate" validate:"min=0"`
+	Currency   string `json:"currency" validate:"omitempty,len=3"`
+}
+
+// Invoice bills the billable time of a project within a period. Amounts are in cents.
+type Invoice struct {
+	ID          string     `db:"invoice_id" json:"id"`
+	ProjectID   string     `db:"project_id" json:"projectId"`
+	ProjectName string     `db:"project_name" json:"projectName"`
+	Number      int        `db:"number" json:"number"`
+	Status      string     `db:"status" json:"status"`
+	GroupBy     string     `db:"group_by" json:"groupBy"`
+	PeriodStart time.Time  `db:"period_start" json:"periodStart"`
+	PeriodEnd   time.Time  `db:"period_end" json:"periodEnd"`
+	Currency    string     `db:"currency" json:"currency"`
+	Total       int        `db:"total" json:"total"`
+	CreatedBy   string     `db:"created_by" json:"createdBy"`
+	FinalizedAt *time.Time `db:"finalized_at" json:"finalizedAt"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updatedAt"`
+	CreatedAt   time.Time  `db:"created_at" json:"createdAt"`
+	Items       []Item     `db:"-" json:"items,omitempty"`
+}
+
+// NewInvoice drafts an invoice for the time started within [From, To).
+type NewInvoice struct {
+	From    time.Time `json:"from" validate:"required"`
+	To      time.Time `json:"to" validate:"required,gtfield=From"`
+	GroupBy string    `json:"groupBy" validate:"omitempty,oneof=task label"`
+}
+
+// Item is a line of an invoice: the time of a task or label billed at one rate.
+type Item struct {
+	ID          string `db:"item_id" json:"id"`
+	Position    int    `db:"position" json:"position"`
+	Description string `db:"description" json:"description"`
+	Seconds     int    `db:"seconds" json:"seconds"`
+	Rate        int    `db:"rate" json:"rate"`
+	Amount      int    `db:"amount" json:"amount"`
+}
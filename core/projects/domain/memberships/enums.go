@@ -0,0 +1,34 @@
+package memberships
+
+import "github.com/pkg/errors"
+
+// ErrInvalidRole is returned when a role does not match any known role.
+var ErrInvalidRole = errors.New("role provided was not a valid role")
+
+// Role type for enumerated values
+type Role int
+
+// Roles
+const (
+	Administrator Role = iota
+	Editor
+	Commenter
+	Viewer
+)
+
+var roles = [...]string{"administrator", "editor", "commenter", "viewer"}
+
+// String retrieves the corresponding string value for a role
+func (r Role) String() string {
+	return roles[r]
+}
+
+// ParseRole converts a stored role value into a Role
+func ParseRole(s string) (Role, error) {
+	for i, v := range roles {
+		if v == s {
+			return Role(i), nil
+		}
+	}
+	return Viewer, ErrInvalidRole
+}
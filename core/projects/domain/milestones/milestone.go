@@ -0,0 +1,257 @@
+package milestones
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/columns"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrNotFound  = errors.New("milestone not found")
+	ErrInvalidID = errors.New("id provided was not a valid UUID")
+)
+
+// done matches the tasks sitting in their project's done column. It expects the lowercase
+// title of the done column as its argument.
+const done = `EXISTS (SELECT 1 FROM columns c WHERE c.project_id = t.project_id
+	AND LOWER(c.title) = ? AND t.task_id = ANY(c.task_ids))`
+
+// progress selects milestones with the number of attached tasks and finished tasks. After
+// the done column title, it expects whether internal tasks are left out.
+const progress = `SELECT m.milestone_id, m.project_id, m.title, m.description, m.due_date, m.created_by,
+	m.updated_at, m.created_at, COUNT(t.task_id) AS total, COUNT(t.task_id) FILTER (WHERE ` + done + `) AS completed
+	FROM milestones m LEFT JOIN tasks t ON t.milestone_id = m.milestone_id AND NOT (? AND t.internal)`
+
+// Create adds a milestone to a project.
+func Create(ctx context.Context, repo database.Storer, nm NewMilestone, pid, uid string, now time.Time) (Milestone, error) {
+	var m Milestone
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return m, ErrInvalidID
+	}
+
+	m = Milestone{
+		ID:          uuid.New().String(),
+		ProjectID:   pid,
+		Title:       nm.Title,
+		Description: nm.Description,
+		DueDate:     nm.DueDate,
+		CreatedBy:   uid,
+		UpdatedAt:   now.UTC(),
+		CreatedAt:   now.UTC(),
+	}
+
+	if m.DueDate != nil {
+		due := m.DueDate.UTC()
+		m.DueDate = &due
+	}
+
+	stmt := repo.Insert(
+		"milestones",
+	).SetMap(map[string]interface{}{
+		"milestone_id": m.ID,
+		"project_id":   m.ProjectID,
+		"title":        m.Title,
+		"description":  m.Description,
+		"due_date":     m.DueDate,
+		"created_by":   m.CreatedBy,
+		"updated_at":   m.UpdatedAt,
+		"created_at":   m.CreatedAt,
+	})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return m, errors.Wrapf(err, "inserting milestone: %v", nm)
+	}
+
+	return m, nil
+}
+
+// List returns the milestones of a project with their progress, earliest due first. For
+// clients, progress only counts the tasks they can see.
+func List(ctx context.Context, repo database.Storer, pid string, client bool) ([]Milestone, error) {
+	var ms = make([]Milestone, 0)
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	q := repo.Rebind(progress + ` WHERE m.project_id = ? GROUP BY m.milestone_id
+		ORDER BY m.due_date NULLS LAST, m.created_at`)
+
+	if err := repo.SelectContext(ctx, &ms, q, strings.ToLower(columns.Done), client, pid); err != nil {
+		return nil, errors.Wrap(err, "selecting milestones")
+	}
+
+	for i := range ms {
+		ms[i].Progress = percent(ms[i].Completed, ms[i].Total)
+	}
+
+	return ms, nil
+}
+
+// Retrieve returns a milestone of a project with its progress. For clients, progress only
+// counts the tasks they can see.
+func Retrieve(ctx context.Context, repo database.Storer, pid, mid string, client bool) (Milestone, error) {
+	var m Milestone
+
+	if _, err := uuid.Parse(mid); err != nil {
+		return m, ErrInvalidID
+	}
+
+	q := repo.Rebind(progress + ` WHERE m.project_id = ? AND m.milestone_id = ? GROUP BY m.milestone_id`)
+
+	if err := repo.QueryRowxContext(ctx, q, strings.ToLower(columns.Done), client, pid, mid).StructScan(&m); err != nil {
+		if err == sql.ErrNoRows {
+			return m, ErrNotFound
+		}
+		return m, err
+	}
+
+	m.Progress = percent(m.Completed, m.Total)
+
+	return m, nil
+}
+
+// Timeline returns the milestones of a project in due date order, each with its status and
+// attached tasks. Milestones without a due date come last. Clients don't see internal tasks.
+func Timeline(ctx context.Context, repo database.Storer, pid string, client bool, now time.Time) ([]Stage, error) {
+	ms, err := List(ctx, repo, pid, client)
+	if err != nil {
+		return nil, err
+	}
+
+	var ts []TaskSummary
+
+	q := repo.Rebind(`SELECT t.task_id, COALESCE(t.key, '') AS key, t.title, t.milestone_id, t.internal, ` + done + ` AS done
+		FROM tasks t WHERE t.project_id = ? AND t.milestone_id <> '' AND NOT (? AND t.internal) ORDER BY t.seq`)
+
+	if err := repo.SelectContext(ctx, &ts, q, strings.ToLower(columns.Done), pid, client); err != nil {
+		return nil, errors.Wrap(err, "selecting milestone tasks")
+	}
+
+	attached := make(map[string][]TaskSummary)
+	for _, t := range ts {
+		attached[t.MilestoneID] = append(attached[t.MilestoneID], t)
+	}
+
+	ss := make([]Stage, 0, len(ms))
+	for _, m := range ms {
+		s := Stage{Milestone: m, Status: status(m, now), Tasks: attached[m.ID]}
+		if s.Tasks == nil {
+			s.Tasks = make([]TaskSummary, 0)
+		}
+		ss = append(ss, s)
+	}
+
+	return ss, nil
+}
+
+// Update modifies a milestone of a project. Its progress is counted as Retrieve does.
+func Update(ctx context.Context, repo database.Storer, pid, mid string, update UpdateMilestone, client bool, now time.Time) (Milestone, error) {
+	m, err := Retrieve(ctx, repo, pid, mid, client)
+	if err != nil {
+		return m, err
+	}
+
+	if update.Title != nil {
+		m.Title = *update.Title
+	}
+	if update.Description != nil {
+		m.Description = *update.Description
+	}
+	if update.DueDate != nil {
+		due := update.DueDate.UTC()
+		m.DueDate = &due
+	}
+	m.UpdatedAt = now.UTC()
+
+	stmt := repo.Update(
+		"milestones",
+	).SetMap(map[string]interface{}{
+		"title":       m.Title,
+		"description": m.Description,
+		"due_date":    m.DueDate,
+		"updated_at":  m.UpdatedAt,
+	}).Where(sq.Eq{"project_id": pid, "milestone_id": mid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return m, errors.Wrapf(err, "updating milestone: %s", mid)
+	}
+
+	return m, nil
+}
+
+// Delete removes a milestone of a project and detaches its tasks.
+func Delete(ctx context.Context, repo database.Storer, pid, mid string) error {
+	if _, err := uuid.Parse(mid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"milestones",
+	).Where(sq.Eq{"project_id": pid, "milestone_id": mid})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "deleting milestone %s", mid)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+
+	detach := repo.Update(
+		"tasks",
+	).Set("milestone_id", "").Where(sq.Eq{"milestone_id": mid})
+
+	if _, err := detach.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "detaching tasks from milestone %s", mid)
+	}
+
+	return nil
+}
+
+// DeleteAll removes every milestone of a project.
+func DeleteAll(ctx context.Context, repo database.Storer, pid string) error {
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"milestones",
+	).Where(sq.Eq{"project_id": pid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting all milestones")
+	}
+
+	return nil
+}
+
+func percent(n, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return n * 100 / total
+}
+
+func status(m Milestone, now time.Time) string {
+	switch {
+	case m.Total > 0 && m.Completed == m.Total:
+		return Completed
+	case m.DueDate == nil:
+		return Unscheduled
+	case m.DueDate.Before(now):
+		return Overdue
+	default:
+		return Upcoming
+	}
+}
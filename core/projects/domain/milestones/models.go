@@ -0,0 +1,56 @@
+package milestones
+
+import "time"
+
+// Milestone statuses on the timeline.
+const (
+	Completed   = "completed"
+	Overdue     = "overdue"
+	Upcoming    = "upcoming"
+	Unscheduled = "unscheduled"
+)
+
+// Milestone is a deliverable of a project. Total and Completed count its attached tasks, and
+// Progress is the percentage of them that are done.
+type Milestone struct {
+	ID          string     `db:"milestone_id" json:"id"`
+	ProjectID   string     `db:"project_id" json:"projectId"`
+	Title       string     `db:"title" json:"title"`
+	Description string     `db:"description" json:"description"`
+	DueDate     *time.Time `db:"due_date" json:"dueDate"`
+	CreatedBy   string     `db:"created_by" json:"createdBy"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updatedAt"`
+	CreatedAt   time.Time  `db:"created_at" json:"createdAt"`
+	Total       int        `db:"total" json:"total"`
+	Completed   int        `db:"completed" json:"completed"`
+	Progress    int        `db:"-" json:"progress"`
+}
+
+type NewMilestone struct {
+	Title       string     `json:"title" validate:"required,max=64"`
+	Description string     `json:"description"`
+	DueDate     *time.Time `json:"dueDate"`
+}
+
+type UpdateMilestone struct {
+	Title       *string    `json:"title" validate:"omitempty,max=64"`
+	Description *string    `json:"description"`
+	DueDate     *time.Time `json:"dueDate"`
+}
+
+// Stage is a milestone on the project timeline with the tasks attached to it.
+type Stage struct {
+	Milestone
+	Status string        `json:"status"`
+	Tasks  []TaskSummary `json:"tasks"`
+}
+
+// TaskSummary is a task attached to a milestone.
+type TaskSummary struct {
+	ID          string `db:"task_id" json:"id"`
+	Key         string `db:"key" json:"key"`
+	Title       string `db:"title" json:"title"`
+	MilestoneID string `db:"milestone_id" json:"-"`
+	Done        bool   `db:"done" json:"done"`
+	Internal    bool   `db:"internal" json:"-"`
+}
@@ -0,0 +1,62 @@
+package permissions
+
+import "github.com/devpies/devpie-client-core/projects/domain/memberships"
+
+// Action is an operation a user may attempt on a project or its resources.
+type Action int
+
+// Actions
+const (
+	ViewProject Action = iota
+	UpdateProject
+	DeleteProject
+	CreateTask
+	UpdateTask
+	MoveTask
+	DeleteTask
+	Comment
+	ManageGrants
+	ManageWebhooks
+	ManageBilling
+	ManageFields
+)
+
+// Scope identifies the project resources named by the route parameters of a request.
+type Scope struct {
+	ProjectID string
+	ColumnID  string
+	TaskID    string
+}
+
+// matrix lists the roles allowed to perform each action.
+//
+//	action         administrator  editor  commenter  viewer
+//	ViewProject    yes            yes     yes        yes
+//	UpdateProject  yes            yes     no         no
+//	DeleteProject  yes            no      no         no
+//	CreateTask     yes            yes     no         no
+//	UpdateTask     yes            yes     no         no
+//	MoveTask       yes            yes     no         no
+//	DeleteTask     yes            yes     no         no
+//	Comment        yes            yes     yes        no
+//	ManageGrants   yes            no      no         no
+//	ManageWebhooks yes            no      no         no
+//	ManageBilling  yes            no      no         no
+//	ManageFields   yes            no      no         no
+//
+// The project owner is always treated as an administrator. A project grant replaces
+// the role a user holds through the project's team.
+var matrix = map[Action][]memberships.Role{
+	ViewProject:    {memberships.Administrator, memberships.Editor, memberships.Commenter, memberships.Viewer},
+	UpdateProject:  {memberships.Administrator, memberships.Editor},
+	DeleteProject:  {memberships.Administrator},
+	CreateTask:     {memberships.Administrator, memberships.Editor},
+	UpdateTask:     {memberships.Administrator, memberships.Editor},
+	MoveTask:       {memberships.Administrator, memberships.Editor},
+	DeleteTask:     {memberships.Administrator, memberships.Editor},
+	Comment:        {memberships.Administrator, memberships.Editor, memberships.Commenter},
+	ManageGrants:   {memberships.Administrator},
+	ManageWebhooks: {memberships.Administrator},
+	ManageBilling:  {memberships.Administrator},
+	ManageFields:   {memberships.Administrator},
+}
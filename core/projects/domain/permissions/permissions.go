@@ -0,0 +1,159 @@
+package permissions
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/columns"
+	"github.com/devpies/devpie-client-core/projects/domain/grants"
+	"github.com/devpies/devpie-client-core/projects/domain/memberships"
+	"github.com/devpies/devpie-client-core/projects/domain/tasks"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrNotFound   = errors.New("project not found")
+	ErrInvalidID  = errors.New("id provided was not a valid UUID")
+	ErrForbidden  = errors.New("user does not have permission to perform this action")
+	ErrNoResource = errors.New("no project resource was provided")
+)
+
+// Allowed reports whether the role may perform the action.
+func Allowed(role memberships.Role, action Action) bool {
+	for _, r := range matrix[action] {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveProject returns the id of the project owning every resource in the scope.
+// Resources that belong to different projects are reported as not found.
+func ResolveProject(ctx context.Context, repo *database.Repository, s Scope) (string, error) {
+	var ids []string
+
+	if s.ProjectID != "" {
+		if _, err := uuid.Parse(s.ProjectID); err != nil {
+			return "", ErrInvalidID
+		}
+		ids = append(ids, s.ProjectID)
+	}
+
+	if s.ColumnID != "" {
+		c, err := columns.Retrieve(ctx, repo, s.ColumnID)
+		if err != nil {
+			switch err {
+			case columns.ErrInvalidID:
+				return "", ErrInvalidID
+			case columns.ErrNotFound:
+				return "", ErrNotFound
+			default:
+				return "", errors.Wrapf(err, "resolving column %q", s.ColumnID)
+			}
+		}
+		ids = append(ids, c.ProjectID)
+	}
+
+	if s.TaskID != "" {
+		t, err := tasks.Retrieve(ctx, repo, s.TaskID)
+		if err != nil {
+			switch err {
+			case tasks.ErrInvalidID:
+				return "", ErrInvalidID
+			case tasks.ErrNotFound:
+				return "", ErrNotFound
+			default:
+				return "", errors.Wrapf(err, "resolving task %q", s.TaskID)
+			}
+		}
+		ids = append(ids, t.ProjectID)
+	}
+
+	if len(ids) == 0 {
+		return "", ErrNoResource
+	}
+
+	for _, id := range ids[1:] {
+		if id != ids[0] {
+			return "", ErrNotFound
+		}
+	}
+
+	return ids[0], nil
+}
+
+// RetrieveRole returns the role a user holds on a project. The project owner is an
+// administrator, an unexpired project grant comes next and everyone else inherits the
+// role of their membership in the project's team.
+func RetrieveRole(ctx context.Context, repo database.Storer, pid, uid string) (memberships.Role, error) {
+	var owner, teamID string
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return memberships.Viewer, ErrInvalidID
+	}
+
+	q := `SELECT user_id, COALESCE(team_id, '') FROM projects WHERE project_id = $1`
+
+	if err := repo.QueryRowxContext(ctx, q, pid).Scan(&owner, &teamID); err != nil {
+		if err == sql.ErrNoRows {
+			return memberships.Viewer, ErrNotFound
+		}
+		return memberships.Viewer, errors.Wrapf(err, "selecting project %q", pid)
+	}
+
+	if owner == uid {
+		return memberships.Administrator, nil
+	}
+
+	// a project grant overrides the role inherited from the team
+	g, err := grants.RetrieveActive(ctx, repo, pid, uid)
+	if err != nil && err != grants.ErrNotFound {
+		return memberships.Viewer, err
+	}
+	if err == nil {
+		return parseRole(g.Role)
+	}
+
+	if teamID == "" {
+		return memberships.Viewer, ErrForbidden
+	}
+
+	m, err := memberships.Retrieve(ctx, repo, uid, teamID)
+	if err != nil {
+		switch err {
+		case memberships.ErrNotFound, memberships.ErrInvalidID:
+			return memberships.Viewer, ErrForbidden
+		default:
+			return memberships.Viewer, err
+		}
+	}
+
+	return parseRole(m.Role)
+}
+
+// parseRole converts a stored role, refusing access when it is not recognized.
+func parseRole(s string) (memberships.Role, error) {
+	role, err := memberships.ParseRole(s)
+	if err != nil {
+		return memberships.Viewer, ErrForbidden
+	}
+	return role, nil
+}
+
+// Authorize verifies that the user's role on the project permits the action.
+func Authorize(ctx context.Context, repo database.Storer, pid, uid string, action Action) (memberships.Role, error) {
+	role, err := RetrieveRole(ctx, repo, pid, uid)
+	if err != nil {
+		return role, err
+	}
+
+	if !Allowed(role, action) {
+		return role, ErrForbidden
+	}
+
+	return role, nil
+}
@@ -0,0 +1,46 @@
+package permissions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/devpies/devpie-client-core/projects/domain/memberships"
+)
+
+func TestAllowed(t *testing.T) {
+	roles := []memberships.Role{memberships.Administrator, memberships.Editor, memberships.Commenter, memberships.Viewer}
+
+	testcases := []struct {
+		name   string
+		action Action
+		want   []bool
+	}{
+		{"view project", ViewProject, []bool{true, true, true, true}},
+		{"update project", UpdateProject, []bool{true, true, false, false}},
+		{"delete project", DeleteProject, []bool{true, false, false, false}},
+		{"create task", CreateTask, []bool{true, true, false, false}},
+		{"update task", UpdateTask, []bool{true, true, false, false}},
+		{"move task", MoveTask, []bool{true, true, false, false}},
+		{"delete task", DeleteTask, []bool{true, true, false, false}},
+		{"comment", Comment, []bool{true, true, true, false}},
+		{"manage grants", ManageGrants, []bool{true, false, false, false}},
+		{"manage webhooks", ManageWebhooks, []bool{true, false, false, false}},
+		{"manage billing", ManageBilling, []bool{true, false, false, false}},
+		{"manage fields", ManageFields, []bool{true, false, false, false}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			for i, role := range roles {
+				assert.Equal(t, tc.want[i], Allowed(role, tc.action), "role %v", role)
+			}
+		})
+	}
+}
+
+func TestAllowedCoversEveryAction(t *testing.T) {
+	for a := ViewProject; a <= ManageFields; a++ {
+		assert.True(t, Allowed(memberships.Administrator, a), "action %d", a)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/lib/pq"
 	"github.com/pkg/errors"
 
+	"github.com/devpies/devpie-client-core/projects/domain/grants"
 	"github.com/devpies/devpie-client-core/projects/domain/memberships"
 	"github.com/devpies/devpie-client-core/projects/platform/database"
 )
@@ -30,7 +31,7 @@ type ProjectQuerier interface {
 	List(ctx context.Context, repo database.Storer, np NewProject, uid string, now time.Time) (Project, error)
 
 	Create(ctx context.Context, repo database.Storer, np NewProject, uid string, now time.Time) (Project, error)
-	Delete(ctx context.Context, repo database.Storer, pid, uid string) error
+	Delete(ctx context.Context, repo database.Storer, pid string) error
 }
 
 func RetrieveTeamID(ctx context.Context, repo database.Storer, pid string) (string, error) {
@@ -120,9 +121,11 @@ func RetrieveShared(ctx context.Context, repo database.Storer, pid, uid string)
 		return p, ErrInvalidID
 	}
 
-	m, err := memberships.Retrieve(ctx, repo, uid, tid)
-	if err != nil {
-		return p, ErrNotAuthorized
+	// access comes from a team membership or a project grant
+	if _, err = memberships.Retrieve(ctx, repo, uid, tid); err != nil {
+		if _, err = grants.RetrieveActive(ctx, repo, pid, uid); err != nil {
+			return p, ErrNotAuthorized
+		}
 	}
 
 	stmt := repo.Select(
@@ -137,7 +140,7 @@ func RetrieveShared(ctx context.Context, repo database.Storer, pid, uid string)
 		"column_order",
 		"updated_at",
 		"created_at",
-	).From("projects").Where(sq.Eq{"project_id": "?", "team_id": "?"})
+	).From("projects").Where(sq.Eq{"project_id": "?"})
 
 	q, args, err := stmt.ToSql()
 
@@ -145,7 +148,7 @@ func RetrieveShared(ctx context.Context, repo database.Storer, pid, uid string)
 		return p, errors.Wrapf(err, "building query: %v", args)
 	}
 
-	row := repo.QueryRowxContext(ctx, q, pid, m.TeamID)
+	row := repo.QueryRowxContext(ctx, q, pid)
 	err = row.Scan(&p.ID, &p.Name, &p.Prefix, &p.Description, &p.TeamID, &p.UserID, &p.Active, &p.Public, (*pq.StringArray)(&p.ColumnOrder), &p.UpdatedAt, &p.CreatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -163,6 +166,9 @@ func List(ctx context.Context, repo database.Storer, uid string) ([]Project, err
 
 	q := `SELECT * FROM projects
 		  WHERE team_id IN (SELECT team_id FROM memberships WHERE user_id = $1)
+		  UNION
+		  SELECT * FROM projects
+		  WHERE project_id IN (SELECT project_id FROM grants WHERE user_id = $1 AND (expiration IS NULL OR expiration > (NOW() AT TIME ZONE 'utc')))
 		  UNION 
 		  SELECT * FROM projects 
 		  WHERE user_id = $1
@@ -267,14 +273,14 @@ func Update(ctx context.Context, repo database.Storer, pid, uid string, update U
 	return p, nil
 }
 
-func Delete(ctx context.Context, repo database.Storer, pid, uid string) error {
+func Delete(ctx context.Context, repo database.Storer, pid string) error {
 	if _, err := uuid.Parse(pid); err != nil {
 		return ErrInvalidID
 	}
 
 	stmt := repo.Delete(
 		"projects",
-	).Where(sq.Eq{"project_id": pid, "user_id": uid})
+	).Where(sq.Eq{"project_id": pid})
 
 	if _, err := stmt.ExecContext(ctx); err != nil {
 		return errors.Wrapf(err, "deleting project %s", pid)
@@ -0,0 +1,68 @@
+package recurrences
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Frequencies of a recurrence rule.
+const (
+	Daily   = "daily"
+	Weekly  = "weekly"
+	Monthly = "monthly"
+)
+
+// Rule says how often a series repeats and when it ends, like an iCalendar RRULE. Weekdays
+// only apply to weekly rules and use the RRULE codes MO to SU. A series without Count or
+// Until repeats until it is stopped.
+type Rule struct {
+	Frequency string         `db:"frequency" json:"frequency"`
+	Interval  int            `db:"every" json:"interval"`
+	Weekdays  pq.StringArray `db:"weekdays" json:"weekdays"`
+	Count     *int           `db:"max_occurrences" json:"count"`
+	Until     *time.Time     `db:"until" json:"until"`
+}
+
+// Recurrence is a series of tasks. Every instance is a copy of the latest task of the series,
+// created in ColumnID when NextRunAt comes. Occurrences counts the tasks of the series
+// including the first one, and NextRunAt is nil once the series has ended.
+type Recurrence struct {
+	ID          string     `db:"recurrence_id" json:"id"`
+	ProjectID   string     `db:"project_id" json:"projectId"`
+	ColumnID    string     `db:"column_id" json:"columnId"`
+	TaskID      string     `db:"task_id" json:"taskId"`
+	Occurrences int        `db:"occurrences" json:"occurrences"`
+	StartsAt    time.Time  `db:"starts_at" json:"startsAt"`
+	NextRunAt   *time.Time `db:"next_run_at" json:"nextRunAt"`
+	CreatedBy   string     `db:"created_by" json:"createdBy"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updatedAt"`
+	CreatedAt   time.Time  `db:"created_at" json:"createdAt"`
+	RRule       string     `db:"-" json:"rrule"`
+	Rule
+}
+
+// NewRecurrence turns a task into the first task of a series. Without StartsAt the series
+// starts now and the next task is created one interval later; with it, the next task is
+// created at StartsAt.
+type NewRecurrence struct {
+	ColumnID  string     `json:"columnId" validate:"required,uuid"`
+	Frequency string     `json:"frequency" validate:"required,oneof=daily weekly monthly"`
+	Interval  int        `json:"interval" validate:"omitempty,min=1,max=366"`
+	Weekdays  []string   `json:"weekdays" validate:"omitempty,dive,oneof=MO TU WE TH FR SA SU"`
+	Count     *int       `json:"count" validate:"omitempty,min=2"`
+	Until     *time.Time `json:"until"`
+	StartsAt  *time.Time `json:"startsAt"`
+}
+
+// UpdateRecurrence edits a series. Count set to 0 removes the limit on occurrences and
+// Forever removes both end conditions.
+type UpdateRecurrence struct {
+	ColumnID  *string    `json:"columnId" validate:"omitempty,uuid"`
+	Frequency *string    `json:"frequency" validate:"omitempty,oneof=daily weekly monthly"`
+	Interval  *int       `json:"interval" validate:"omitempty,min=1,max=366"`
+	Weekdays  []string   `json:"weekdays" validate:"omitempty,dive,oneof=MO TU WE TH FR SA SU"`
+	Count     *int       `json:"count" validate:"omitempty,min=0"`
+	Until     *time.Time `json:"until"`
+	Forever   bool       `json:"forever"`
+}
@@ -0,0 +1,315 @@
+package recurrences
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrNotFound         = errors.New("recurrence not found")
+	ErrInvalidID        = errors.New("id provided was not a valid UUID")
+	ErrAlreadyRecurring = errors.New("task already belongs to a recurring series")
+	ErrInvalidStart     = errors.New("a series can not start in the past")
+	ErrEnded            = errors.New("the end condition leaves no further occurrences")
+)
+
+// fields lists the columns of a recurrence.
+const fields = `recurrence_id, project_id, column_id, task_id, frequency, every, weekdays, max_occurrences,
+	until, occurrences, starts_at, next_run_at, created_by, updated_at, created_at`
+
+// Create starts a series from a task. The task must not belong to another series.
+func Create(ctx context.Context, repo database.Storer, nr NewRecurrence, pid, tid, uid string, now time.Time) (Recurrence, error) {
+	var r Recurrence
+
+	if _, err := uuid.Parse(tid); err != nil {
+		return r, ErrInvalidID
+	}
+
+	r = Recurrence{
+		ID:          uuid.New().String(),
+		ProjectID:   pid,
+		ColumnID:    nr.ColumnID,
+		TaskID:      tid,
+		Occurrences: 1,
+		StartsAt:    now.UTC(),
+		CreatedBy:   uid,
+		UpdatedAt:   now.UTC(),
+		CreatedAt:   now.UTC(),
+		Rule: Rule{
+			Frequency: nr.Frequency,
+			Interval:  nr.Interval,
+			Weekdays:  nr.Weekdays,
+			Count:     nr.Count,
+			Until:     nr.Until,
+		},
+	}
+
+	if r.Interval < 1 {
+		r.Interval = 1
+	}
+	if r.Weekdays == nil || r.Frequency != Weekly {
+		r.Weekdays = make([]string, 0)
+	}
+	if r.Until != nil {
+		until := r.Until.UTC()
+		r.Until = &until
+	}
+
+	if nr.StartsAt != nil {
+		if nr.StartsAt.Before(now) {
+			return r, ErrInvalidStart
+		}
+		r.StartsAt = nr.StartsAt.UTC()
+		r.NextRunAt = &r.StartsAt
+		if r.Until != nil && r.StartsAt.After(*r.Until) {
+			return r, ErrEnded
+		}
+	} else {
+		r.NextRunAt = r.Next(r.StartsAt, r.StartsAt, r.Occurrences)
+		if r.NextRunAt == nil {
+			return r, ErrEnded
+		}
+	}
+
+	tx, err := repo.BeginTxx(ctx, nil)
+	if err != nil {
+		return r, errors.Wrap(err, "starting transaction")
+	}
+	defer tx.Rollback()
+
+	// claim the task for the series, unless it already belongs to one
+	res, err := tx.ExecContext(ctx, repo.Rebind(`UPDATE tasks SET recurrence_id = ?
+		WHERE task_id = ? AND project_id = ? AND recurrence_id = ''`), r.ID, tid, pid)
+	if err != nil {
+		return r, errors.Wrapf(err, "attaching task %s to series", tid)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return r, ErrAlreadyRecurring
+	}
+
+	q := repo.Rebind(`INSERT INTO task_recurrences (` + fields + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+
+	if _, err := tx.ExecContext(ctx, q, r.ID, r.ProjectID, r.ColumnID, r.TaskID, r.Frequency, r.Interval,
+		r.Weekdays, r.Count, r.Until, r.Occurrences, r.StartsAt, r.NextRunAt, r.CreatedBy, r.UpdatedAt,
+		r.CreatedAt); err != nil {
+		return r, errors.Wrapf(err, "inserting recurrence: %v", nr)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return r, errors.Wrap(err, "committing recurrence")
+	}
+
+	r.RRule = r.String()
+
+	return r, nil
+}
+
+// List returns the series of a project, the next one due first.
+func List(ctx context.Context, repo database.Storer, pid string) ([]Recurrence, error) {
+	var rs = make([]Recurrence, 0)
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	q := repo.Rebind(`SELECT ` + fields + ` FROM task_recurrences WHERE project_id = ?
+		ORDER BY next_run_at NULLS LAST, created_at`)
+
+	if err := repo.SelectContext(ctx, &rs, q, pid); err != nil {
+		return nil, errors.Wrap(err, "selecting recurrences")
+	}
+
+	for i := range rs {
+		rs[i].RRule = rs[i].String()
+	}
+
+	return rs, nil
+}
+
+// Retrieve returns a series of a project.
+func Retrieve(ctx context.Context, repo database.Storer, pid, rid string) (Recurrence, error) {
+	var r Recurrence
+
+	if _, err := uuid.Parse(rid); err != nil {
+		return r, ErrInvalidID
+	}
+
+	q := repo.Rebind(`SELECT ` + fields + ` FROM task_recurrences WHERE project_id = ? AND recurrence_id = ?`)
+
+	if err := repo.QueryRowxContext(ctx, q, pid, rid).StructScan(&r); err != nil {
+		if err == sql.ErrNoRows {
+			return r, ErrNotFound
+		}
+		return r, err
+	}
+
+	r.RRule = r.String()
+
+	return r, nil
+}
+
+// Update edits the rule or column of a series and reschedules its next task.
+func Update(ctx context.Context, repo database.Storer, pid, rid string, update UpdateRecurrence, now time.Time) (Recurrence, error) {
+	r, err := Retrieve(ctx, repo, pid, rid)
+	if err != nil {
+		return r, err
+	}
+
+	if update.ColumnID != nil {
+		r.ColumnID = *update.ColumnID
+	}
+	if update.Frequency != nil {
+		r.Frequency = *update.Frequency
+	}
+	if update.Interval != nil {
+		r.Interval = *update.Interval
+	}
+	if update.Weekdays != nil {
+		r.Weekdays = update.Weekdays
+	}
+	if r.Frequency != Weekly {
+		r.Weekdays = make([]string, 0)
+	}
+	if update.Count != nil {
+		r.Count = update.Count
+	}
+	if update.Until != nil {
+		until := update.Until.UTC()
+		r.Until = &until
+	}
+	if update.Forever {
+		r.Count = nil
+		r.Until = nil
+	}
+	if r.Count != nil && *r.Count == 0 {
+		r.Count = nil
+	}
+
+	// a series that has not produced a task yet keeps its start
+	if r.Occurrences == 1 && r.StartsAt.After(now) {
+		r.NextRunAt = &r.StartsAt
+		if r.Until != nil && r.StartsAt.After(*r.Until) {
+			r.NextRunAt = nil
+		}
+	} else {
+		r.NextRunAt = r.Next(r.StartsAt, now, r.Occurrences)
+	}
+	r.UpdatedAt = now.UTC()
+
+	stmt := repo.Update(
+		"task_recurrences",
+	).SetMap(map[string]interface{}{
+		"column_id":       r.ColumnID,
+		"frequency":       r.Frequency,
+		"every":           r.Interval,
+		"weekdays":        r.Weekdays,
+		"max_occurrences": r.Count,
+		"until":           r.Until,
+		"next_run_at":     r.NextRunAt,
+		"updated_at":      r.UpdatedAt,
+	}).Where(sq.Eq{"project_id": pid, "recurrence_id": rid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return r, errors.Wrapf(err, "updating recurrence: %s", rid)
+	}
+
+	r.RRule = r.String()
+
+	return r, nil
+}
+
+// Delete stops a series. The tasks it created are kept.
+func Delete(ctx context.Context, repo database.Storer, pid, rid string) error {
+	if _, err := uuid.Parse(rid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"task_recurrences",
+	).Where(sq.Eq{"project_id": pid, "recurrence_id": rid})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "deleting recurrence %s", rid)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+
+	detach := repo.Update(
+		"tasks",
+	).Set("recurrence_id", "").Where(sq.Eq{"recurrence_id": rid})
+
+	if _, err := detach.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "detaching tasks from recurrence %s", rid)
+	}
+
+	return nil
+}
+
+// DeleteAll removes every series of a project.
+func DeleteAll(ctx context.Context, repo database.Storer, pid string) error {
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"task_recurrences",
+	).Where(sq.Eq{"project_id": pid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting all recurrences")
+	}
+
+	return nil
+}
+
+// Claim leases up to limit due series to the caller. A claimed series is not due again
+// until the lease runs out, so replicas never create the same task concurrently and a
+// crashed scheduler's series are picked up later.
+func Claim(ctx context.Context, repo database.Storer, now time.Time, lease time.Duration, limit int) ([]Recurrence, error) {
+	var rs = make([]Recurrence, 0)
+
+	q := `UPDATE task_recurrences SET next_run_at = $1 WHERE recurrence_id IN (
+			SELECT recurrence_id FROM task_recurrences WHERE next_run_at <= $2
+			ORDER BY next_run_at LIMIT $3 FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + fields
+
+	if err := repo.SelectContext(ctx, &rs, q, now.Add(lease).UTC(), now.UTC(), limit); err != nil {
+		return nil, errors.Wrap(err, "claiming recurrences")
+	}
+
+	return rs, nil
+}
+
+// Advance records the task created for a series and schedules the next one. Occurrences
+// missed while no scheduler ran are skipped rather than created at once.
+func Advance(ctx context.Context, repo database.Storer, r Recurrence, tid string, now time.Time) error {
+	r.Occurrences++
+	next := r.Next(r.StartsAt, now, r.Occurrences)
+
+	stmt := repo.Update(
+		"task_recurrences",
+	).SetMap(map[string]interface{}{
+		"task_id":     tid,
+		"occurrences": r.Occurrences,
+		"next_run_at": next,
+		"updated_at":  now.UTC(),
+	}).Where(sq.Eq{"recurrence_id": r.ID})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "advancing recurrence %s", r.ID)
+	}
+
+	return nil
+}
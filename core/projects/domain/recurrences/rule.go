@@ -0,0 +1,127 @@
+package recurrences
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// weekdays maps RRULE day codes to weekdays.
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Next returns the first occurrence of the rule after a time, for a series anchored at start.
+// Occurrences keep the time of day of start. Monthly series fall on the day of the month of
+// start, or on the last day of shorter months. It returns nil once the rule has ended after
+// the given number of occurrences.
+func (r Rule) Next(start, after time.Time, occurrences int) *time.Time {
+	if r.Count != nil && *r.Count > 0 && occurrences >= *r.Count {
+		return nil
+	}
+
+	start = start.UTC()
+	if after.Before(start) {
+		after = start
+	}
+
+	var next time.Time
+	switch {
+	case r.Frequency == Monthly:
+		next = r.nextMonth(start, after)
+	case r.Frequency == Weekly && len(r.Weekdays) > 0:
+		next = r.nextWeekday(start, after)
+	case r.Frequency == Weekly:
+		next = r.nextDay(start, after, 7*r.interval())
+	default:
+		next = r.nextDay(start, after, r.interval())
+	}
+
+	if r.Until != nil && next.After(*r.Until) {
+		return nil
+	}
+
+	return &next
+}
+
+// String formats the rule as an iCalendar RRULE value.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + strings.ToUpper(r.Frequency)}
+	if r.interval() > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.interval()))
+	}
+	if r.Frequency == Weekly && len(r.Weekdays) > 0 {
+		parts = append(parts, "BYDAY="+strings.Join(r.Weekdays, ","))
+	}
+	if r.Count != nil && *r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", *r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+func (r Rule) interval() int {
+	if r.Interval < 1 {
+		return 1
+	}
+	return r.Interval
+}
+
+func (r Rule) nextDay(start, after time.Time, step int) time.Time {
+	k := int(after.Sub(start).Hours()/24) / step
+	next := start.AddDate(0, 0, k*step)
+	for !next.After(after) {
+		k++
+		next = start.AddDate(0, 0, k*step)
+	}
+	return next
+}
+
+func (r Rule) nextWeekday(start, after time.Time) time.Time {
+	days := make(map[time.Weekday]bool)
+	for _, d := range r.Weekdays {
+		days[weekdays[d]] = true
+	}
+
+	// days since the monday of the week of start
+	offset := (int(start.Weekday()) + 6) % 7
+
+	i := int(after.Sub(start).Hours() / 24)
+	for {
+		next := start.AddDate(0, 0, i)
+		week := (i + offset) / 7
+		if next.After(after) && days[next.Weekday()] && week%r.interval() == 0 {
+			return next
+		}
+		i++
+	}
+}
+
+func (r Rule) nextMonth(start, after time.Time) time.Time {
+	months := (after.Year()-start.Year())*12 + int(after.Month()-start.Month())
+	k := months / r.interval()
+	next := addMonths(start, k*r.interval())
+	for !next.After(after) {
+		k++
+		next = addMonths(start, k*r.interval())
+	}
+	return next
+}
+
+// addMonths moves a time n months ahead, keeping the day of the month where it exists.
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	day := t.Day()
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
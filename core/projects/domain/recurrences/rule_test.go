@@ -0,0 +1,129 @@
+package recurrences
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func at(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+func intp(n int) *int {
+	return &n
+}
+
+func timep(t time.Time) *time.Time {
+	return &t
+}
+
+func TestRuleNext(t *testing.T) {
+	start := at(2021, time.January, 1, 9)
+
+	testcases := []struct {
+		name        string
+		rule        Rule
+		after       time.Time
+		occurrences int
+		want        *time.Time
+	}{
+		{"daily", Rule{Frequency: Daily}, start, 1, timep(at(2021, time.January, 2, 9))},
+		{"daily with interval", Rule{Frequency: Daily, Interval: 3}, start, 1, timep(at(2021, time.January, 4, 9))},
+		{"before start", Rule{Frequency: Daily}, at(2020, time.December, 1, 9), 1, timep(at(2021, time.January, 2, 9))},
+		{"catch up after downtime", Rule{Frequency: Daily, Interval: 3}, at(2021, time.January, 20, 12), 1, timep(at(2021, time.January, 22, 9))},
+		{"weekly", Rule{Frequency: Weekly, Interval: 2}, start, 1, timep(at(2021, time.January, 15, 9))},
+		{"count not reached", Rule{Frequency: Daily, Count: intp(3)}, start, 2, timep(at(2021, time.January, 2, 9))},
+		{"count reached", Rule{Frequency: Daily, Count: intp(3)}, start, 3, nil},
+		{"zero count", Rule{Frequency: Daily, Count: intp(0)}, start, 10, timep(at(2021, time.January, 2, 9))},
+		{"until includes last", Rule{Frequency: Daily, Until: timep(at(2021, time.January, 3, 9))}, at(2021, time.January, 2, 9), 2, timep(at(2021, time.January, 3, 9))},
+		{"until passed", Rule{Frequency: Daily, Until: timep(at(2021, time.January, 3, 9))}, at(2021, time.January, 3, 9), 3, nil},
+		{"monthly", Rule{Frequency: Monthly}, start, 1, timep(at(2021, time.February, 1, 9))},
+		{"weekdays", Rule{Frequency: Weekly, Weekdays: []string{"MO"}}, start, 1, timep(at(2021, time.January, 4, 9))},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.rule.Next(start, tc.after, tc.occurrences))
+		})
+	}
+}
+
+func TestNextWeekday(t *testing.T) {
+	monday := at(2021, time.January, 4, 9)
+	wednesday := at(2021, time.January, 6, 9)
+
+	testcases := []struct {
+		name  string
+		rule  Rule
+		start time.Time
+		after time.Time
+		want  time.Time
+	}{
+		{"next day of the week", Rule{Weekdays: []string{"MO", "WE", "FR"}}, monday, monday, at(2021, time.January, 6, 9)},
+		{"into the next week", Rule{Weekdays: []string{"MO", "WE", "FR"}}, monday, at(2021, time.January, 8, 9), at(2021, time.January, 11, 9)},
+		{"same day later", Rule{Weekdays: []string{"MO", "WE", "FR"}}, monday, at(2021, time.January, 6, 8), at(2021, time.January, 6, 9)},
+		{"interval in the first week", Rule{Interval: 2, Weekdays: []string{"MO", "FR"}}, monday, monday, at(2021, time.January, 8, 9)},
+		{"interval skips a week", Rule{Interval: 2, Weekdays: []string{"MO", "FR"}}, monday, at(2021, time.January, 8, 9), at(2021, time.January, 18, 9)},
+		{"interval counts from the week of start", Rule{Interval: 2, Weekdays: []string{"MO", "FR"}}, wednesday, at(2021, time.January, 8, 9), at(2021, time.January, 18, 9)},
+		{"catch up after downtime", Rule{Weekdays: []string{"MO"}}, monday, at(2021, time.March, 17, 12), at(2021, time.March, 22, 9)},
+		{"catch up with interval", Rule{Interval: 3, Weekdays: []string{"MO"}}, monday, at(2021, time.March, 17, 12), at(2021, time.March, 29, 9)},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.rule.nextWeekday(tc.start, tc.after))
+		})
+	}
+}
+
+func TestNextMonth(t *testing.T) {
+	endOfJanuary := at(2021, time.January, 31, 9)
+
+	testcases := []struct {
+		name  string
+		rule  Rule
+		start time.Time
+		after time.Time
+		want  time.Time
+	}{
+		{"clamped to a shorter month", Rule{}, endOfJanuary, endOfJanuary, at(2021, time.February, 28, 9)},
+		{"back to the day of start", Rule{}, endOfJanuary, at(2021, time.February, 28, 9), at(2021, time.March, 31, 9)},
+		{"clamped to a 30 day month", Rule{}, endOfJanuary, at(2021, time.March, 31, 9), at(2021, time.April, 30, 9)},
+		{"leap year", Rule{}, at(2020, time.January, 31, 9), at(2020, time.January, 31, 9), at(2020, time.February, 29, 9)},
+		{"interval", Rule{Interval: 2}, endOfJanuary, endOfJanuary, at(2021, time.March, 31, 9)},
+		{"interval across a year", Rule{Interval: 3}, at(2021, time.November, 30, 9), at(2021, time.November, 30, 9), at(2022, time.February, 28, 9)},
+		{"catch up after downtime", Rule{}, endOfJanuary, at(2021, time.July, 15, 12), at(2021, time.July, 31, 9)},
+		{"catch up with interval", Rule{Interval: 2}, endOfJanuary, at(2021, time.August, 1, 9), at(2021, time.September, 30, 9)},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.rule.nextMonth(tc.start, tc.after))
+		})
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	testcases := []struct {
+		name string
+		t    time.Time
+		n    int
+		want time.Time
+	}{
+		{"none", at(2021, time.January, 15, 9), 0, at(2021, time.January, 15, 9)},
+		{"keeps the day", at(2021, time.January, 15, 9), 1, at(2021, time.February, 15, 9)},
+		{"end of february", at(2021, time.January, 31, 9), 1, at(2021, time.February, 28, 9)},
+		{"end of february in a leap year", at(2020, time.January, 31, 9), 1, at(2020, time.February, 29, 9)},
+		{"end of a 30 day month", at(2021, time.January, 31, 9), 3, at(2021, time.April, 30, 9)},
+		{"into the next year", at(2021, time.November, 30, 9), 3, at(2022, time.February, 28, 9)},
+		{"a year ahead", at(2020, time.February, 29, 9), 12, at(2021, time.February, 28, 9)},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, addMonths(tc.t, tc.n))
+		})
+	}
+}
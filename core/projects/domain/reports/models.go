@@ -0,0 +1,59 @@
+package reports
+
+import (
+	"time"
+
+	"github.com/devpies/devpie-client-core/projects/domain/customfields"
+	"github.com/devpies/devpie-client-core/projects/domain/milestones"
+	"github.com/devpies/devpie-client-core/projects/domain/tasks"
+)
+
+// Formats a status report can be rendered in.
+const (
+	HTML     = "html"
+	Markdown = "markdown"
+)
+
+// Report summarizes the progress of a project over a period.
+type Report struct {
+	ProjectID    string             `json:"projectId"`
+	ProjectName  string             `json:"projectName"`
+	From         time.Time          `json:"from"`
+	To           time.Time          `json:"to"`
+	Milestones   []milestones.Stage `json:"milestones"`
+	Completed    []Item             `json:"completed"`
+	InProgress   []Item             `json:"inProgress"`
+	PointsBurned int                `json:"pointsBurned"`
+	TimeLogged   int                `json:"timeLogged"`
+	Billable     int                `json:"billable"`
+	ShowPoints   bool               `json:"showPoints"`
+	GeneratedAt  time.Time          `json:"generatedAt"`
+}
+
+// Item is a task listed in a report, with the custom field values set on it.
+type Item struct {
+	ID          string               `db:"task_id" json:"id"`
+	Key         string               `db:"key" json:"key"`
+	Title       string               `db:"title" json:"title"`
+	Points      int                  `db:"points" json:"points"`
+	AssignedTo  string               `db:"assigned_to" json:"assignedTo"`
+	Column      string               `db:"column_title" json:"column"`
+	CompletedAt *time.Time           `db:"completed_at" json:"completedAt"`
+	Values      tasks.Fields         `db:"custom_fields" json:"-"`
+	Fields      []customfields.Value `db:"-" json:"fields"`
+}
+
+// Template is a team's customized report layout for one format.
+type Template struct {
+	TeamID    string    `db:"team_id" json:"teamId"`
+	Format    string    `db:"format" json:"format"`
+	Body      string    `db:"body" json:"body"`
+	Default   bool      `db:"-" json:"default"`
+	UpdatedBy string    `db:"updated_by" json:"updatedBy"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+type NewTemplate struct {
+	Body string `json:"body" validate:"required"`
+}
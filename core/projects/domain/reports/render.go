@@ -0,0 +1,171 @@
+package reports
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/ioutil"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/customfields"
+	"github.com/devpies/devpie-client-core/projects/domain/milestones"
+)
+
+// funcs are available to every report template.
+var funcs = map[string]interface{}{
+	"hours": func(s int) string { return fmt.Sprintf("%.1f", float64(s)/3600) },
+	"date":  func(t time.Time) string { return t.Format("January 2, 2006") },
+	"due": func(t *time.Time) string {
+		if t == nil {
+			return "no due date"
+		}
+		return t.Format("January 2, 2006")
+	},
+	"title": strings.Title,
+}
+
+// defaults are the built-in layouts used until a team customizes its own.
+var defaults = map[string]string{
+	HTML: `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Status report - {{.ProjectName}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; color: #222; margin: 40px; }
+table { width: 100%; border-collapse: collapse; margin: 12px 0 24px; }
+th, td { padding: 6px 8px; border-bottom: 1px solid #ddd; text-align: left; }
+.num { text-align: right; }
+.summary td { border: 0; padding: 2px 8px 2px 0; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>{{.ProjectName}} status report</h1>
+<p>{{date .From}} &ndash; {{date .To}}</p>
+<table class="summary">
+<tr><td>Tasks completed</td><td>{{len .Completed}}</td></tr>
+<tr><td>Tasks in progress</td><td>{{len .InProgress}}</td></tr>
+{{if .ShowPoints}}<tr><td>Points burned</td><td>{{.PointsBurned}}</td></tr>
+{{end}}<tr><td>Time logged</td><td>{{hours .TimeLogged}} h ({{hours .Billable}} h billable)</td></tr>
+</table>
+<h2>Milestones</h2>
+{{if .Milestones}}<table>
+<thead><tr><th>Milestone</th><th>Due</th><th>Status</th><th class="num">Progress</th></tr></thead>
+<tbody>
+{{range .Milestones}}<tr><td>{{.Title}}</td><td>{{due .DueDate}}</td><td>{{title .Status}}</td><td class="num">{{.Progress}}% ({{.Completed}}/{{.Total}})</td></tr>
+{{end}}</tbody>
+</table>{{else}}<p>No milestones.</p>{{end}}
+<h2>Completed</h2>
+{{if .Completed}}<table>
+<thead><tr><th>Task</th><th>Title</th>{{if .ShowPoints}}<th class="num">Points</th>{{end}}<th>Completed</th></tr></thead>
+<tbody>
+{{range .Completed}}<tr><td>{{.Key}}</td><td>{{.Title}}{{range .Fields}}<br><small>{{.Name}}: {{.Value}}</small>{{end}}</td>{{if $.ShowPoints}}<td class="num">{{.Points}}</td>{{end}}<td>{{with .CompletedAt}}{{date .}}{{end}}</td></tr>
+{{end}}</tbody>
+</table>{{else}}<p>No tasks were completed.</p>{{end}}
+<h2>In progress</h2>
+{{if .InProgress}}<table>
+<thead><tr><th>Task</th><th>Title</th>{{if .ShowPoints}}<th class="num">Points</th>{{end}}<th>Column</th></tr></thead>
+<tbody>
+{{range .InProgress}}<tr><td>{{.Key}}</td><td>{{.Title}}{{range .Fields}}<br><small>{{.Name}}: {{.Value}}</small>{{end}}</td>{{if $.ShowPoints}}<td class="num">{{.Points}}</td>{{end}}<td>{{.Column}}</td></tr>
+{{end}}</tbody>
+</table>{{else}}<p>No tasks are in progress.</p>{{end}}
+</body>
+</html>
+`,
+	Markdown: `# {{.ProjectName}} status report
+
+{{date .From}} – {{date .To}}
+
+- Tasks completed: {{len .Completed}}
+- Tasks in progress: {{len .InProgress}}
+{{- if .ShowPoints}}
+- Points burned: {{.PointsBurned}}
+{{- end}}
+- Time logged: {{hours .TimeLogged}} h ({{hours .Billable}} h billable)
+
+## Milestones
+{{if .Milestones}}
+| Milestone | Due | Status | Progress |
+| --- | --- | --- | ---: |
+{{- range .Milestones}}
+| {{.Title}} | {{due .DueDate}} | {{title .Status}} | {{.Progress}}% ({{.Completed}}/{{.Total}}) |
+{{- end}}
+{{else}}
+No milestones.
+{{end}}
+## Completed
+{{if .Completed}}
+{{- range .Completed}}
+- **{{.Key}}** {{.Title}}{{if $.ShowPoints}} ({{.Points}} points){{end}}{{with .CompletedAt}}, {{date .}}{{end}}{{range .Fields}}; {{.Name}}: {{.Value}}{{end}}
+{{- end}}
+{{else}}
+No tasks were completed.
+{{end}}
+## In progress
+{{if .InProgress}}
+{{- range .InProgress}}
+- **{{.Key}}** {{.Title}}{{if $.ShowPoints}} ({{.Points}} points){{end}}, {{.Column}}{{range .Fields}}; {{.Name}}: {{.Value}}{{end}}
+{{- end}}
+{{else}}
+No tasks are in progress.
+{{end}}`,
+}
+
+// ContentType returns the media type of a report format.
+func ContentType(format string) string {
+	if format == Markdown {
+		return "text/markdown; charset=utf-8"
+	}
+	return "text/html; charset=utf-8"
+}
+
+// Render writes a report using a template body. HTML output is escaped, Markdown is written
+// as is.
+func Render(w io.Writer, format, body string, r Report) error {
+	switch format {
+	case HTML:
+		t, err := htmltemplate.New(HTML).Funcs(funcs).Parse(body)
+		if err != nil {
+			return err
+		}
+		return t.Execute(w, r)
+	case Markdown:
+		t, err := texttemplate.New(Markdown).Funcs(funcs).Parse(body)
+		if err != nil {
+			return err
+		}
+		return t.Execute(w, r)
+	default:
+		return ErrInvalidFormat
+	}
+}
+
+// Validate checks that a template body renders a sample report.
+func Validate(format, body string) error {
+	if _, ok := defaults[format]; !ok {
+		return ErrInvalidFormat
+	}
+
+	now := time.Now().UTC()
+	sample := Report{
+		ProjectName: "Sample",
+		From:        now.AddDate(0, 0, -7),
+		To:          now,
+		Milestones:  []milestones.Stage{{Milestone: milestones.Milestone{Title: "Launch"}, Status: milestones.Upcoming}},
+		Completed:   []Item{{Key: "APP-1", Title: "Done task", CompletedAt: &now, Fields: []customfields.Value{{Name: "Environment", Value: "Staging"}}}},
+		InProgress:  []Item{{Key: "APP-2", Title: "Open task", Column: "In Progress"}},
+		ShowPoints:  true,
+		GeneratedAt: now,
+	}
+
+	if err := Render(ioutil.Discard, format, body, sample); err != nil {
+		return errors.Wrap(err, "invalid template")
+	}
+
+	return nil
+}
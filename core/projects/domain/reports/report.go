@@ -0,0 +1,240 @@
+package reports
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/columns"
+	"github.com/devpies/devpie-client-core/projects/domain/customfields"
+	"github.com/devpies/devpie-client-core/projects/domain/milestones"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrNotFound      = errors.New("report template not found")
+	ErrInvalidID     = errors.New("id provided was not a valid UUID")
+	ErrInvalidFormat = errors.New("reports can only be rendered as html or markdown")
+	ErrNoTeam        = errors.New("project does not belong to a team")
+)
+
+// items selects tasks with the title of the column they sit in.
+const items = `SELECT t.task_id, COALESCE(t.key, '') AS key, t.title, COALESCE(t.points, 0) AS points,
+	COALESCE(t.assigned_to, '') AS assigned_to, c.title AS column_title, t.completed_at, t.custom_fields
+	FROM tasks t JOIN columns c ON c.project_id = t.project_id AND t.task_id = ANY(c.task_ids)
+	WHERE t.project_id = ?`
+
+// Build gathers the status report of a project for the period [from, to). Tasks count as
+// completed when they reached the done column within the period, and as in progress while
+// they sit between the first column of the board and the done column. Client reports leave
+// out internal tasks and estimates.
+func Build(ctx context.Context, repo database.Storer, pid string, from, to, now time.Time, client bool) (Report, error) {
+	var r Report
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return r, ErrInvalidID
+	}
+
+	r = Report{
+		ProjectID:   pid,
+		From:        from.UTC(),
+		To:          to.UTC(),
+		Completed:   make([]Item, 0),
+		InProgress:  make([]Item, 0),
+		ShowPoints:  !client,
+		GeneratedAt: now.UTC(),
+	}
+
+	if err := repo.QueryRowxContext(ctx, `SELECT name FROM projects WHERE project_id = $1`, pid).Scan(&r.ProjectName); err != nil {
+		if err == sql.ErrNoRows {
+			return r, ErrNotFound
+		}
+		return r, errors.Wrapf(err, "selecting project %s", pid)
+	}
+
+	visible := ""
+	if client {
+		visible = " AND NOT t.internal"
+	}
+	done := strings.ToLower(columns.Done)
+
+	q := repo.Rebind(items + visible + ` AND LOWER(c.title) = ? AND t.completed_at >= ? AND t.completed_at < ?
+		ORDER BY t.completed_at`)
+	if err := repo.SelectContext(ctx, &r.Completed, q, pid, done, r.From, r.To); err != nil {
+		return r, errors.Wrap(err, "selecting completed tasks")
+	}
+
+	q = repo.Rebind(items + visible + ` AND LOWER(c.title) <> ? AND c.column_name <> 'column-1'
+		ORDER BY c.column_name, t.seq`)
+	if err := repo.SelectContext(ctx, &r.InProgress, q, pid, done); err != nil {
+		return r, errors.Wrap(err, "selecting tasks in progress")
+	}
+
+	for _, i := range r.Completed {
+		r.PointsBurned += i.Points
+	}
+
+	fs, err := customfields.List(ctx, repo, pid)
+	if err != nil {
+		return r, err
+	}
+	for i := range r.Completed {
+		r.Completed[i].Fields = customfields.Format(fs, r.Completed[i].Values)
+	}
+	for i := range r.InProgress {
+		r.InProgress[i].Fields = customfields.Format(fs, r.InProgress[i].Values)
+	}
+
+	stmt := repo.Select(
+		"COALESCE(SUM(e.duration), 0)",
+		"COALESCE(SUM(e.duration) FILTER (WHERE e.billable), 0)",
+	).From(
+		"time_entries e",
+	).Join(
+		"tasks t ON t.task_id = e.task_id",
+	).Where(sq.Eq{"e.project_id": "?"}).Where("e.stopped_at IS NOT NULL AND e.started_at >= ? AND e.started_at < ?" + visible)
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return r, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.QueryRowxContext(ctx, q, pid, r.From, r.To).Scan(&r.TimeLogged, &r.Billable); err != nil {
+		return r, errors.Wrap(err, "summing logged time")
+	}
+
+	r.Milestones, err = milestones.Timeline(ctx, repo, pid, client, now)
+	if err != nil {
+		return r, err
+	}
+
+	if client {
+		for i := range r.Completed {
+			r.Completed[i].Points = 0
+		}
+		for i := range r.InProgress {
+			r.InProgress[i].Points = 0
+		}
+		r.PointsBurned = 0
+	}
+
+	return r, nil
+}
+
+// RetrieveTemplate returns a team's template for a format, or the built-in one when the
+// team hasn't customized it.
+func RetrieveTemplate(ctx context.Context, repo database.Storer, teamID, format string) (Template, error) {
+	var t Template
+
+	body, ok := defaults[format]
+	if !ok {
+		return t, ErrInvalidFormat
+	}
+
+	if teamID != "" {
+		stmt := repo.Select(
+			"team_id",
+			"format",
+			"body",
+			"updated_by",
+			"updated_at",
+			"created_at",
+		).From("report_templates").Where(sq.Eq{"team_id": "?", "format": "?"})
+
+		q, args, err := stmt.ToSql()
+		if err != nil {
+			return t, errors.Wrapf(err, "building query: %v", args)
+		}
+
+		err = repo.QueryRowxContext(ctx, q, teamID, format).StructScan(&t)
+		if err == nil {
+			return t, nil
+		}
+		if err != sql.ErrNoRows {
+			return t, err
+		}
+	}
+
+	return Template{TeamID: teamID, Format: format, Body: body, Default: true}, nil
+}
+
+// SetTemplate customizes a team's report layout for a format. Bodies are expected to have
+// passed Validate.
+func SetTemplate(ctx context.Context, repo database.Storer, teamID, format string, nt NewTemplate, uid string, now time.Time) (Template, error) {
+	var t Template
+
+	if teamID == "" {
+		return t, ErrNoTeam
+	}
+	if _, err := uuid.Parse(teamID); err != nil {
+		return t, ErrInvalidID
+	}
+
+	if _, ok := defaults[format]; !ok {
+		return t, ErrInvalidFormat
+	}
+
+	t = Template{
+		TeamID:    teamID,
+		Format:    format,
+		Body:      nt.Body,
+		UpdatedBy: uid,
+		UpdatedAt: now.UTC(),
+		CreatedAt: now.UTC(),
+	}
+
+	stmt := repo.Insert(
+		"report_templates",
+	).SetMap(map[string]interface{}{
+		"team_id":    t.TeamID,
+		"format":     t.Format,
+		"body":       t.Body,
+		"updated_by": t.UpdatedBy,
+		"updated_at": t.UpdatedAt,
+		"created_at": t.CreatedAt,
+	}).Suffix(
+		"ON CONFLICT (team_id, format) DO UPDATE SET body = EXCLUDED.body, " +
+			"updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at RETURNING created_at",
+	)
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return t, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.QueryRowxContext(ctx, q, args...).Scan(&t.CreatedAt); err != nil {
+		return t, errors.Wrapf(err, "saving %s report template of team %s", format, teamID)
+	}
+
+	return t, nil
+}
+
+// DeleteTemplate restores the built-in layout of a format for a team.
+func DeleteTemplate(ctx context.Context, repo database.Storer, teamID, format string) error {
+	if teamID == "" {
+		return ErrNoTeam
+	}
+	if _, ok := defaults[format]; !ok {
+		return ErrInvalidFormat
+	}
+
+	stmt := repo.Delete(
+		"report_templates",
+	).Where(sq.Eq{"team_id": teamID, "format": format})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "deleting %s report template of team %s", format, teamID)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
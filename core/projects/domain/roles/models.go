@@ -0,0 +1,9 @@
+package roles
+
+// Profile is what a user's access token last said about them.
+type Profile struct {
+	UserID   string `db:"user_id"`
+	Client   bool   `db:"client"`
+	Email    string `db:"email"`
+	Nickname string `db:"nickname"`
+}
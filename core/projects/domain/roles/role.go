@@ -0,0 +1,119 @@
+package roles
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+// Record remembers whether a user holds the client role and how they can be mentioned, as
+// last seen in their access token. Other users are only known through what was recorded.
+func Record(ctx context.Context, repo database.Storer, p Profile, now time.Time) error {
+	q := `INSERT INTO user_roles (user_id, client, email, nickname, updated_at)
+		VALUES (:user_id, :client, :email, :nickname, :updated_at)
+		ON CONFLICT (user_id) DO UPDATE SET client = EXCLUDED.client, email = EXCLUDED.email,
+		nickname = EXCLUDED.nickname, updated_at = EXCLUDED.updated_at`
+
+	if _, err := repo.NamedExecContext(ctx, q, map[string]interface{}{
+		"user_id":    p.UserID,
+		"client":     p.Client,
+		"email":      p.Email,
+		"nickname":   p.Nickname,
+		"updated_at": now.UTC(),
+	}); err != nil {
+		return errors.Wrapf(err, "recording role of user %s", p.UserID)
+	}
+
+	return nil
+}
+
+// Lookup returns whether each of the users holds the client role. Users whose role was
+// never recorded are left out.
+func Lookup(ctx context.Context, repo database.Storer, ids []string) (map[string]bool, error) {
+	clients := make(map[string]bool, len(ids))
+	if len(ids) == 0 {
+		return clients, nil
+	}
+
+	var rows []struct {
+		UserID string `db:"user_id"`
+		Client bool   `db:"client"`
+	}
+
+	q := repo.Rebind(`SELECT user_id, client FROM user_roles WHERE user_id = ANY(?)`)
+	if err := repo.SelectContext(ctx, &rows, q, pq.Array(ids)); err != nil {
+		return nil, errors.Wrap(err, "selecting user roles")
+	}
+
+	for _, r := range rows {
+		clients[r.UserID] = r.Client
+	}
+
+	return clients, nil
+}
+
+// Team returns the users known not to hold the client role, keeping their order.
+func Team(ctx context.Context, repo database.Storer, ids []string) ([]string, error) {
+	clients, err := Lookup(ctx, repo, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	team := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if client, ok := clients[id]; ok && !client {
+			team = append(team, id)
+		}
+	}
+
+	return team, nil
+}
+
+// Members returns the recorded profiles of the users with access to a project: its owner,
+// the members of its team and the holders of unexpired grants.
+func Members(ctx context.Context, repo database.Storer, pid string) ([]Profile, error) {
+	var ps = make([]Profile, 0)
+
+	q := repo.Rebind(`SELECT user_id, client, email, nickname FROM user_roles WHERE user_id IN (
+		SELECT user_id FROM projects WHERE project_id = ?
+		UNION
+		SELECT m.user_id FROM memberships m JOIN projects p ON m.team_id = p.team_id WHERE p.project_id = ?
+		UNION
+		SELECT user_id FROM grants WHERE project_id = ?
+		AND (expiration IS NULL OR expiration > (NOW() AT TIME ZONE 'utc'))
+	)`)
+
+	if err := repo.SelectContext(ctx, &ps, q, pid, pid, pid); err != nil {
+		return nil, errors.Wrapf(err, "selecting members of project %s", pid)
+	}
+
+	return ps, nil
+}
+
+// Resolve matches a mention handle against profiles. A handle matches a full email
+// address, the part of an email before the @, or a nickname. It returns false when nobody
+// or more than one person matches.
+func Resolve(handle string, ps []Profile) (Profile, bool) {
+	var match Profile
+	h := strings.ToLower(handle)
+
+	for _, p := range ps {
+		email := strings.ToLower(p.Email)
+		local := strings.SplitN(email, "@", 2)[0]
+
+		if h != email && h != local && h != strings.ToLower(p.Nickname) {
+			continue
+		}
+		if match.UserID != "" && match.UserID != p.UserID {
+			return Profile{}, false
+		}
+		match = p
+	}
+
+	return match, match.UserID != ""
+}
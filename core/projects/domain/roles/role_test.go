@@ -0,0 +1,36 @@
+package roles
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	ps := []Profile{
+		{UserID: "1", Email: "ana.silva@example.com", Nickname: "Ana"},
+		{UserID: "2", Email: "bob@example.com", Nickname: "Bob"},
+		{UserID: "3", Email: "bob@other.test", Nickname: "Robert"},
+	}
+
+	testcases := []struct {
+		name   string
+		handle string
+		want   string
+	}{
+		{"email", "ana.silva@example.com", "1"},
+		{"email local part", "ana.silva", "1"},
+		{"nickname", "ana", "1"},
+		{"ambiguous", "bob", ""},
+		{"unique email", "bob@other.test", "3"},
+		{"unknown", "carol", ""},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, ok := Resolve(tc.handle, ps)
+			assert.Equal(t, tc.want != "", ok)
+			assert.Equal(t, tc.want, p.UserID)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package stats
+
+import "time"
+
+// Intervals of a trend.
+const (
+	Day  = "day"
+	Week = "week"
+)
+
+// Stats summarizes the tasks of a project. Unassigned and Overdue only count open tasks,
+// those outside the done column, and a task with several labels counts towards each of them.
+type Stats struct {
+	ProjectID  string   `json:"projectId"`
+	Tasks      int      `json:"tasks"`
+	Points     int      `json:"points"`
+	Unassigned int      `json:"unassigned"`
+	Overdue    int      `json:"overdue"`
+	ByColumn   []Bucket `json:"byColumn"`
+	ByAssignee []Bucket `json:"byAssignee"`
+	ByLabel    []Bucket `json:"byLabel"`
+	Trend      Trend    `json:"trend"`
+}
+
+// Bucket counts the tasks and points of a column, assignee or label. Name is the title of
+// a column and is empty otherwise.
+type Bucket struct {
+	Key    string `db:"key" json:"key"`
+	Name   string `db:"name" json:"name,omitempty"`
+	Tasks  int    `db:"tasks" json:"tasks"`
+	Points int    `db:"points" json:"points"`
+}
+
+// Trend counts the tasks created and completed in each interval of a period.
+type Trend struct {
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Interval string    `json:"interval"`
+	Points   []Point   `json:"points"`
+}
+
+// Point is an interval of a trend, starting at Start.
+type Point struct {
+	Start     time.Time `db:"start" json:"start"`
+	Created   int       `db:"created" json:"created"`
+	Completed int       `db:"completed" json:"completed"`
+}
@@ -0,0 +1,114 @@
+package stats
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/columns"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrInvalidID       = errors.New("id provided was not a valid UUID")
+	ErrInvalidInterval = errors.New("trends can only be grouped by day or week")
+	ErrInvalidRange    = errors.New("trends can't span more than a year of days")
+)
+
+// maxPoints bounds the number of intervals in a trend.
+const maxPoints = 366
+
+// open matches the tasks that are not in their project's done column. Column membership is
+// used rather than completed_at, which tasks finished before it was added never got. It
+// expects the lowercase title of the done column as its argument.
+const open = `NOT EXISTS (SELECT 1 FROM columns c WHERE c.project_id = t.project_id
+	AND LOWER(c.title) = ? AND t.task_id = ANY(c.task_ids))`
+
+// Compute gathers the statistics of a project, with a trend over [from, to) in days or
+// weeks. Client statistics leave out internal tasks and points.
+func Compute(ctx context.Context, repo database.Storer, pid string, from, to, now time.Time, interval string, client bool) (Stats, error) {
+	s := Stats{ProjectID: pid}
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return s, ErrInvalidID
+	}
+	step := map[string]time.Duration{Day: 24 * time.Hour, Week: 7 * 24 * time.Hour}[interval]
+	if step == 0 {
+		return s, ErrInvalidInterval
+	}
+	if to.Sub(from)/step > maxPoints {
+		return s, ErrInvalidRange
+	}
+
+	visible := ""
+	if client {
+		visible = " AND NOT t.internal"
+	}
+
+	q := repo.Rebind(`SELECT COUNT(*), COALESCE(SUM(t.points), 0),
+		COUNT(*) FILTER (WHERE t.assigned_to = '' AND ` + open + `),
+		COUNT(*) FILTER (WHERE t.due_date < ? AND ` + open + `)
+		FROM tasks t WHERE t.project_id = ?` + visible)
+	done := strings.ToLower(columns.Done)
+	if err := repo.QueryRowxContext(ctx, q, done, now.UTC(), done, pid).Scan(&s.Tasks, &s.Points, &s.Unassigned, &s.Overdue); err != nil {
+		return s, errors.Wrap(err, "counting tasks")
+	}
+
+	s.ByColumn = make([]Bucket, 0)
+	q = repo.Rebind(`SELECT c.column_id AS key, c.title AS name, COUNT(t.task_id) AS tasks,
+		COALESCE(SUM(t.points), 0) AS points
+		FROM columns c JOIN projects p ON p.project_id = c.project_id
+		LEFT JOIN tasks t ON t.task_id = ANY(c.task_ids)` + visible + `
+		WHERE c.project_id = ?
+		GROUP BY c.column_id, c.title, c.column_name, p.column_order
+		ORDER BY array_position(p.column_order, c.column_name), c.column_name`)
+	if err := repo.SelectContext(ctx, &s.ByColumn, q, pid); err != nil {
+		return s, errors.Wrap(err, "counting tasks per column")
+	}
+
+	s.ByAssignee = make([]Bucket, 0)
+	q = repo.Rebind(`SELECT t.assigned_to AS key, COUNT(*) AS tasks, COALESCE(SUM(t.points), 0) AS points
+		FROM tasks t WHERE t.project_id = ? AND t.assigned_to <> ''` + visible + `
+		GROUP BY t.assigned_to ORDER BY tasks DESC, key`)
+	if err := repo.SelectContext(ctx, &s.ByAssignee, q, pid); err != nil {
+		return s, errors.Wrap(err, "counting tasks per assignee")
+	}
+
+	s.ByLabel = make([]Bucket, 0)
+	q = repo.Rebind(`SELECT l.label AS key, COUNT(*) AS tasks, COALESCE(SUM(t.points), 0) AS points
+		FROM tasks t, unnest(t.labels) AS l(label) WHERE t.project_id = ?` + visible + `
+		GROUP BY l.label ORDER BY tasks DESC, key`)
+	if err := repo.SelectContext(ctx, &s.ByLabel, q, pid); err != nil {
+		return s, errors.Wrap(err, "counting tasks per label")
+	}
+
+	s.Trend = Trend{From: from.UTC(), To: to.UTC(), Interval: interval, Points: make([]Point, 0)}
+	q = repo.Rebind(`WITH intervals AS (
+			SELECT generate_series(date_trunc(?, ?::timestamp), ?::timestamp - interval '1 microsecond',
+			('1 ' || ?)::interval) AS start
+		)
+		SELECT i.start,
+		(SELECT COUNT(*) FROM tasks t WHERE t.project_id = ? AND t.created_at >= i.start
+			AND t.created_at < i.start + ('1 ' || ?)::interval` + visible + `) AS created,
+		(SELECT COUNT(*) FROM tasks t WHERE t.project_id = ? AND t.completed_at >= i.start
+			AND t.completed_at < i.start + ('1 ' || ?)::interval` + visible + `) AS completed
+		FROM intervals i ORDER BY i.start`)
+	if err := repo.SelectContext(ctx, &s.Trend.Points, q, interval, s.Trend.From, s.Trend.To, interval,
+		pid, interval, pid, interval); err != nil {
+		return s, errors.Wrap(err, "counting created and completed tasks")
+	}
+
+	if client {
+		s.Points = 0
+		for _, bs := range [][]Bucket{s.ByColumn, s.ByAssignee, s.ByLabel} {
+			for i := range bs {
+				bs[i].Points = 0
+			}
+		}
+	}
+
+	return s, nil
+}
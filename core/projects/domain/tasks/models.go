@@ -1,37 +1,160 @@
 package tasks
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
 	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/platform/markdown"
 )
 
 type Task struct {
-	ID          string    `db:"task_id" json:"id"`
-	Key         string    `db:"key" json:"key"`
-	Seq         int       `db:"seq" json:"seq"`
-	Title       string    `db:"title" json:"title"`
-	Points      int       `db:"points" json:"points"`
-	Content     string    `db:"content" json:"content"`
-	ProjectID   string    `db:"project_id" json:"projectId"`
-	AssignedTo  string    `db:"assigned_to" json:"assignedTo"`
-	Attachments []string  `db:"attachments" json:"attachments"`
-	Comments    []string  `db:"comments" json:"comments"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updatedAt"`
-	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
+	ID               string     `db:"task_id" json:"id"`
+	Key              string     `db:"key" json:"key"`
+	Seq              int        `db:"seq" json:"seq"`
+	Title            string     `db:"title" json:"title"`
+	Points           int        `db:"points" json:"points"`
+	Content          string     `db:"content" json:"content"`
+	Labels           []string   `db:"labels" json:"labels"`
+	ProjectID        string     `db:"project_id" json:"projectId"`
+	AssignedTo       string     `db:"assigned_to" json:"assignedTo"`
+	Attachments      []string   `db:"attachments" json:"attachments"`
+	Comments         []string   `db:"comments" json:"comments"`
+	MilestoneID      string     `db:"milestone_id" json:"milestoneId"`
+	NeedsApproval    bool       `db:"needs_approval" json:"needsApproval"`
+	ApprovalStatus   string     `db:"approval_status" json:"approvalStatus"`
+	Approvers        []string   `db:"approvers" json:"approvers"`
+	Internal         bool       `db:"internal" json:"internal"`
+	InternalComments []string   `db:"internal_comments" json:"internalComments"`
+	DueDate          *time.Time `db:"due_date" json:"dueDate"`
+	CompletedAt      *time.Time `db:"completed_at" json:"completedAt"`
+	RecurrenceID     string     `db:"recurrence_id" json:"recurrenceId"`
+	Fields           Fields     `db:"custom_fields" json:"fields"`
+	Checklist        Checklist  `db:"-" json:"checklist"`
+	UpdatedAt        time.Time  `db:"updated_at" json:"updatedAt"`
+	CreatedAt        time.Time  `db:"created_at" json:"createdAt"`
+
+	ContentHTML          string   `db:"-" json:"contentHtml"`
+	CommentsHTML         []string `db:"-" json:"commentsHtml"`
+	InternalCommentsHTML []string `db:"-" json:"internalCommentsHtml"`
+}
+
+// Checklist summarizes the progress of a task's checklist.
+type Checklist struct {
+	Total   int `json:"total"`
+	Done    int `json:"done"`
+	Percent int `json:"percent"`
 }
 
+// NewTask is the input for creating a task. With a TemplateID, the template's title pattern,
+// content, labels, points and checklist are used for whatever the request leaves out.
 type NewTask struct {
-	Title string `json:"title" validate:"required"`
+	Title      string   `json:"title" validate:"required_without=TemplateID"`
+	Content    string   `json:"content"`
+	Labels     []string `json:"labels" validate:"omitempty,dive,min=1,max=32"`
+	Points     int      `json:"points" validate:"min=0"`
+	Internal   bool     `json:"internal"`
+	TemplateID string   `json:"templateId" validate:"omitempty,uuid"`
+
+	// RecurrenceID is set when a recurring series creates the task, and Occurrence numbers
+	// the task within the series. Each occurrence is only created once.
+	RecurrenceID string `json:"-"`
+	Occurrence   int    `json:"-"`
 }
 
 type UpdateTask struct {
-	Title       *string   `json:"title"`
-	Key         *string   `json:"key"`
-	Points      *int      `json:"points"`
-	Content     *string   `json:"content"`
-	AssignedTo  *string   `json:"assignedTo"`
-	Attachments []string  `json:"attachments"`
-	Comments    []string  `json:"comments"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	Title            *string    `json:"title"`
+	Key              *string    `json:"key"`
+	Points           *int       `json:"points"`
+	Content          *string    `json:"content"`
+	Labels           []string   `json:"labels" validate:"omitempty,dive,min=1,max=32"`
+	AssignedTo       *string    `json:"assignedTo"`
+	Attachments      []string   `json:"attachments"`
+	Comments         []string   `json:"comments"`
+	MilestoneID      *string    `json:"milestoneId"`
+	DueDate          *time.Time `json:"dueDate"`
+	NeedsApproval    *bool      `json:"needsApproval"`
+	Internal         *bool      `json:"internal"`
+	InternalComments []string   `json:"internalComments"`
+	Fields           Fields     `json:"fields"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+}
+
+// NewComment is a comment added to a task. Internal comments are only shown to the team.
+type NewComment struct {
+	Content  string `json:"content" validate:"required"`
+	Internal bool   `json:"internal"`
+}
+
+// Fields holds the values of a task's custom fields by field ID. In an update, a nil value
+// clears the field and fields left out keep their value.
+type Fields map[string]interface{}
+
+// Scan reads custom field values stored as JSON.
+func (f *Fields) Scan(src interface{}) error {
+	b, ok := src.([]byte)
+	if !ok {
+		return errors.Errorf("unexpected type %T for custom fields", src)
+	}
+	*f = make(Fields)
+	return json.Unmarshal(b, f)
+}
+
+// Value stores custom field values as JSON.
+func (f Fields) Value() (driver.Value, error) {
+	if f == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(f)
+	return string(b), err
+}
+
+// Filter narrows down and orders the tasks of a project. Tasks match when they have every
+// label in Labels, and Fields matches custom field values by field ID: the value of a
+// multiselect field must include the given option, and other fields must equal it. Sort
+// names one of the sorts below, prefixed with - for descending order.
+type Filter struct {
+	AssignedTo  string            `json:"assignedTo,omitempty"`
+	Labels      []string          `json:"labels,omitempty"`
+	MilestoneID string            `json:"milestoneId,omitempty"`
+	Fields      map[string]string `json:"fields,omitempty"`
+	Sort        string            `json:"sort,omitempty"`
+}
+
+// sorts maps the sorts of a filter to their columns.
+var sorts = map[string]string{
+	"key":     "seq",
+	"title":   "LOWER(title)",
+	"points":  "points",
+	"due":     "due_date",
+	"created": "created_at",
+	"updated": "updated_at",
+}
+
+// Scan reads a filter stored as JSON.
+func (f *Filter) Scan(src interface{}) error {
+	b, ok := src.([]byte)
+	if !ok {
+		return errors.Errorf("unexpected type %T for task filter", src)
+	}
+	return json.Unmarshal(b, f)
+}
+
+// Value stores a filter as JSON.
+func (f Filter) Value() (driver.Value, error) {
+	b, err := json.Marshal(f)
+	return string(b), err
+}
+
+// Validate checks that the filter sorts by a known sort.
+func (f Filter) Validate() error {
+	if _, ok := sorts[strings.TrimPrefix(f.Sort, "-")]; f.Sort != "" && !ok {
+		return ErrInvalidSort
+	}
+	return nil
 }
 
 type MoveTask struct {
@@ -39,3 +162,27 @@ type MoveTask struct {
 	From    string   `json:"from"`
 	TaskIds []string `json:"taskIds"`
 }
+
+// ClientView returns the task as users with the client role see it, without internal
+// comments and estimates.
+func (t Task) ClientView() Task {
+	t.Points = 0
+	t.InternalComments = make([]string, 0)
+	t.InternalCommentsHTML = make([]string, 0)
+	return t
+}
+
+// Render fills in the sanitized HTML of the task's Markdown content and comments.
+func (t *Task) Render(l markdown.Linker) {
+	t.ContentHTML = markdown.Render(t.Content, l)
+	t.CommentsHTML = renderAll(t.Comments, l)
+	t.InternalCommentsHTML = renderAll(t.InternalComments, l)
+}
+
+func renderAll(srcs []string, l markdown.Linker) []string {
+	out := make([]string, len(srcs))
+	for i, src := range srcs {
+		out[i] = markdown.Render(src, l)
+	}
+	return out
+}
@@ -18,8 +18,12 @@ import (
 )
 
 var (
-	ErrNotFound  = errors.New("task not found")
-	ErrInvalidID = errors.New("id provided was not a valid UUID")
+	ErrNotFound    = errors.New("task not found")
+	ErrInvalidID   = errors.New("id provided was not a valid UUID")
+	ErrInvalidSort = errors.New("tasks can only be sorted by key, title, points, due, created or updated")
+	ErrTeamOnly    = errors.New("clients can't change what only the team can see")
+	ErrApproval    = errors.New("task was sent for approval; withdraw the request instead")
+	ErrAwaiting    = errors.New("task is awaiting client approval and can't be made internal")
 )
 
 func Retrieve(ctx context.Context, repo *database.Repository, tid string) (Task, error) {
@@ -36,9 +40,22 @@ func Retrieve(ctx context.Context, repo *database.Repository, tid string) (Task,
 		"title",
 		"points",
 		"content",
+		"labels",
 		"assigned_to",
 		"attachments",
 		"comments",
+		"milestone_id",
+		"needs_approval",
+		"approval_status",
+		"approvers",
+		"internal",
+		"internal_comments",
+		"due_date",
+		"completed_at",
+		"recurrence_id",
+		"custom_fields",
+		"(SELECT COUNT(*) FROM checklist_items ci WHERE ci.task_id = tasks.task_id) AS checklist_total",
+		"(SELECT COUNT(*) FROM checklist_items ci WHERE ci.task_id = tasks.task_id AND ci.done) AS checklist_done",
 		"project_id",
 		"updated_at",
 		"created_at",
@@ -51,21 +68,32 @@ func Retrieve(ctx context.Context, repo *database.Repository, tid string) (Task,
 		return t, errors.Wrapf(err, "building query: %v", args)
 	}
 
-	err = repo.QueryRowxContext(ctx, q, tid).Scan(&t.ID, &t.Key, &t.Seq, &t.Title, &t.Points, &t.Content, &t.AssignedTo, (*pq.StringArray)(&t.Attachments), (*pq.StringArray)(&t.Comments), &t.ProjectID, &t.UpdatedAt, &t.CreatedAt)
+	err = repo.QueryRowxContext(ctx, q, tid).Scan(&t.ID, &t.Key, &t.Seq, &t.Title, &t.Points, &t.Content, (*pq.StringArray)(&t.Labels), &t.AssignedTo, (*pq.StringArray)(&t.Attachments), (*pq.StringArray)(&t.Comments), &t.MilestoneID, &t.NeedsApproval, &t.ApprovalStatus, (*pq.StringArray)(&t.Approvers), &t.Internal, (*pq.StringArray)(&t.InternalComments), &t.DueDate, &t.CompletedAt, &t.RecurrenceID, &t.Fields, &t.Checklist.Total, &t.Checklist.Done, &t.ProjectID, &t.UpdatedAt, &t.CreatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return t, ErrNotFound
 		}
 		return t, err
 	}
+	t.Checklist.Percent = percent(t.Checklist.Done, t.Checklist.Total)
 
 	return t, nil
 }
 
 func List(ctx context.Context, repo *database.Repository, pid string) ([]Task, error) {
+	return Find(ctx, repo, pid, Filter{})
+}
+
+// Find returns the tasks of a project that match a filter, in key order unless the filter
+// sorts them otherwise.
+func Find(ctx context.Context, repo *database.Repository, pid string, f Filter) ([]Task, error) {
 	var t Task
 	var ts = make([]Task, 0)
 
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
 	stmt := repo.Select(
 		"task_id",
 		"key",
@@ -73,27 +101,67 @@ func List(ctx context.Context, repo *database.Repository, pid string) ([]Task, e
 		"title",
 		"points",
 		"content",
+		"labels",
 		"assigned_to",
 		"attachments",
 		"comments",
+		"milestone_id",
+		"needs_approval",
+		"approval_status",
+		"approvers",
+		"internal",
+		"internal_comments",
+		"due_date",
+		"completed_at",
+		"recurrence_id",
+		"custom_fields",
+		"(SELECT COUNT(*) FROM checklist_items ci WHERE ci.task_id = tasks.task_id) AS checklist_total",
+		"(SELECT COUNT(*) FROM checklist_items ci WHERE ci.task_id = tasks.task_id AND ci.done) AS checklist_done",
 		"project_id",
 		"updated_at",
 		"created_at",
-	).From("tasks").Where(sq.Eq{"project_id": "?"})
+	).From("tasks").Where(sq.Eq{"project_id": pid})
+
+	if f.AssignedTo != "" {
+		stmt = stmt.Where(sq.Eq{"assigned_to": f.AssignedTo})
+	}
+	if len(f.Labels) > 0 {
+		stmt = stmt.Where("labels @> ?", pq.Array(f.Labels))
+	}
+	if f.MilestoneID != "" {
+		stmt = stmt.Where(sq.Eq{"milestone_id": f.MilestoneID})
+	}
+	for id, v := range f.Fields {
+		stmt = stmt.Where("(custom_fields->>?::text = ? OR custom_fields->?::text @> to_jsonb(?::text))", id, v, id, v)
+	}
+
+	order := "seq"
+	if f.Sort != "" {
+		order = sorts[strings.TrimPrefix(f.Sort, "-")]
+		if strings.HasPrefix(f.Sort, "-") {
+			order += " DESC NULLS LAST"
+		} else {
+			order += " NULLS LAST"
+		}
+		order += ", seq"
+	}
+	stmt = stmt.OrderBy(order)
+
 	q, args, err := stmt.ToSql()
 	if err != nil {
 		return nil, errors.Wrapf(err, "building query: %v", args)
 	}
 
-	rows, err := repo.QueryxContext(ctx, q, pid)
+	rows, err := repo.QueryxContext(ctx, q, args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "selecting tasks")
 	}
 	for rows.Next() {
-		err = rows.Scan(&t.ID, &t.Key, &t.Seq, &t.Title, &t.Points, &t.Content, &t.AssignedTo, (*pq.StringArray)(&t.Attachments), (*pq.StringArray)(&t.Comments), &t.ProjectID, &t.UpdatedAt, &t.CreatedAt)
+		err = rows.Scan(&t.ID, &t.Key, &t.Seq, &t.Title, &t.Points, &t.Content, (*pq.StringArray)(&t.Labels), &t.AssignedTo, (*pq.StringArray)(&t.Attachments), (*pq.StringArray)(&t.Comments), &t.MilestoneID, &t.NeedsApproval, &t.ApprovalStatus, (*pq.StringArray)(&t.Approvers), &t.Internal, (*pq.StringArray)(&t.InternalComments), &t.DueDate, &t.CompletedAt, &t.RecurrenceID, &t.Fields, &t.Checklist.Total, &t.Checklist.Done, &t.ProjectID, &t.UpdatedAt, &t.CreatedAt)
 		if err != nil {
 			return nil, errors.Wrap(err, "scanning row into Struct")
 		}
+		t.Checklist.Percent = percent(t.Checklist.Done, t.Checklist.Total)
 		ts = append(ts, t)
 	}
 
@@ -150,28 +218,48 @@ func Create(ctx context.Context, repo *database.Repository, nt NewTask, pid, uid
 
 	k := fmt.Sprintf("%s%d", p.Prefix, seq)
 
+	if nt.Labels == nil {
+		nt.Labels = make([]string, 0)
+	}
+
 	t = Task{
-		ID:          uuid.New().String(),
-		Key:         k,
-		Title:       nt.Title,
-		ProjectID:   pid,
-		Comments:    make([]string, 0),
-		Attachments: make([]string, 0),
+		ID:               uuid.New().String(),
+		Key:              k,
+		Title:            nt.Title,
+		Points:           nt.Points,
+		Content:          nt.Content,
+		Labels:           nt.Labels,
+		ProjectID:        pid,
+		Comments:         make([]string, 0),
+		Attachments:      make([]string, 0),
+		Approvers:        make([]string, 0),
+		Internal:         nt.Internal,
+		InternalComments: make([]string, 0),
+		RecurrenceID:     nt.RecurrenceID,
+		Fields:           make(Fields),
+		UpdatedAt:        now.UTC(),
+		CreatedAt:        now.UTC(),
 	}
 
 	stmt2 := repo.Insert(
 		"tasks",
 	).SetMap(map[string]interface{}{
-		"task_id":     t.ID,
-		"key":         t.Key,
-		"title":       t.Title,
-		"content":     t.Content,
-		"assigned_to": t.AssignedTo,
-		"attachments": pq.Array(t.Attachments),
-		"comments":    pq.Array(t.Comments),
-		"project_id":  t.ProjectID,
-		"updated_at":  now.UTC(),
-		"created_at":  now.UTC(),
+		"task_id":       t.ID,
+		"key":           t.Key,
+		"title":         t.Title,
+		"points":        t.Points,
+		"content":       t.Content,
+		"labels":        pq.Array(t.Labels),
+		"assigned_to":   t.AssignedTo,
+		"attachments":   pq.Array(t.Attachments),
+		"comments":      pq.Array(t.Comments),
+		"internal":      t.Internal,
+		"project_id":    t.ProjectID,
+		"recurrence_id": t.RecurrenceID,
+		"occurrence":    nt.Occurrence,
+		"custom_fields": t.Fields,
+		"updated_at":    t.UpdatedAt,
+		"created_at":    t.CreatedAt,
 	})
 
 	if _, err := stmt2.ExecContext(ctx); err != nil {
@@ -181,6 +269,22 @@ func Create(ctx context.Context, repo *database.Repository, nt NewTask, pid, uid
 	return t, nil
 }
 
+// RetrieveOccurrence returns the task a recurring series created for an occurrence.
+func RetrieveOccurrence(ctx context.Context, repo *database.Repository, rid string, occurrence int) (Task, error) {
+	var tid string
+
+	q := repo.Rebind(`SELECT task_id FROM tasks WHERE recurrence_id = ? AND occurrence = ?`)
+
+	if err := repo.QueryRowxContext(ctx, q, rid, occurrence).Scan(&tid); err != nil {
+		if err == sql.ErrNoRows {
+			return Task{}, ErrNotFound
+		}
+		return Task{}, errors.Wrapf(err, "looking for occurrence %d of recurrence %s", occurrence, rid)
+	}
+
+	return Retrieve(ctx, repo, tid)
+}
+
 func Update(ctx context.Context, repo *database.Repository, tid string, update UpdateTask, now time.Time) (Task, error) {
 	t, err := Retrieve(ctx, repo, tid)
 	if err != nil {
@@ -193,6 +297,16 @@ func Update(ctx context.Context, repo *database.Repository, tid string, update U
 	if update.Content != nil {
 		t.Content = *update.Content
 	}
+	if update.Labels != nil {
+		t.Labels = update.Labels
+	}
+	for id, v := range update.Fields {
+		if v == nil {
+			delete(t.Fields, id)
+			continue
+		}
+		t.Fields[id] = v
+	}
 	if update.AssignedTo != nil {
 		t.AssignedTo = *update.AssignedTo
 	}
@@ -202,16 +316,51 @@ func Update(ctx context.Context, repo *database.Repository, tid string, update U
 	if update.Comments != nil {
 		t.Comments = update.Comments
 	}
+	if update.MilestoneID != nil {
+		t.MilestoneID = *update.MilestoneID
+	}
+	if update.DueDate != nil {
+		due := update.DueDate.UTC()
+		t.DueDate = &due
+	}
+	if update.Internal != nil {
+		// approvers are clients, who would lose sight of the task they were asked about
+		if *update.Internal && !t.Internal && t.ApprovalStatus == "requested" {
+			return t, ErrAwaiting
+		}
+		t.Internal = *update.Internal
+	}
+	if update.InternalComments != nil {
+		t.InternalComments = update.InternalComments
+	}
+	if update.NeedsApproval != nil && *update.NeedsApproval != t.NeedsApproval {
+		// once approval was requested, only the approvals history may change it
+		if t.ApprovalStatus != "" {
+			return t, ErrApproval
+		}
+		t.NeedsApproval = *update.NeedsApproval
+		t.Approvers = make([]string, 0)
+	}
+	t.UpdatedAt = now.UTC()
 
 	stmt := repo.Update(
 		"tasks",
 	).SetMap(map[string]interface{}{
-		"title":       t.Title,
-		"content":     t.Content,
-		"assigned_to": t.AssignedTo,
-		"comments":    pq.Array(t.Comments),
-		"attachments": pq.Array(t.Attachments),
-		"updated_at":  now.UTC(),
+		"title":             t.Title,
+		"content":           t.Content,
+		"labels":            pq.Array(t.Labels),
+		"custom_fields":     t.Fields,
+		"assigned_to":       t.AssignedTo,
+		"comments":          pq.Array(t.Comments),
+		"attachments":       pq.Array(t.Attachments),
+		"milestone_id":      t.MilestoneID,
+		"due_date":          t.DueDate,
+		"needs_approval":    t.NeedsApproval,
+		"approval_status":   t.ApprovalStatus,
+		"approvers":         pq.Array(t.Approvers),
+		"internal":          t.Internal,
+		"internal_comments": pq.Array(t.InternalComments),
+		"updated_at":        t.UpdatedAt,
 	}).Where(sq.Eq{"task_id": tid})
 
 	if _, err := stmt.ExecContext(ctx); err != nil {
@@ -254,3 +403,86 @@ func DeleteAll(ctx context.Context, repo *database.Repository, pid string) error
 
 	return nil
 }
+
+// AddComment appends a comment to a task, or to its internal comments.
+func AddComment(ctx context.Context, repo *database.Repository, tid string, nc NewComment, now time.Time) (Task, error) {
+	if _, err := uuid.Parse(tid); err != nil {
+		return Task{}, ErrInvalidID
+	}
+
+	field := "comments"
+	if nc.Internal {
+		field = "internal_comments"
+	}
+
+	stmt := repo.Update(
+		"tasks",
+	).Set(field, sq.Expr("array_append(COALESCE("+field+", '{}'), ?)", nc.Content)).
+		Set("updated_at", now.UTC()).Where(sq.Eq{"task_id": tid})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return Task{}, errors.Wrapf(err, "commenting on task: %s", tid)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return Task{}, ErrNotFound
+	}
+
+	return Retrieve(ctx, repo, tid)
+}
+
+// Complete records when a task reached the done column. A nil time reopens the task.
+func Complete(ctx context.Context, repo *database.Repository, tid string, at *time.Time) error {
+	if _, err := uuid.Parse(tid); err != nil {
+		return ErrInvalidID
+	}
+
+	if at != nil {
+		utc := at.UTC()
+		at = &utc
+	}
+
+	stmt := repo.Update(
+		"tasks",
+	).Set("completed_at", at).Where(sq.Eq{"task_id": tid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "completing task: %s", tid)
+	}
+
+	return nil
+}
+
+// Keys returns the task keys of a project, indexed by their uppercase form. Clients only get
+// the keys of tasks that are not internal.
+func Keys(ctx context.Context, repo *database.Repository, pid string, client bool) (map[string]string, error) {
+	var ks []string
+
+	stmt := repo.Select("key").From("tasks").Where(sq.Eq{"project_id": "?"}).Where("key IS NOT NULL")
+	if client {
+		stmt = stmt.Where("NOT internal")
+	}
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.SelectContext(ctx, &ks, q, pid); err != nil {
+		return nil, errors.Wrap(err, "selecting task keys")
+	}
+
+	keys := make(map[string]string, len(ks))
+	for _, k := range ks {
+		keys[strings.ToUpper(k)] = k
+	}
+
+	return keys, nil
+}
+
+func percent(n, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return n * 100 / total
+}
@@ -0,0 +1,38 @@
+package templates
+
+import "time"
+
+// Template is a reusable starting point for the tasks of a project. TitlePattern may contain
+// the placeholders {title} and {date}, and Checklist holds the text of the checklist items
+// added to every task created from it.
+type Template struct {
+	ID           string    `db:"template_id" json:"id"`
+	ProjectID    string    `db:"project_id" json:"projectId"`
+	Name         string    `db:"name" json:"name"`
+	TitlePattern string    `db:"title_pattern" json:"titlePattern"`
+	Content      string    `db:"content" json:"content"`
+	Labels       []string  `db:"labels" json:"labels"`
+	Points       int       `db:"points" json:"points"`
+	Checklist    []string  `db:"checklist" json:"checklist"`
+	CreatedBy    string    `db:"created_by" json:"createdBy"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updatedAt"`
+	CreatedAt    time.Time `db:"created_at" json:"createdAt"`
+}
+
+type NewTemplate struct {
+	Name         string   `json:"name" validate:"required,max=64"`
+	TitlePattern string   `json:"titlePattern" validate:"max=128"`
+	Content      string   `json:"content"`
+	Labels       []string `json:"labels" validate:"omitempty,dive,min=1,max=32"`
+	Points       int      `json:"points" validate:"min=0"`
+	Checklist    []string `json:"checklist" validate:"omitempty,dive,min=1"`
+}
+
+type UpdateTemplate struct {
+	Name         *string  `json:"name" validate:"omitempty,min=1,max=64"`
+	TitlePattern *string  `json:"titlePattern" validate:"omitempty,max=128"`
+	Content      *string  `json:"content"`
+	Labels       []string `json:"labels" validate:"omitempty,dive,min=1,max=32"`
+	Points       *int     `json:"points" validate:"omitempty,min=0"`
+	Checklist    []string `json:"checklist" validate:"omitempty,dive,min=1"`
+}
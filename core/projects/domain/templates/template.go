@@ -0,0 +1,285 @@
+package templates
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/tasks"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrNotFound   = errors.New("template not found")
+	ErrInvalidID  = errors.New("id provided was not a valid UUID")
+	ErrNameTaken  = errors.New("a template with this name already exists in the project")
+	ErrEmptyTitle = errors.New("task title is required when the template has no title pattern")
+)
+
+// uniqueViolation is the Postgres error code for a broken unique constraint.
+const uniqueViolation = "23505"
+
+// Create adds a task template to a project.
+func Create(ctx context.Context, repo database.Storer, nt NewTemplate, pid, uid string, now time.Time) (Template, error) {
+	var t Template
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return t, ErrInvalidID
+	}
+
+	t = Template{
+		ID:           uuid.New().String(),
+		ProjectID:    pid,
+		Name:         nt.Name,
+		TitlePattern: nt.TitlePattern,
+		Content:      nt.Content,
+		Labels:       nt.Labels,
+		Points:       nt.Points,
+		Checklist:    nt.Checklist,
+		CreatedBy:    uid,
+		UpdatedAt:    now.UTC(),
+		CreatedAt:    now.UTC(),
+	}
+
+	if t.Labels == nil {
+		t.Labels = make([]string, 0)
+	}
+	if t.Checklist == nil {
+		t.Checklist = make([]string, 0)
+	}
+
+	stmt := repo.Insert(
+		"task_templates",
+	).SetMap(map[string]interface{}{
+		"template_id":   t.ID,
+		"project_id":    t.ProjectID,
+		"name":          t.Name,
+		"title_pattern": t.TitlePattern,
+		"content":       t.Content,
+		"labels":        pq.Array(t.Labels),
+		"points":        t.Points,
+		"checklist":     pq.Array(t.Checklist),
+		"created_by":    t.CreatedBy,
+		"updated_at":    t.UpdatedAt,
+		"created_at":    t.CreatedAt,
+	})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		if isUniqueViolation(err) {
+			return t, ErrNameTaken
+		}
+		return t, errors.Wrapf(err, "inserting task template: %v", nt)
+	}
+
+	return t, nil
+}
+
+// List returns the task templates of a project by name.
+func List(ctx context.Context, repo database.Storer, pid string) ([]Template, error) {
+	var ts = make([]Template, 0)
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	stmt := selectTemplates(repo).Where(sq.Eq{"project_id": "?"}).OrderBy("name")
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	rows, err := repo.QueryxContext(ctx, q, pid)
+	if err != nil {
+		return nil, errors.Wrap(err, "selecting task templates")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scan(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "scanning task template")
+		}
+		ts = append(ts, t)
+	}
+
+	return ts, rows.Err()
+}
+
+// Retrieve returns a task template of a project.
+func Retrieve(ctx context.Context, repo database.Storer, pid, tid string) (Template, error) {
+	var t Template
+
+	if _, err := uuid.Parse(tid); err != nil {
+		return t, ErrInvalidID
+	}
+
+	stmt := selectTemplates(repo).Where(sq.Eq{"project_id": "?", "template_id": "?"})
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return t, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	t, err = scan(repo.QueryRowxContext(ctx, q, pid, tid))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return t, ErrNotFound
+		}
+		return t, err
+	}
+
+	return t, nil
+}
+
+// Update modifies a task template of a project.
+func Update(ctx context.Context, repo database.Storer, pid, tid string, update UpdateTemplate, now time.Time) (Template, error) {
+	t, err := Retrieve(ctx, repo, pid, tid)
+	if err != nil {
+		return t, err
+	}
+
+	if update.Name != nil {
+		t.Name = *update.Name
+	}
+	if update.TitlePattern != nil {
+		t.TitlePattern = *update.TitlePattern
+	}
+	if update.Content != nil {
+		t.Content = *update.Content
+	}
+	if update.Labels != nil {
+		t.Labels = update.Labels
+	}
+	if update.Points != nil {
+		t.Points = *update.Points
+	}
+	if update.Checklist != nil {
+		t.Checklist = update.Checklist
+	}
+	t.UpdatedAt = now.UTC()
+
+	stmt := repo.Update(
+		"task_templates",
+	).SetMap(map[string]interface{}{
+		"name":          t.Name,
+		"title_pattern": t.TitlePattern,
+		"content":       t.Content,
+		"labels":        pq.Array(t.Labels),
+		"points":        t.Points,
+		"checklist":     pq.Array(t.Checklist),
+		"updated_at":    t.UpdatedAt,
+	}).Where(sq.Eq{"project_id": pid, "template_id": tid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		if isUniqueViolation(err) {
+			return t, ErrNameTaken
+		}
+		return t, errors.Wrapf(err, "updating task template: %s", tid)
+	}
+
+	return t, nil
+}
+
+// Delete removes a task template of a project. Tasks created from it are left alone.
+func Delete(ctx context.Context, repo database.Storer, pid, tid string) error {
+	if _, err := uuid.Parse(tid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"task_templates",
+	).Where(sq.Eq{"project_id": pid, "template_id": tid})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "deleting task template %s", tid)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteAll removes every task template of a project.
+func DeleteAll(ctx context.Context, repo database.Storer, pid string) error {
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"task_templates",
+	).Where(sq.Eq{"project_id": pid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting all task templates")
+	}
+
+	return nil
+}
+
+// Apply fills in what a new task leaves out from the template: the title from the title
+// pattern, then the content, labels and points. {title} in the pattern is replaced with the
+// title of the request and {date} with the current date.
+func (t Template) Apply(nt tasks.NewTask, now time.Time) (tasks.NewTask, error) {
+	if t.TitlePattern != "" {
+		r := strings.NewReplacer("{title}", nt.Title, "{date}", now.UTC().Format("2006-01-02"))
+		nt.Title = strings.TrimSpace(r.Replace(t.TitlePattern))
+	}
+	if nt.Title == "" {
+		return nt, ErrEmptyTitle
+	}
+	if nt.Content == "" {
+		nt.Content = t.Content
+	}
+	if nt.Labels == nil {
+		nt.Labels = t.Labels
+	}
+	if nt.Points == 0 {
+		nt.Points = t.Points
+	}
+
+	return nt, nil
+}
+
+func selectTemplates(repo database.Storer) sq.SelectBuilder {
+	return repo.Select(
+		"template_id",
+		"project_id",
+		"name",
+		"title_pattern",
+		"content",
+		"labels",
+		"points",
+		"checklist",
+		"created_by",
+		"updated_at",
+		"created_at",
+	).From("task_templates")
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scan(row scanner) (Template, error) {
+	var t Template
+
+	err := row.Scan(&t.ID, &t.ProjectID, &t.Name, &t.TitlePattern, &t.Content, (*pq.StringArray)(&t.Labels),
+		&t.Points, (*pq.StringArray)(&t.Checklist), &t.CreatedBy, &t.UpdatedAt, &t.CreatedAt)
+
+	return t, err
+}
+
+func isUniqueViolation(err error) bool {
+	pqErr, ok := errors.Cause(err).(*pq.Error)
+	return ok && pqErr.Code == uniqueViolation
+}
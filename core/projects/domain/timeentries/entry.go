@@ -0,0 +1,404 @@
+package timeentries
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrNotFound     = errors.New("time entry not found")
+	ErrInvalidID    = errors.New("id provided was not a valid UUID")
+	ErrTimerRunning = errors.New("a timer is already running")
+	ErrNotRunning   = errors.New("time entry is not a running timer")
+	ErrInvalidGroup = errors.New("totals can only be grouped by task, user, day or label")
+	ErrLocked       = errors.New("time entry is locked by a finalized invoice")
+)
+
+// groupings maps report groups to the column they aggregate on, and the column naming the group.
+var groupings = map[string][2]string{
+	"task":  {"e.task_id", "MAX(t.key)"},
+	"user":  {"e.user_id", "''"},
+	"day":   {"to_char(e.started_at, 'YYYY-MM-DD')", "''"},
+	"label": {"e.label", "''"},
+}
+
+var columns = []string{
+	"entry_id",
+	"project_id",
+	"task_id",
+	"user_id",
+	"note",
+	"label",
+	"billable",
+	"started_at",
+	"stopped_at",
+	"duration",
+	"invoice_id",
+	"locked",
+	"updated_at",
+	"created_at",
+}
+
+// Create records time on a task, or starts a timer when no duration is given. A user
+// can only run one timer at a time.
+func Create(ctx context.Context, repo database.Storer, ne NewEntry, pid, tid, uid string, now time.Time) (Entry, error) {
+	var e Entry
+
+	if _, err := uuid.Parse(tid); err != nil {
+		return e, ErrInvalidID
+	}
+
+	e = Entry{
+		ID:        uuid.New().String(),
+		ProjectID: pid,
+		TaskID:    tid,
+		UserID:    uid,
+		Note:      ne.Note,
+		Label:     ne.Label,
+		Billable:  true,
+		StartedAt: now.UTC(),
+		UpdatedAt: now.UTC(),
+		CreatedAt: now.UTC(),
+	}
+
+	if ne.Billable != nil {
+		e.Billable = *ne.Billable
+	}
+
+	if ne.Duration != nil {
+		e.Duration = *ne.Duration
+		e.StartedAt = now.Add(-time.Duration(e.Duration) * time.Second).UTC()
+		if ne.StartedAt != nil {
+			e.StartedAt = ne.StartedAt.UTC()
+		}
+		stop := e.StartedAt.Add(time.Duration(e.Duration) * time.Second)
+		e.StoppedAt = &stop
+	} else {
+		if _, err := Running(ctx, repo, uid); err != ErrNotFound {
+			if err == nil {
+				return e, ErrTimerRunning
+			}
+			return e, err
+		}
+	}
+
+	stmt := repo.Insert(
+		"time_entries",
+	).SetMap(map[string]interface{}{
+		"entry_id":   e.ID,
+		"project_id": e.ProjectID,
+		"task_id":    e.TaskID,
+		"user_id":    e.UserID,
+		"note":       e.Note,
+		"label":      e.Label,
+		"billable":   e.Billable,
+		"started_at": e.StartedAt,
+		"stopped_at": e.StoppedAt,
+		"duration":   e.Duration,
+		"updated_at": e.UpdatedAt,
+		"created_at": e.CreatedAt,
+	})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return e, errors.Wrapf(err, "inserting time entry: %v", ne)
+	}
+
+	return e, nil
+}
+
+// Retrieve returns a time entry of a user.
+func Retrieve(ctx context.Context, repo database.Storer, eid, uid string) (Entry, error) {
+	var e Entry
+
+	if _, err := uuid.Parse(eid); err != nil {
+		return e, ErrInvalidID
+	}
+
+	stmt := repo.Select(columns...).From("time_entries").Where(sq.Eq{"entry_id": "?", "user_id": "?"})
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return e, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.QueryRowxContext(ctx, q, eid, uid).StructScan(&e); err != nil {
+		if err == sql.ErrNoRows {
+			return e, ErrNotFound
+		}
+		return e, err
+	}
+
+	return e, nil
+}
+
+// Running returns the timer a user is running.
+func Running(ctx context.Context, repo database.Storer, uid string) (Entry, error) {
+	var e Entry
+
+	stmt := repo.Select(columns...).From("time_entries").Where(sq.Eq{"user_id": "?", "stopped_at": nil})
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return e, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.QueryRowxContext(ctx, q, uid).StructScan(&e); err != nil {
+		if err == sql.ErrNoRows {
+			return e, ErrNotFound
+		}
+		return e, err
+	}
+
+	return e, nil
+}
+
+// List returns a user's time entries started within [from, to), most recent first.
+func List(ctx context.Context, repo database.Storer, uid string, from, to time.Time) ([]Entry, error) {
+	var es = make([]Entry, 0)
+
+	stmt := repo.Select(columns...).From("time_entries").Where(sq.Eq{"user_id": "?"}).
+		Where("started_at >= ? AND started_at < ?").OrderBy("started_at DESC")
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.SelectContext(ctx, &es, q, uid, from.UTC(), to.UTC()); err != nil {
+		return nil, errors.Wrap(err, "selecting time entries")
+	}
+
+	return es, nil
+}
+
+// ListByTask returns the time entries of a task, most recent first.
+func ListByTask(ctx context.Context, repo database.Storer, tid string) ([]Entry, error) {
+	var es = make([]Entry, 0)
+
+	if _, err := uuid.Parse(tid); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	stmt := repo.Select(columns...).From("time_entries").Where(sq.Eq{"task_id": "?"}).OrderBy("started_at DESC")
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.SelectContext(ctx, &es, q, tid); err != nil {
+		return nil, errors.Wrap(err, "selecting time entries")
+	}
+
+	return es, nil
+}
+
+// Update modifies a time entry of a user. The duration of a running timer can't be set.
+func Update(ctx context.Context, repo database.Storer, eid, uid string, update UpdateEntry, now time.Time) (Entry, error) {
+	e, err := Retrieve(ctx, repo, eid, uid)
+	if err != nil {
+		return e, err
+	}
+
+	if e.Locked {
+		return e, ErrLocked
+	}
+
+	if update.Duration != nil && e.StoppedAt == nil {
+		return e, ErrTimerRunning
+	}
+
+	if update.StartedAt != nil {
+		e.StartedAt = update.StartedAt.UTC()
+	}
+	if update.Duration != nil {
+		e.Duration = *update.Duration
+	}
+	if e.StoppedAt != nil {
+		stop := e.StartedAt.Add(time.Duration(e.Duration) * time.Second)
+		e.StoppedAt = &stop
+	}
+	if update.Note != nil {
+		e.Note = *update.Note
+	}
+	if update.Label != nil {
+		e.Label = *update.Label
+	}
+	if update.Billable != nil {
+		e.Billable = *update.Billable
+	}
+	e.UpdatedAt = now.UTC()
+
+	stmt := repo.Update(
+		"time_entries",
+	).SetMap(map[string]interface{}{
+		"note":       e.Note,
+		"label":      e.Label,
+		"billable":   e.Billable,
+		"started_at": e.StartedAt,
+		"stopped_at": e.StoppedAt,
+		"duration":   e.Duration,
+		"updated_at": e.UpdatedAt,
+	}).Where(sq.Eq{"entry_id": eid, "user_id": uid, "locked": false})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return e, errors.Wrapf(err, "updating time entry: %s", eid)
+	}
+
+	return e, nil
+}
+
+// Stop stops a user's running timer and records the elapsed time.
+func Stop(ctx context.Context, repo database.Storer, eid, uid string, now time.Time) (Entry, error) {
+	e, err := Retrieve(ctx, repo, eid, uid)
+	if err != nil {
+		return e, err
+	}
+
+	if e.StoppedAt != nil {
+		return e, ErrNotRunning
+	}
+
+	stop := now.UTC()
+	e.StoppedAt = &stop
+	e.Duration = int(stop.Sub(e.StartedAt) / time.Second)
+	if e.Duration < 1 {
+		e.Duration = 1
+	}
+	e.UpdatedAt = now.UTC()
+
+	stmt := repo.Update(
+		"time_entries",
+	).SetMap(map[string]interface{}{
+		"stopped_at": e.StoppedAt,
+		"duration":   e.Duration,
+		"updated_at": e.UpdatedAt,
+	}).Where(sq.Eq{"entry_id": eid, "user_id": uid, "stopped_at": nil})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return e, errors.Wrapf(err, "stopping time entry: %s", eid)
+	}
+
+	return e, nil
+}
+
+// Delete removes a time entry of a user.
+func Delete(ctx context.Context, repo database.Storer, eid, uid string) error {
+	e, err := Retrieve(ctx, repo, eid, uid)
+	if err != nil {
+		return err
+	}
+
+	if e.Locked {
+		return ErrLocked
+	}
+
+	stmt := repo.Delete(
+		"time_entries",
+	).Where(sq.Eq{"entry_id": eid, "user_id": uid, "locked": false})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "deleting time entry %s", eid)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteByTask removes every time entry of a task. Billed time must be kept, so nothing is
+// removed when an entry of the task is locked or on an invoice.
+func DeleteByTask(ctx context.Context, repo database.Storer, tid string) error {
+	var billed bool
+
+	q := repo.Rebind(`SELECT EXISTS (SELECT 1 FROM time_entries
+		WHERE task_id = ? AND (locked OR invoice_id IS NOT NULL))`)
+
+	if err := repo.QueryRowxContext(ctx, q, tid).Scan(&billed); err != nil {
+		return errors.Wrapf(err, "looking for billed time entries of task %s", tid)
+	}
+	if billed {
+		return ErrLocked
+	}
+
+	stmt := repo.Delete(
+		"time_entries",
+	).Where(sq.Eq{"task_id": tid, "locked": false, "invoice_id": nil})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting time entries of task %s", tid)
+	}
+
+	return nil
+}
+
+// DeleteAll removes every time entry of a project.
+func DeleteAll(ctx context.Context, repo database.Storer, pid string) error {
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"time_entries",
+	).Where(sq.Eq{"project_id": pid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting all time entries")
+	}
+
+	return nil
+}
+
+// Totals sums the stopped time entries of a project started within [from, to), grouped by
+// task, user, day or label. Days are UTC days. Clients only see time on tasks that are not internal.
+func Totals(ctx context.Context, repo database.Storer, pid, group string, from, to time.Time, client bool) ([]Total, error) {
+	var ts = make([]Total, 0)
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	g, ok := groupings[group]
+	if !ok {
+		return nil, ErrInvalidGroup
+	}
+
+	stmt := repo.Select(
+		fmt.Sprintf("%s AS grp", g[0]),
+		fmt.Sprintf("%s AS name", g[1]),
+		"COALESCE(SUM(e.duration), 0) AS duration",
+		"COALESCE(SUM(e.duration) FILTER (WHERE e.billable), 0) AS billable",
+		"COUNT(*) AS entries",
+	).From(
+		"time_entries e",
+	).Join(
+		"tasks t ON t.task_id = e.task_id",
+	).Where(sq.Eq{"e.project_id": "?"}).Where("e.stopped_at IS NOT NULL AND e.started_at >= ? AND e.started_at < ?").
+		GroupBy(g[0]).OrderBy("grp")
+
+	if client {
+		stmt = stmt.Where("NOT t.internal")
+	}
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.SelectContext(ctx, &ts, q, pid, from.UTC(), to.UTC()); err != nil {
+		return nil, errors.Wrap(err, "selecting time totals")
+	}
+
+	return ts, nil
+}
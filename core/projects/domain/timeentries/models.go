@@ -0,0 +1,49 @@
+package timeentries
+
+import "time"
+
+// Entry is time a user spent on a task. Durations are in seconds. An entry without
+// StoppedAt is a running timer; its duration is set when it is stopped. Entries billed on
+// a finalized invoice are locked.
+type Entry struct {
+	ID        string     `db:"entry_id" json:"id"`
+	ProjectID string     `db:"project_id" json:"projectId"`
+	TaskID    string     `db:"task_id" json:"taskId"`
+	UserID    string     `db:"user_id" json:"userId"`
+	Note      string     `db:"note" json:"note"`
+	Label     string     `db:"label" json:"label"`
+	Billable  bool       `db:"billable" json:"billable"`
+	StartedAt time.Time  `db:"started_at" json:"startedAt"`
+	StoppedAt *time.Time `db:"stopped_at" json:"stoppedAt"`
+	Duration  int        `db:"duration" json:"duration"`
+	InvoiceID *string    `db:"invoice_id" json:"invoiceId"`
+	Locked    bool       `db:"locked" json:"locked"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updatedAt"`
+	CreatedAt time.Time  `db:"created_at" json:"createdAt"`
+}
+
+// NewEntry records time on a task. Without a duration it starts a timer instead.
+type NewEntry struct {
+	Duration  *int       `json:"duration" validate:"omitempty,min=1,max=86400"`
+	StartedAt *time.Time `json:"startedAt"`
+	Note      string     `json:"note"`
+	Label     string     `json:"label" validate:"max=48"`
+	Billable  *bool      `json:"billable"`
+}
+
+type UpdateEntry struct {
+	Duration  *int       `json:"duration" validate:"omitempty,min=1,max=86400"`
+	StartedAt *time.Time `json:"startedAt"`
+	Note      *string    `json:"note"`
+	Label     *string    `json:"label" validate:"omitempty,max=48"`
+	Billable  *bool      `json:"billable"`
+}
+
+// Total is the time recorded in one group of a project report.
+type Total struct {
+	Group    string `db:"grp" json:"group"`
+	Name     string `db:"name" json:"name,omitempty"`
+	Duration int    `db:"duration" json:"duration"`
+	Billable int    `db:"billable" json:"billable"`
+	Entries  int    `db:"entries" json:"entries"`
+}
@@ -0,0 +1,101 @@
+package transitions
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+// summary is a Summary as computed by the database.
+type summary struct {
+	Tasks       int             `db:"tasks"`
+	Mean        sql.NullFloat64 `db:"mean"`
+	Percentiles pq.Float64Array `db:"percentiles"`
+}
+
+// aggregates summarizes the seconds of a set of rows. Rows can be picked with a FILTER
+// condition appended to each aggregate.
+func aggregates(seconds, filter string) string {
+	return `COUNT(*)` + filter + ` AS tasks, AVG(` + seconds + `)` + filter + ` AS mean,
+		percentile_cont(ARRAY[0.5, 0.85, 0.95]) WITHIN GROUP (ORDER BY ` + seconds + `)` + filter + ` AS percentiles`
+}
+
+func (s summary) Summary() Summary {
+	sm := Summary{Tasks: s.Tasks, Mean: int(math.Round(s.Mean.Float64))}
+	if len(s.Percentiles) == 3 {
+		sm.P50 = int(math.Round(s.Percentiles[0]))
+		sm.P85 = int(math.Round(s.Percentiles[1]))
+		sm.P95 = int(math.Round(s.Percentiles[2]))
+	}
+	return sm
+}
+
+// Compute measures the lead time, cycle time and column times of a project over [from, to).
+// Lead time runs from the creation of a task to its completion. Cycle time starts when the
+// task first entered a column past the first one of the board, so tasks without such a
+// move are left out of it. Client metrics leave out internal tasks.
+func Compute(ctx context.Context, repo database.Storer, pid string, from, to time.Time, client bool) (Metrics, error) {
+	m := Metrics{ProjectID: pid, From: from.UTC(), To: to.UTC(), Columns: make([]ColumnTime, 0)}
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return m, ErrInvalidID
+	}
+
+	visible := ""
+	if client {
+		visible = " AND NOT t.internal"
+	}
+
+	var lead, cycle summary
+	q := repo.Rebind(`WITH done AS (
+			SELECT EXTRACT(EPOCH FROM t.completed_at - t.created_at) AS lead,
+			EXTRACT(EPOCH FROM t.completed_at - (
+				SELECT MIN(tr.entered_at) FROM task_transitions tr
+				LEFT JOIN columns c ON c.column_id = tr.column_id
+				WHERE tr.task_id = t.task_id AND c.column_name IS DISTINCT FROM p.column_order[1]
+			)) AS cycle
+			FROM tasks t JOIN projects p ON p.project_id = t.project_id
+			WHERE t.project_id = ? AND t.completed_at >= ? AND t.completed_at < ?` + visible + `
+		)
+		SELECT ` + aggregates("lead", "") + `, ` + aggregates("cycle", " FILTER (WHERE cycle >= 0)") + `
+		FROM done`)
+	if err := repo.QueryRowxContext(ctx, q, pid, m.From, m.To).Scan(&lead.Tasks, &lead.Mean, &lead.Percentiles,
+		&cycle.Tasks, &cycle.Mean, &cycle.Percentiles); err != nil {
+		return m, errors.Wrap(err, "measuring lead and cycle times")
+	}
+	m.LeadTime = lead.Summary()
+	m.CycleTime = cycle.Summary()
+
+	var rows []struct {
+		ColumnID string `db:"column_id"`
+		Title    string `db:"title"`
+		summary
+	}
+	q = repo.Rebind(`WITH stays AS (
+			SELECT tr.column_id, MAX(tr.column_title) AS title,
+			SUM(EXTRACT(EPOCH FROM tr.exited_at - tr.entered_at)) AS seconds
+			FROM task_transitions tr JOIN tasks t ON t.task_id = tr.task_id
+			WHERE tr.project_id = ? AND tr.exited_at >= ? AND tr.exited_at < ?` + visible + `
+			GROUP BY tr.column_id, tr.task_id
+		)
+		SELECT s.column_id, COALESCE(c.title, MAX(s.title)) AS title, ` + aggregates("s.seconds", "") + `
+		FROM stays s JOIN projects p ON p.project_id = ?
+		LEFT JOIN columns c ON c.column_id = s.column_id
+		GROUP BY s.column_id, c.title, c.column_name, p.column_order
+		ORDER BY array_position(p.column_order, c.column_name), title`)
+	if err := repo.SelectContext(ctx, &rows, q, pid, m.From, m.To, pid); err != nil {
+		return m, errors.Wrap(err, "measuring column times")
+	}
+	for _, r := range rows {
+		m.Columns = append(m.Columns, ColumnTime{ColumnID: r.ColumnID, Title: r.Title, Time: r.summary.Summary()})
+	}
+
+	return m, nil
+}
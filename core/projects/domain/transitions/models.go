@@ -0,0 +1,42 @@
+package transitions
+
+import "time"
+
+// Transition is a stay of a task in a column of its board. ExitedAt is nil while the task
+// is still there.
+type Transition struct {
+	ID        string     `db:"transition_id" json:"id"`
+	ProjectID string     `db:"project_id" json:"projectId"`
+	TaskID    string     `db:"task_id" json:"taskId"`
+	ColumnID  string     `db:"column_id" json:"columnId"`
+	Column    string     `db:"column_title" json:"column"`
+	EnteredAt time.Time  `db:"entered_at" json:"enteredAt"`
+	ExitedAt  *time.Time `db:"exited_at" json:"exitedAt"`
+}
+
+// Metrics summarizes how long the tasks of a project took over a period. Lead and cycle
+// times cover the tasks completed in the period, column times the stays that ended in it.
+type Metrics struct {
+	ProjectID string       `json:"projectId"`
+	From      time.Time    `json:"from"`
+	To        time.Time    `json:"to"`
+	LeadTime  Summary      `json:"leadTime"`
+	CycleTime Summary      `json:"cycleTime"`
+	Columns   []ColumnTime `json:"columns"`
+}
+
+// Summary describes a set of durations in seconds. Percentiles are zero when Tasks is.
+type Summary struct {
+	Tasks int `json:"tasks"`
+	Mean  int `json:"mean"`
+	P50   int `json:"p50"`
+	P85   int `json:"p85"`
+	P95   int `json:"p95"`
+}
+
+// ColumnTime summarizes the time tasks spent in a column, adding up their stays.
+type ColumnTime struct {
+	ColumnID string  `json:"columnId"`
+	Title    string  `json:"title"`
+	Time     Summary `json:"time"`
+}
@@ -0,0 +1,120 @@
+package transitions
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/domain/columns"
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrInvalidID = errors.New("id provided was not a valid UUID")
+)
+
+var fields = []string{
+	"transition_id",
+	"project_id",
+	"task_id",
+	"column_id",
+	"column_title",
+	"entered_at",
+	"exited_at",
+}
+
+// Enter records a task entering a column, ending its stay in the column it was in.
+func Enter(ctx context.Context, repo database.Storer, tid string, c columns.Column, now time.Time) (Transition, error) {
+	var t Transition
+
+	if _, err := uuid.Parse(tid); err != nil {
+		return t, ErrInvalidID
+	}
+
+	t = Transition{
+		ID:        uuid.New().String(),
+		ProjectID: c.ProjectID,
+		TaskID:    tid,
+		ColumnID:  c.ID,
+		Column:    c.Title,
+		EnteredAt: now.UTC(),
+	}
+
+	tx, err := repo.BeginTxx(ctx, nil)
+	if err != nil {
+		return t, errors.Wrap(err, "starting transaction")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, repo.Rebind(`UPDATE task_transitions SET exited_at = ?
+		WHERE task_id = ? AND exited_at IS NULL`), t.EnteredAt, tid); err != nil {
+		return t, errors.Wrapf(err, "ending stay of task %s", tid)
+	}
+
+	if _, err := tx.ExecContext(ctx, repo.Rebind(`INSERT INTO task_transitions (transition_id, project_id, task_id,
+		column_id, column_title, entered_at) VALUES (?, ?, ?, ?, ?, ?)`), t.ID, t.ProjectID, t.TaskID, t.ColumnID,
+		t.Column, t.EnteredAt); err != nil {
+		return t, errors.Wrapf(err, "inserting transition of task %s", tid)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return t, errors.Wrap(err, "committing transition")
+	}
+
+	return t, nil
+}
+
+// List returns the column history of a task, oldest first.
+func List(ctx context.Context, repo database.Storer, tid string) ([]Transition, error) {
+	var ts = make([]Transition, 0)
+
+	if _, err := uuid.Parse(tid); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	stmt := repo.Select(fields...).From("task_transitions").Where(sq.Eq{"task_id": "?"}).OrderBy("entered_at")
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.SelectContext(ctx, &ts, q, tid); err != nil {
+		return nil, errors.Wrap(err, "selecting transitions")
+	}
+
+	return ts, nil
+}
+
+// DeleteByTask removes the column history of a task.
+func DeleteByTask(ctx context.Context, repo database.Storer, tid string) error {
+	stmt := repo.Delete(
+		"task_transitions",
+	).Where(sq.Eq{"task_id": tid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting transitions of task %s", tid)
+	}
+
+	return nil
+}
+
+// DeleteAll removes the column history of every task of a project.
+func DeleteAll(ctx context.Context, repo database.Storer, pid string) error {
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"task_transitions",
+	).Where(sq.Eq{"project_id": pid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting all transitions")
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package views
+
+import (
+	"time"
+
+	"github.com/devpies/devpie-client-core/projects/domain/tasks"
+)
+
+// View is a saved combination of task filters, sort and grouping on a project. Views belong
+// to the user who saved them, and shared views are visible to everyone on the project.
+// GroupBy is column, assignee, label, milestone or field.<field id>, and is left to clients
+// to apply.
+type View struct {
+	ID        string       `db:"view_id" json:"id"`
+	ProjectID string       `db:"project_id" json:"projectId"`
+	UserID    string       `db:"user_id" json:"userId"`
+	Name      string       `db:"name" json:"name"`
+	Filter    tasks.Filter `db:"filter" json:"filter"`
+	GroupBy   string       `db:"group_by" json:"groupBy"`
+	Shared    bool         `db:"shared" json:"shared"`
+	UpdatedAt time.Time    `db:"updated_at" json:"updatedAt"`
+	CreatedAt time.Time    `db:"created_at" json:"createdAt"`
+}
+
+type NewView struct {
+	Name    string       `json:"name" validate:"required,max=64"`
+	Filter  tasks.Filter `json:"filter"`
+	GroupBy string       `json:"groupBy" validate:"max=48"`
+	Shared  bool         `json:"shared"`
+}
+
+type UpdateView struct {
+	Name    *string       `json:"name" validate:"omitempty,min=1,max=64"`
+	Filter  *tasks.Filter `json:"filter"`
+	GroupBy *string       `json:"groupBy" validate:"omitempty,max=48"`
+	Shared  *bool         `json:"shared"`
+}
@@ -0,0 +1,210 @@
+package views
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrNotFound     = errors.New("view not found")
+	ErrInvalidID    = errors.New("id provided was not a valid UUID")
+	ErrForbidden    = errors.New("only the owner of a view can change it")
+	ErrInvalidGroup = errors.New("views can only be grouped by column, assignee, label, milestone or field.<id>")
+)
+
+// groups lists the groupings of a view besides custom fields.
+var groups = map[string]bool{
+	"":          true,
+	"column":    true,
+	"assignee":  true,
+	"label":     true,
+	"milestone": true,
+}
+
+// fields lists the columns of a view.
+const fields = `view_id, project_id, user_id, name, filter, group_by, shared, updated_at, created_at`
+
+// Create saves a view of a project for a user.
+func Create(ctx context.Context, repo database.Storer, nv NewView, pid, uid string, now time.Time) (View, error) {
+	var v View
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return v, ErrInvalidID
+	}
+
+	v = View{
+		ID:        uuid.New().String(),
+		ProjectID: pid,
+		UserID:    uid,
+		Name:      nv.Name,
+		Filter:    nv.Filter,
+		GroupBy:   nv.GroupBy,
+		Shared:    nv.Shared,
+		UpdatedAt: now.UTC(),
+		CreatedAt: now.UTC(),
+	}
+
+	if err := validate(v); err != nil {
+		return v, err
+	}
+
+	stmt := repo.Insert(
+		"saved_views",
+	).SetMap(map[string]interface{}{
+		"view_id":    v.ID,
+		"project_id": v.ProjectID,
+		"user_id":    v.UserID,
+		"name":       v.Name,
+		"filter":     v.Filter,
+		"group_by":   v.GroupBy,
+		"shared":     v.Shared,
+		"updated_at": v.UpdatedAt,
+		"created_at": v.CreatedAt,
+	})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return v, errors.Wrapf(err, "inserting view: %v", nv)
+	}
+
+	return v, nil
+}
+
+// List returns the views of a project a user can see: their own and the shared ones.
+func List(ctx context.Context, repo database.Storer, pid, uid string) ([]View, error) {
+	var vs = make([]View, 0)
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	q := repo.Rebind(`SELECT ` + fields + ` FROM saved_views WHERE project_id = ? AND (user_id = ? OR shared)
+		ORDER BY LOWER(name)`)
+
+	if err := repo.SelectContext(ctx, &vs, q, pid, uid); err != nil {
+		return nil, errors.Wrap(err, "selecting views")
+	}
+
+	return vs, nil
+}
+
+// Retrieve returns a view of a project if the user owns it or it is shared.
+func Retrieve(ctx context.Context, repo database.Storer, pid, vid, uid string) (View, error) {
+	var v View
+
+	if _, err := uuid.Parse(vid); err != nil {
+		return v, ErrInvalidID
+	}
+
+	q := repo.Rebind(`SELECT ` + fields + ` FROM saved_views WHERE project_id = ? AND view_id = ?
+		AND (user_id = ? OR shared)`)
+
+	if err := repo.QueryRowxContext(ctx, q, pid, vid, uid).StructScan(&v); err != nil {
+		if err == sql.ErrNoRows {
+			return v, ErrNotFound
+		}
+		return v, err
+	}
+
+	return v, nil
+}
+
+// Update changes a view. Only its owner can.
+func Update(ctx context.Context, repo database.Storer, pid, vid, uid string, update UpdateView, now time.Time) (View, error) {
+	v, err := Retrieve(ctx, repo, pid, vid, uid)
+	if err != nil {
+		return v, err
+	}
+	if v.UserID != uid {
+		return v, ErrForbidden
+	}
+
+	if update.Name != nil {
+		v.Name = *update.Name
+	}
+	if update.Filter != nil {
+		v.Filter = *update.Filter
+	}
+	if update.GroupBy != nil {
+		v.GroupBy = *update.GroupBy
+	}
+	if update.Shared != nil {
+		v.Shared = *update.Shared
+	}
+	v.UpdatedAt = now.UTC()
+
+	if err := validate(v); err != nil {
+		return v, err
+	}
+
+	stmt := repo.Update(
+		"saved_views",
+	).SetMap(map[string]interface{}{
+		"name":       v.Name,
+		"filter":     v.Filter,
+		"group_by":   v.GroupBy,
+		"shared":     v.Shared,
+		"updated_at": v.UpdatedAt,
+	}).Where(sq.Eq{"view_id": vid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return v, errors.Wrapf(err, "updating view: %s", vid)
+	}
+
+	return v, nil
+}
+
+// Delete removes a view. Only its owner can.
+func Delete(ctx context.Context, repo database.Storer, pid, vid, uid string) error {
+	v, err := Retrieve(ctx, repo, pid, vid, uid)
+	if err != nil {
+		return err
+	}
+	if v.UserID != uid {
+		return ErrForbidden
+	}
+
+	stmt := repo.Delete(
+		"saved_views",
+	).Where(sq.Eq{"view_id": vid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting view %s", vid)
+	}
+
+	return nil
+}
+
+// DeleteAll removes every view of a project.
+func DeleteAll(ctx context.Context, repo database.Storer, pid string) error {
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"saved_views",
+	).Where(sq.Eq{"project_id": pid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting all views")
+	}
+
+	return nil
+}
+
+func validate(v View) error {
+	if err := v.Filter.Validate(); err != nil {
+		return err
+	}
+	if !groups[v.GroupBy] && !strings.HasPrefix(v.GroupBy, "field.") {
+		return ErrInvalidGroup
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package watchers
+
+import "time"
+
+// Watcher subscribes a user to changes on a project or, when TaskID is set, a single task.
+type Watcher struct {
+	ID        string    `db:"watcher_id" json:"id"`
+	ProjectID string    `db:"project_id" json:"projectId"`
+	TaskID    string    `db:"task_id" json:"taskId,omitempty"`
+	UserID    string    `db:"user_id" json:"userId"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
@@ -0,0 +1,168 @@
+package watchers
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+)
+
+var (
+	ErrNotFound  = errors.New("watcher not found")
+	ErrInvalidID = errors.New("id provided was not a valid UUID")
+)
+
+// Create subscribes a user to a project, or to a task of the project when tid is not empty.
+// Watching something twice is not an error.
+func Create(ctx context.Context, repo database.Storer, pid, tid, uid string, now time.Time) (Watcher, error) {
+	var w Watcher
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return w, ErrInvalidID
+	}
+	if tid != "" {
+		if _, err := uuid.Parse(tid); err != nil {
+			return w, ErrInvalidID
+		}
+	}
+
+	w = Watcher{
+		ID:        uuid.New().String(),
+		ProjectID: pid,
+		TaskID:    tid,
+		UserID:    uid,
+		CreatedAt: now.UTC(),
+	}
+
+	stmt := repo.Insert(
+		"watchers",
+	).SetMap(map[string]interface{}{
+		"watcher_id": w.ID,
+		"project_id": w.ProjectID,
+		"task_id":    w.TaskID,
+		"user_id":    w.UserID,
+		"created_at": w.CreatedAt,
+	}).Suffix(
+		"ON CONFLICT (project_id, task_id, user_id) DO UPDATE SET user_id = EXCLUDED.user_id RETURNING watcher_id, created_at",
+	)
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return w, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.QueryRowxContext(ctx, q, args...).Scan(&w.ID, &w.CreatedAt); err != nil {
+		return w, errors.Wrapf(err, "inserting watcher: %v", w)
+	}
+
+	return w, nil
+}
+
+// List returns everything a user watches, most recent first.
+func List(ctx context.Context, repo database.Storer, uid string) ([]Watcher, error) {
+	var ws = make([]Watcher, 0)
+
+	stmt := repo.Select(
+		"watcher_id",
+		"project_id",
+		"task_id",
+		"user_id",
+		"created_at",
+	).From(
+		"watchers",
+	).Where(sq.Eq{"user_id": "?"}).OrderBy("created_at DESC")
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.SelectContext(ctx, &ws, q, uid); err != nil {
+		return nil, errors.Wrap(err, "selecting watchers")
+	}
+
+	return ws, nil
+}
+
+// Recipients returns the users watching a project or, when tid is not empty, the users
+// watching the project or the task. The user who made the change is left out.
+func Recipients(ctx context.Context, repo database.Storer, pid, tid, uid string) ([]string, error) {
+	var ids = make([]string, 0)
+
+	stmt := repo.Select(
+		"DISTINCT user_id",
+	).From(
+		"watchers",
+	).Where(sq.Eq{"project_id": pid, "task_id": []string{"", tid}}).Where(sq.NotEq{"user_id": uid})
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.SelectContext(ctx, &ids, q, args...); err != nil {
+		return nil, errors.Wrap(err, "selecting watchers")
+	}
+
+	return ids, nil
+}
+
+// Delete unsubscribes a user from a project or task.
+func Delete(ctx context.Context, repo database.Storer, pid, tid, uid string) error {
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"watchers",
+	).Where(sq.Eq{"project_id": pid, "task_id": tid, "user_id": uid})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "deleting watcher of %s", pid)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteByTask removes every watcher of a task.
+func DeleteByTask(ctx context.Context, repo database.Storer, tid string) error {
+	if _, err := uuid.Parse(tid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"watchers",
+	).Where(sq.Eq{"task_id": tid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting watchers of task %s", tid)
+	}
+
+	return nil
+}
+
+// DeleteAll removes every watcher of a project and its tasks.
+func DeleteAll(ctx context.Context, repo database.Storer, pid string) error {
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"watchers",
+	).Where(sq.Eq{"project_id": pid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting all watchers")
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package webhooks
+
+import "time"
+
+// Delivery statuses.
+const (
+	Pending   = "pending"
+	Succeeded = "succeeded"
+	Failed    = "failed"
+)
+
+// Webhook is an endpoint registered by a project owner. Events lists the event types
+// sent to the endpoint; an empty list means every event.
+type Webhook struct {
+	ID        string    `db:"webhook_id" json:"id"`
+	ProjectID string    `db:"project_id" json:"projectId"`
+	URL       string    `db:"url" json:"url"`
+	Secret    string    `db:"secret" json:"secret,omitempty"`
+	Events    []string  `db:"events" json:"events"`
+	CreatedBy string    `db:"created_by" json:"createdBy"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+type NewWebhook struct {
+	URL    string   `json:"url" validate:"required,url"`
+	Events []string `json:"events"`
+}
+
+// Delivery is a single event sent, or waiting to be sent, to a webhook.
+type Delivery struct {
+	ID            string    `db:"delivery_id" json:"id"`
+	WebhookID     string    `db:"webhook_id" json:"webhookId"`
+	EventID       string    `db:"event_id" json:"eventId"`
+	EventType     string    `db:"event_type" json:"eventType"`
+	Payload       string    `db:"payload" json:"payload"`
+	Status        string    `db:"status" json:"status"`
+	Attempts      int       `db:"attempts" json:"attempts"`
+	ResponseCode  int       `db:"response_code" json:"responseCode"`
+	LastError     string    `db:"last_error" json:"lastError"`
+	NextAttemptAt time.Time `db:"next_attempt_at" json:"nextAttemptAt"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updatedAt"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
+}
+
+// Target is a claimed delivery together with the endpoint it goes to.
+type Target struct {
+	Delivery
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
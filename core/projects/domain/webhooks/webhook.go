@@ -0,0 +1,350 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/devpies/devpie-client-core/projects/platform/database"
+	wh "github.com/devpies/devpie-client-core/projects/platform/webhooks"
+)
+
+var (
+	ErrNotFound   = errors.New("webhook not found")
+	ErrInvalidID  = errors.New("id provided was not a valid UUID")
+	ErrInvalidURL = wh.ErrForbiddenEndpoint
+)
+
+// MaxAttempts is the number of times a delivery is tried before it is marked as failed.
+const MaxAttempts = 8
+
+// Backoff returns the wait before the next attempt after n failed attempts. It doubles
+// from 30 seconds and is capped at an hour.
+func Backoff(n int) time.Duration {
+	d := 30 * time.Second
+	for i := 1; i < n && d < time.Hour; i++ {
+		d *= 2
+	}
+	if d > time.Hour {
+		d = time.Hour
+	}
+	return d
+}
+
+// Create registers a webhook on a project. The signing secret is generated here and only
+// returned to the caller once.
+func Create(ctx context.Context, repo database.Storer, nw NewWebhook, pid, uid string, now time.Time) (Webhook, error) {
+	var w Webhook
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return w, ErrInvalidID
+	}
+	if err := wh.CheckURL(ctx, nw.URL); err != nil {
+		return w, ErrInvalidURL
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return w, errors.Wrap(err, "generating webhook secret")
+	}
+
+	w = Webhook{
+		ID:        uuid.New().String(),
+		ProjectID: pid,
+		URL:       nw.URL,
+		Secret:    hex.EncodeToString(b),
+		Events:    nw.Events,
+		CreatedBy: uid,
+		UpdatedAt: now.UTC(),
+		CreatedAt: now.UTC(),
+	}
+
+	if w.Events == nil {
+		w.Events = make([]string, 0)
+	}
+
+	stmt := repo.Insert(
+		"webhooks",
+	).SetMap(map[string]interface{}{
+		"webhook_id": w.ID,
+		"project_id": w.ProjectID,
+		"url":        w.URL,
+		"secret":     w.Secret,
+		"events":     pq.Array(w.Events),
+		"created_by": w.CreatedBy,
+		"updated_at": w.UpdatedAt,
+		"created_at": w.CreatedAt,
+	})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return w, errors.Wrapf(err, "inserting webhook: %v", nw)
+	}
+
+	return w, nil
+}
+
+// List returns the webhooks of a project without their secrets.
+func List(ctx context.Context, repo database.Storer, pid string) ([]Webhook, error) {
+	var ws = make([]Webhook, 0)
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	stmt := repo.Select(
+		"webhook_id",
+		"project_id",
+		"url",
+		"events",
+		"created_by",
+		"updated_at",
+		"created_at",
+	).From(
+		"webhooks",
+	).Where(sq.Eq{"project_id": "?"}).OrderBy("created_at")
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	rows, err := repo.QueryxContext(ctx, q, pid)
+	if err != nil {
+		return nil, errors.Wrap(err, "selecting webhooks")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var w Webhook
+		err = rows.Scan(&w.ID, &w.ProjectID, &w.URL, (*pq.StringArray)(&w.Events), &w.CreatedBy, &w.UpdatedAt, &w.CreatedAt)
+		if err != nil {
+			return nil, errors.Wrap(err, "scanning webhook")
+		}
+		ws = append(ws, w)
+	}
+
+	return ws, rows.Err()
+}
+
+// Subscribed returns the ids of the project's webhooks that receive an event type.
+func Subscribed(ctx context.Context, repo database.Storer, pid, event string) ([]string, error) {
+	var ids = make([]string, 0)
+
+	stmt := repo.Select(
+		"webhook_id",
+	).From(
+		"webhooks",
+	).Where(sq.Eq{"project_id": "?"}).Where("(events = '{}' OR ? = ANY(events))")
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.SelectContext(ctx, &ids, q, pid, event); err != nil {
+		return nil, errors.Wrap(err, "selecting subscribed webhooks")
+	}
+
+	return ids, nil
+}
+
+// Delete removes a webhook and its delivery log.
+func Delete(ctx context.Context, repo database.Storer, pid, wid string) error {
+	if _, err := uuid.Parse(wid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"webhooks",
+	).Where(sq.Eq{"project_id": pid, "webhook_id": wid})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "deleting webhook %s", wid)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteAll removes every webhook of a project.
+func DeleteAll(ctx context.Context, repo database.Storer, pid string) error {
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"webhooks",
+	).Where(sq.Eq{"project_id": pid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "deleting all webhooks")
+	}
+
+	return nil
+}
+
+// CreateDelivery queues an event for a webhook. Queueing the same event twice is not an error.
+func CreateDelivery(ctx context.Context, repo database.Storer, wid, eid, event, payload string, now time.Time) error {
+	stmt := repo.Insert(
+		"webhook_deliveries",
+	).SetMap(map[string]interface{}{
+		"delivery_id":     uuid.New().String(),
+		"webhook_id":      wid,
+		"event_id":        eid,
+		"event_type":      event,
+		"payload":         payload,
+		"status":          Pending,
+		"next_attempt_at": now.UTC(),
+		"updated_at":      now.UTC(),
+		"created_at":      now.UTC(),
+	}).Suffix("ON CONFLICT (webhook_id, event_id) DO NOTHING")
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "inserting delivery of %s to webhook %s", eid, wid)
+	}
+
+	return nil
+}
+
+// Deliveries returns the most recent deliveries of a project's webhook.
+func Deliveries(ctx context.Context, repo database.Storer, pid, wid string, limit uint64) ([]Delivery, error) {
+	var ds = make([]Delivery, 0)
+
+	if _, err := uuid.Parse(wid); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	stmt := repo.Select(
+		"d.delivery_id",
+		"d.webhook_id",
+		"d.event_id",
+		"d.event_type",
+		"d.payload",
+		"d.status",
+		"d.attempts",
+		"d.response_code",
+		"d.last_error",
+		"d.next_attempt_at",
+		"d.updated_at",
+		"d.created_at",
+	).From(
+		"webhook_deliveries d",
+	).Join(
+		"webhooks w ON w.webhook_id = d.webhook_id",
+	).Where(sq.Eq{"w.project_id": "?", "d.webhook_id": "?"}).OrderBy("d.created_at DESC").Limit(limit)
+
+	q, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, "building query: %v", args)
+	}
+
+	if err := repo.SelectContext(ctx, &ds, q, pid, wid); err != nil {
+		return nil, errors.Wrap(err, "selecting deliveries")
+	}
+
+	return ds, nil
+}
+
+// Redeliver queues a delivery of a project's webhook to be sent again as soon as possible.
+func Redeliver(ctx context.Context, repo database.Storer, pid, wid, did string, now time.Time) (Delivery, error) {
+	var d Delivery
+
+	if _, err := uuid.Parse(wid); err != nil {
+		return d, ErrInvalidID
+	}
+	if _, err := uuid.Parse(did); err != nil {
+		return d, ErrInvalidID
+	}
+
+	q := `UPDATE webhook_deliveries d SET status = $1, attempts = 0, last_error = '', next_attempt_at = $2, updated_at = $2
+		FROM webhooks w WHERE w.webhook_id = d.webhook_id AND w.project_id = $3 AND d.webhook_id = $4 AND d.delivery_id = $5
+		RETURNING d.delivery_id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+		d.response_code, d.last_error, d.next_attempt_at, d.updated_at, d.created_at`
+
+	if err := repo.QueryRowxContext(ctx, q, Pending, now.UTC(), pid, wid, did).StructScan(&d); err != nil {
+		if err == sql.ErrNoRows {
+			return d, ErrNotFound
+		}
+		return d, errors.Wrapf(err, "redelivering %s", did)
+	}
+
+	return d, nil
+}
+
+// Claim leases up to limit due deliveries to the caller. A claimed delivery is not due
+// again until the lease runs out, so replicas never send the same delivery concurrently
+// and a crashed sender's deliveries are picked up later.
+func Claim(ctx context.Context, repo database.Storer, now time.Time, lease time.Duration, limit int) ([]Target, error) {
+	var ts = make([]Target, 0)
+
+	q := `UPDATE webhook_deliveries d SET next_attempt_at = $1
+		FROM webhooks w WHERE w.webhook_id = d.webhook_id AND d.delivery_id IN (
+			SELECT delivery_id FROM webhook_deliveries WHERE status = $2 AND next_attempt_at <= $3
+			ORDER BY next_attempt_at LIMIT $4 FOR UPDATE SKIP LOCKED
+		)
+		RETURNING d.delivery_id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+		d.response_code, d.last_error, d.next_attempt_at, d.updated_at, d.created_at, w.url, w.secret`
+
+	if err := repo.SelectContext(ctx, &ts, q, now.Add(lease).UTC(), Pending, now.UTC(), limit); err != nil {
+		return nil, errors.Wrap(err, "claiming deliveries")
+	}
+
+	return ts, nil
+}
+
+// Complete records a successful attempt.
+func Complete(ctx context.Context, repo database.Storer, d Delivery, code int, now time.Time) error {
+	stmt := repo.Update(
+		"webhook_deliveries",
+	).SetMap(map[string]interface{}{
+		"status":        Succeeded,
+		"attempts":      d.Attempts + 1,
+		"response_code": code,
+		"last_error":    "",
+		"updated_at":    now.UTC(),
+	}).Where(sq.Eq{"delivery_id": d.ID})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "completing delivery %s", d.ID)
+	}
+
+	return nil
+}
+
+// Fail records a failed attempt and schedules the next one, or gives up after MaxAttempts.
+func Fail(ctx context.Context, repo database.Storer, d Delivery, code int, reason string, now time.Time) error {
+	n := d.Attempts + 1
+
+	status := Pending
+	if n >= MaxAttempts {
+		status = Failed
+	}
+
+	stmt := repo.Update(
+		"webhook_deliveries",
+	).SetMap(map[string]interface{}{
+		"status":          status,
+		"attempts":        n,
+		"response_code":   code,
+		"last_error":      reason,
+		"next_attempt_at": now.Add(Backoff(n)).UTC(),
+		"updated_at":      now.UTC(),
+	}).Where(sq.Eq{"delivery_id": d.ID})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.Wrapf(err, "failing delivery %s", d.ID)
+	}
+
+	return nil
+}
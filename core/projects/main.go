@@ -16,7 +16,9 @@ import (
 	"github.com/ardanlabs/conf"
 	"github.com/devpies/devpie-client-core/projects/api/handlers"
 	"github.com/devpies/devpie-client-core/projects/api/listeners"
+	"github.com/devpies/devpie-client-core/projects/api/schedulers"
 	"github.com/devpies/devpie-client-core/projects/platform/database"
+	"github.com/devpies/devpie-client-core/projects/platform/stream"
 	"github.com/devpies/devpie-client-events/go/events"
 )
 
@@ -40,6 +42,7 @@ func run() error {
 			WriteTimeout     time.Duration `conf:"default:5s"`
 			ShutdownTimeout  time.Duration `conf:"default:5s"`
 			CorsOrigins      string        `conf:"default:https://localhost:3000"`
+			AppURL           string        `conf:"default:https://localhost:3000"`
 			AuthDomain       string        `conf:"default:none"`
 			AuthAudience     string        `conf:"default:none"`
 			AuthM2MClient    string        `conf:"default:none"`
@@ -59,6 +62,17 @@ func run() error {
 			ClientId  string `conf:"default:client-id"`
 			ClusterId string `conf:"default:cluster-id"`
 		}
+		Stream struct {
+			History int           `conf:"default:200"`
+			Window  time.Duration `conf:"default:15m"`
+		}
+		Webhooks struct {
+			Interval time.Duration `conf:"default:10s"`
+			Timeout  time.Duration `conf:"default:10s"`
+		}
+		Recurrences struct {
+			Interval time.Duration `conf:"default:1m"`
+		}
 	}
 
 	if err := conf.Parse(os.Args[1:], "API", &cfg); err != nil {
@@ -125,23 +139,42 @@ func run() error {
 	nats, Close := events.NewClient(cfg.Nats.ClusterId, clusterId, cfg.Nats.Url)
 	defer Close()
 
+	// every replica keeps its own copy of recent board events for streaming clients
+	hub := stream.NewHub(cfg.Stream.History)
+
 	go func(repo *database.Repository, nats *events.Client, infolog *log.Logger, queueGroup string) {
 		l := listeners.NewListeners(infolog, repo)
 		l.RegisterAll(nats, queueGroup)
+		l.RegisterStream(nats, hub, clusterId, cfg.Stream.Window)
+		l.RegisterWebhooks(nats, queueGroup)
+		l.RegisterActivities(nats, queueGroup)
 	}(repo, nats, infolog, queueGroup)
 
+	// =========================================================================
+	// Start Schedulers
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	d := schedulers.NewDeliverer(infolog, repo, cfg.Webhooks.Timeout)
+	go d.Start(cfg.Webhooks.Interval, stop)
+
+	rc := schedulers.NewRecurrer(infolog, repo, nats)
+	go rc.Start(cfg.Recurrences.Interval, stop)
+
 	// =========================================================================
 	// Start API Service
 
 	// Make a channel to listen for shutdown signal from the OS.
 	shutdown := make(chan os.Signal, 1)
 
+	// the write timeout is applied per request so event streams can stay open
 	api := http.Server{
 		Addr: cfg.Web.Port,
-		Handler: handlers.API(shutdown, repo, infolog, cfg.Web.CorsOrigins, cfg.Web.AuthAudience,
-			cfg.Web.AuthDomain, cfg.Web.AuthMAPIAudience, cfg.Web.AuthM2MClient, cfg.Web.AuthM2MSecret, nats),
-		ReadTimeout:  cfg.Web.ReadTimeout,
-		WriteTimeout: cfg.Web.WriteTimeout,
+		Handler: handlers.WithWriteTimeout(handlers.API(shutdown, repo, infolog, cfg.Web.CorsOrigins, cfg.Web.AuthAudience,
+			cfg.Web.AuthDomain, cfg.Web.AuthMAPIAudience, cfg.Web.AuthM2MClient, cfg.Web.AuthM2MSecret, nats, hub, cfg.Web.AppURL),
+			cfg.Web.WriteTimeout),
+		ReadTimeout: cfg.Web.ReadTimeout,
 	}
 
 	// Make a channel to listen for errors coming from the listener. Use a
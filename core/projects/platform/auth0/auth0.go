@@ -35,6 +35,7 @@ type Auther interface {
 	PemCert(token *jwt.Token) (string, error)
 	UserByID(r context.Context) string
 	UserBySubject(ctx context.Context) string
+	UserRoles(ctx context.Context) []string
 	GenerateToken() (Token, error)
 	ConnectionID(token Token) (string, error)
 	CheckScope(scope, tokenString string) (bool, error)
@@ -113,6 +114,17 @@ var (
 
 // Authenticate middleware verifies the access token sent from auth0
 func (a0 *Auth0) Authenticate() web.Middleware {
+	return a0.authenticate(jwtmiddleware.FromAuthHeader)
+}
+
+// AuthenticateStream is Authenticate for event streams. EventSource clients cannot set
+// headers, so the token may also come in the access_token parameter. It must not be used
+// on other routes, where tokens in URLs would leak into logs and Referer headers.
+func (a0 *Auth0) AuthenticateStream() web.Middleware {
+	return a0.authenticate(jwtmiddleware.FromFirst(jwtmiddleware.FromAuthHeader, jwtmiddleware.FromParameter("access_token")))
+}
+
+func (a0 *Auth0) authenticate(extractor jwtmiddleware.TokenExtractor) web.Middleware {
 	// this is the actual middleware function to be executed.
 	f := func(after web.Handler) web.Handler {
 		// create the handler that will be attached in the middleware chain.
@@ -137,6 +149,7 @@ func (a0 *Auth0) Authenticate() web.Middleware {
 					return jwt.ParseRSAPublicKeyFromPEM([]byte(cert))
 				},
 				SigningMethod: jwt.SigningMethodRS256,
+				Extractor:     extractor,
 			})
 
 			if err := jwtMiddleware.CheckJWT(w, r); err != nil {
@@ -226,6 +239,52 @@ func (a0 *Auth0) UserByID(ctx context.Context) string {
 	return fmt.Sprintf("%v", claims["https://client.devpie.io/claims/user_id"])
 }
 
+// UserEmail returns the email address of the user, set by the set-claim--profile rule
+func (a0 *Auth0) UserEmail(ctx context.Context) string {
+	claims := ctx.Value("user").(*jwt.Token).Claims.(jwt.MapClaims)
+	email, _ := claims["https://client.devpie.io/claims/email"].(string)
+	return email
+}
+
+// UserNickname returns the nickname of the user, set by the set-claim--profile rule
+func (a0 *Auth0) UserNickname(ctx context.Context) string {
+	claims := ctx.Value("user").(*jwt.Token).Claims.(jwt.MapClaims)
+	nickname, _ := claims["https://client.devpie.io/claims/nickname"].(string)
+	return nickname
+}
+
+// Roles assigned to users in Auth0 (integrations/auth0/roles).
+const (
+	RoleClient     = "client"
+	RoleFreelancer = "freelancer"
+)
+
+// UserRoles returns the Auth0 roles assigned to the user
+func (a0 *Auth0) UserRoles(ctx context.Context) []string {
+	claims := ctx.Value("user").(*jwt.Token).Claims.(jwt.MapClaims)
+	list, ok := claims["https://client.devpie.io/claims/roles"].([]interface{})
+	if !ok {
+		return nil
+	}
+	roles := make([]string, 0, len(list))
+	for _, r := range list {
+		if role, ok := r.(string); ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// HasRole reports whether the user was assigned an Auth0 role
+func (a0 *Auth0) HasRole(ctx context.Context, role string) bool {
+	for _, r := range a0.UserRoles(ctx) {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
 // GenerateToken generates a new management Token if one does not exist otherwise it returns an existing one.
 func (a0 *Auth0) GenerateToken() (Token, error) {
 	var t Token
@@ -277,3 +277,19 @@ func (_m *Auther) UserBySubject(ctx context.Context) string {
 
 	return r0
 }
+
+// UserRoles provides a mock function with given fields: ctx
+func (_m *Auther) UserRoles(ctx context.Context) []string {
+	ret := _m.Called(ctx)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
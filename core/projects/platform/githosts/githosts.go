@@ -0,0 +1,239 @@
+// Package githosts reads the push and pull request webhooks of GitHub and GitLab.
+package githosts
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Supported git hosts.
+const (
+	GitHub = "github"
+	GitLab = "gitlab"
+)
+
+// Kinds of references a delivery can carry.
+const (
+	Commit      = "commit"
+	Branch      = "branch"
+	PullRequest = "pull_request"
+)
+
+// States of a pull request.
+const (
+	Open   = "open"
+	Merged = "merged"
+	Closed = "closed"
+)
+
+var (
+	ErrUnsupportedEvent = errors.New("event is not a push or pull request")
+	ErrUnknownProvider  = errors.New("git host must be github or gitlab")
+)
+
+// keyPattern matches task keys such as APP-12. Branch names are often lowercase, so case
+// is ignored.
+var keyPattern = regexp.MustCompile(`(?i)\b[a-z][a-z0-9]{0,9}-[0-9]+\b`)
+
+// Ref is a commit, branch or pull request mentioning task keys.
+type Ref struct {
+	Kind  string
+	URL   string
+	Title string
+	Ref   string
+	State string
+	Keys  []string
+}
+
+// Verify checks that a delivery was sent by the git host holding the secret. GitHub signs
+// the body with HMAC-SHA256, GitLab sends the secret itself.
+func Verify(provider string, h http.Header, body []byte, secret string) bool {
+	switch provider {
+	case GitHub:
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		return hmac.Equal([]byte(h.Get("X-Hub-Signature-256")), []byte(want))
+	case GitLab:
+		token := h.Get("X-Gitlab-Token")
+		return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+	default:
+		return false
+	}
+}
+
+// Parse returns the references of a push or pull request delivery that mention task keys.
+func Parse(provider string, h http.Header, body []byte) ([]Ref, error) {
+	switch provider {
+	case GitHub:
+		switch h.Get("X-GitHub-Event") {
+		case "push":
+			return githubPush(body)
+		case "pull_request":
+			return githubPullRequest(body)
+		}
+	case GitLab:
+		switch h.Get("X-Gitlab-Event") {
+		case "Push Hook":
+			return gitlabPush(body)
+		case "Merge Request Hook":
+			return gitlabMergeRequest(body)
+		}
+	default:
+		return nil, ErrUnknownProvider
+	}
+	return nil, ErrUnsupportedEvent
+}
+
+// Keys returns the distinct task keys found in texts, uppercased, in order of appearance.
+func Keys(texts ...string) []string {
+	seen := make(map[string]bool)
+	keys := make([]string, 0)
+	for _, text := range texts {
+		for _, k := range keyPattern.FindAllString(text, -1) {
+			k = strings.ToUpper(k)
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys
+}
+
+func githubPush(body []byte) ([]Ref, error) {
+	var p struct {
+		Ref        string `json:"ref"`
+		Deleted    bool   `json:"deleted"`
+		Repository struct {
+			HTMLURL string `json:"html_url"`
+		} `json:"repository"`
+		Commits []struct {
+			ID      string `json:"id"`
+			Message string `json:"message"`
+			URL     string `json:"url"`
+		} `json:"commits"`
+	}
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, errors.Wrap(err, "decoding github push")
+	}
+
+	var refs []Ref
+	if branch := strings.TrimPrefix(p.Ref, "refs/heads/"); branch != p.Ref && !p.Deleted {
+		refs = appendRef(refs, Ref{Kind: Branch, URL: p.Repository.HTMLURL + "/tree/" + branch, Title: branch, Ref: branch}, branch)
+	}
+	for _, c := range p.Commits {
+		refs = appendRef(refs, Ref{Kind: Commit, URL: c.URL, Title: firstLine(c.Message), Ref: c.ID}, c.Message)
+	}
+	return refs, nil
+}
+
+func githubPullRequest(body []byte) ([]Ref, error) {
+	var p struct {
+		PullRequest struct {
+			HTMLURL string `json:"html_url"`
+			Number  int    `json:"number"`
+			Title   string `json:"title"`
+			State   string `json:"state"`
+			Merged  bool   `json:"merged"`
+			Head    struct {
+				Ref string `json:"ref"`
+			} `json:"head"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, errors.Wrap(err, "decoding github pull request")
+	}
+
+	pr := p.PullRequest
+	state := Open
+	switch {
+	case pr.Merged:
+		state = Merged
+	case pr.State == "closed":
+		state = Closed
+	}
+
+	r := Ref{Kind: PullRequest, URL: pr.HTMLURL, Title: pr.Title, Ref: fmt.Sprintf("#%d", pr.Number), State: state}
+	return appendRef(nil, r, pr.Title, pr.Head.Ref), nil
+}
+
+func gitlabPush(body []byte) ([]Ref, error) {
+	var p struct {
+		Ref     string `json:"ref"`
+		After   string `json:"after"`
+		Project struct {
+			WebURL string `json:"web_url"`
+		} `json:"project"`
+		Commits []struct {
+			ID      string `json:"id"`
+			Message string `json:"message"`
+			URL     string `json:"url"`
+		} `json:"commits"`
+	}
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, errors.Wrap(err, "decoding gitlab push")
+	}
+
+	var refs []Ref
+	// GitLab reports a deleted branch with an all zero after commit
+	deleted := strings.Trim(p.After, "0") == ""
+	if branch := strings.TrimPrefix(p.Ref, "refs/heads/"); branch != p.Ref && !deleted {
+		refs = appendRef(refs, Ref{Kind: Branch, URL: p.Project.WebURL + "/-/tree/" + branch, Title: branch, Ref: branch}, branch)
+	}
+	for _, c := range p.Commits {
+		refs = appendRef(refs, Ref{Kind: Commit, URL: c.URL, Title: firstLine(c.Message), Ref: c.ID}, c.Message)
+	}
+	return refs, nil
+}
+
+func gitlabMergeRequest(body []byte) ([]Ref, error) {
+	var p struct {
+		ObjectAttributes struct {
+			IID          int    `json:"iid"`
+			Title        string `json:"title"`
+			URL          string `json:"url"`
+			State        string `json:"state"`
+			SourceBranch string `json:"source_branch"`
+		} `json:"object_attributes"`
+	}
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, errors.Wrap(err, "decoding gitlab merge request")
+	}
+
+	mr := p.ObjectAttributes
+	state := Open
+	switch mr.State {
+	case "merged":
+		state = Merged
+	case "closed":
+		state = Closed
+	}
+
+	r := Ref{Kind: PullRequest, URL: mr.URL, Title: mr.Title, Ref: fmt.Sprintf("!%d", mr.IID), State: state}
+	return appendRef(nil, r, mr.Title, mr.SourceBranch), nil
+}
+
+// appendRef adds r when the texts mention task keys.
+func appendRef(refs []Ref, r Ref, texts ...string) []Ref {
+	if r.Keys = Keys(texts...); len(r.Keys) > 0 {
+		refs = append(refs, r)
+	}
+	return refs
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
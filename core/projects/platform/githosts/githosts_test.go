@@ -0,0 +1,156 @@
+package githosts
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "3f7c2a1e9b8d4c6f"
+
+func fixture(t *testing.T, name string) []byte {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return b
+}
+
+func header(kv ...string) http.Header {
+	h := http.Header{}
+	for i := 0; i < len(kv); i += 2 {
+		h.Set(kv[i], kv[i+1])
+	}
+	return h
+}
+
+func sign(body []byte, key string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerify(t *testing.T) {
+	body := fixture(t, "github_push.json")
+
+	testcases := []struct {
+		name     string
+		provider string
+		header   http.Header
+		want     bool
+	}{
+		{"github signed", GitHub, header("X-Hub-Signature-256", sign(body, secret)), true},
+		{"github wrong secret", GitHub, header("X-Hub-Signature-256", sign(body, "other")), false},
+		{"github unsigned", GitHub, header(), false},
+		{"gitlab token", GitLab, header("X-Gitlab-Token", secret), true},
+		{"gitlab wrong token", GitLab, header("X-Gitlab-Token", "other"), false},
+		{"gitlab without token", GitLab, header(), false},
+		{"unknown provider", "bitbucket", header("X-Gitlab-Token", secret), false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Verify(tc.provider, tc.header, body, secret))
+		})
+	}
+}
+
+func TestVerify_TamperedBody(t *testing.T) {
+	body := fixture(t, "github_push.json")
+	h := header("X-Hub-Signature-256", sign(body, secret))
+
+	assert.False(t, Verify(GitHub, h, append(body, ' '), secret))
+}
+
+func TestParse_GitHubPush(t *testing.T) {
+	refs, err := Parse(GitHub, header("X-GitHub-Event", "push"), fixture(t, "github_push.json"))
+	require.NoError(t, err)
+	require.Len(t, refs, 2)
+
+	assert.Equal(t, Ref{
+		Kind:  Branch,
+		URL:   "https://github.com/devpies/devpie-client/tree/app-12-login-form",
+		Title: "app-12-login-form",
+		Ref:   "app-12-login-form",
+		Keys:  []string{"APP-12"},
+	}, refs[0])
+
+	assert.Equal(t, Ref{
+		Kind:  Commit,
+		URL:   "https://github.com/devpies/devpie-client/commit/3e1c4f2a8b1d0c9e7f6a5b4c3d2e1f0a9b8c7d6e",
+		Title: "APP-12 Validate the login form",
+		Ref:   "3e1c4f2a8b1d0c9e7f6a5b4c3d2e1f0a9b8c7d6e",
+		Keys:  []string{"APP-12", "APP-13"},
+	}, refs[1])
+}
+
+func TestParse_GitHubPullRequest(t *testing.T) {
+	refs, err := Parse(GitHub, header("X-GitHub-Event", "pull_request"), fixture(t, "github_pull_request_merged.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Ref{{
+		Kind:  PullRequest,
+		URL:   "https://github.com/devpies/devpie-client/pull/42",
+		Title: "APP-12: Login form validation",
+		Ref:   "#42",
+		State: Merged,
+		Keys:  []string{"APP-12"},
+	}}, refs)
+}
+
+func TestParse_GitLabPush(t *testing.T) {
+	refs, err := Parse(GitLab, header("X-Gitlab-Event", "Push Hook"), fixture(t, "gitlab_push.json"))
+	require.NoError(t, err)
+	require.Len(t, refs, 2)
+
+	assert.Equal(t, Branch, refs[0].Kind)
+	assert.Equal(t, "https://gitlab.com/devpies/devpie-client/-/tree/APP-7-invoice-totals", refs[0].URL)
+	assert.Equal(t, []string{"APP-7"}, refs[0].Keys)
+
+	assert.Equal(t, Commit, refs[1].Kind)
+	assert.Equal(t, "Round invoice totals per line (APP-7)", refs[1].Title)
+	assert.Equal(t, "b6568db1bc1dcd7f8b4d5a946b0b91f9dacd7327", refs[1].Ref)
+	assert.Equal(t, []string{"APP-7"}, refs[1].Keys)
+}
+
+func TestParse_GitLabMergeRequest(t *testing.T) {
+	refs, err := Parse(GitLab, header("X-Gitlab-Event", "Merge Request Hook"), fixture(t, "gitlab_merge_request_merged.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Ref{{
+		Kind:  PullRequest,
+		URL:   "https://gitlab.com/devpies/devpie-client/-/merge_requests/8",
+		Title: "Round invoice totals",
+		Ref:   "!8",
+		State: Merged,
+		Keys:  []string{"APP-7"},
+	}}, refs)
+}
+
+func TestParse_UnsupportedEvent(t *testing.T) {
+	_, err := Parse(GitHub, header("X-GitHub-Event", "ping"), fixture(t, "github_ping.json"))
+	assert.Equal(t, ErrUnsupportedEvent, err)
+
+	_, err = Parse("bitbucket", header(), fixture(t, "github_ping.json"))
+	assert.Equal(t, ErrUnknownProvider, err)
+}
+
+func TestKeys(t *testing.T) {
+	testcases := []struct {
+		arg  []string
+		want []string
+	}{
+		{[]string{"APP-12 Fix login"}, []string{"APP-12"}},
+		{[]string{"feature/app-3-signup", "APP-3 and Web-14"}, []string{"APP-3", "WEB-14"}},
+		{[]string{"No keys here", "v2 release"}, []string{}},
+	}
+
+	for _, tc := range testcases {
+		assert.Equal(t, tc.want, Keys(tc.arg...))
+	}
+}
@@ -0,0 +1,122 @@
+// Package markdown renders user content to HTML that is safe to embed in any client.
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+	nethtml "golang.org/x/net/html"
+)
+
+// refPattern matches task keys such as APP-12, and @handle or @email mentions. Mentions
+// must start a word, so email addresses in the text are not taken for them.
+var refPattern = regexp.MustCompile(`(?i)\b([a-z][a-z0-9]{0,9}-[0-9]+)\b|(?:^|[^\w@])(@[\w.+-]+(?:@[\w-]+(?:\.[\w-]+)+)?)`)
+
+var md = goldmark.New(
+	goldmark.WithExtensions(extension.Table, extension.Strikethrough, extension.Linkify),
+	goldmark.WithRendererOptions(gmhtml.WithHardWraps()),
+)
+
+// policy allows the tags and attributes of common user generated content. Links are
+// marked nofollow and images must use http or https.
+var policy = bluemonday.UGCPolicy()
+
+// Linker resolves references found in content to link targets. A reference is left as
+// plain text when its func is nil or reports false.
+type Linker struct {
+	Task    func(key string) (string, bool)
+	Mention func(handle string) (string, bool)
+}
+
+// Render converts Markdown to sanitized HTML and links task keys and @mentions outside
+// of links and code.
+func Render(src string, l Linker) string {
+	if strings.TrimSpace(src) == "" {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(src), &buf); err != nil {
+		return html.EscapeString(src)
+	}
+
+	return link(policy.SanitizeBytes(buf.Bytes()), l)
+}
+
+// link rewrites the text of sanitized HTML, turning references into anchors.
+func link(doc []byte, l Linker) string {
+	var out strings.Builder
+	z := nethtml.NewTokenizer(bytes.NewReader(doc))
+	skip := 0
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case nethtml.ErrorToken:
+			return out.String()
+		case nethtml.StartTagToken, nethtml.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "a", "code", "pre":
+				if tt == nethtml.StartTagToken {
+					skip++
+				} else if skip > 0 {
+					skip--
+				}
+			}
+			out.Write(z.Raw())
+		case nethtml.TextToken:
+			if skip > 0 {
+				out.Write(z.Raw())
+				continue
+			}
+			out.WriteString(replace(string(z.Text()), l))
+		default:
+			out.Write(z.Raw())
+		}
+	}
+}
+
+// replace escapes text and wraps the references it resolves in anchors.
+func replace(text string, l Linker) string {
+	var out strings.Builder
+	last := 0
+
+	for _, m := range refPattern.FindAllStringSubmatchIndex(text, -1) {
+		var start, end int
+		var href, class string
+		var ok bool
+
+		switch {
+		case m[2] >= 0 && l.Task != nil:
+			start, end = m[2], m[3]
+			href, ok = l.Task(strings.ToUpper(text[start:end]))
+			class = "task-key"
+		case m[4] >= 0 && l.Mention != nil:
+			start, end = m[4], m[5]
+			for end > start+1 && text[end-1] == '.' {
+				end--
+			}
+			href, ok = l.Mention(text[start+1 : end])
+			class = "mention"
+		}
+		if !ok {
+			continue
+		}
+
+		out.WriteString(html.EscapeString(text[last:start]))
+		out.WriteString(`<a class="` + class + `" href="` + html.EscapeString(href) + `">`)
+		out.WriteString(html.EscapeString(text[start:end]))
+		out.WriteString("</a>")
+		last = end
+	}
+	out.WriteString(html.EscapeString(text[last:]))
+
+	return out.String()
+}
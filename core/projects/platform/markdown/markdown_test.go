@@ -0,0 +1,51 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	l := Linker{
+		Task: func(key string) (string, bool) {
+			return "https://app.test/tasks/" + key, key == "APP-12"
+		},
+		Mention: func(handle string) (string, bool) {
+			return "https://app.test/people/" + handle, handle == "ana"
+		},
+	}
+
+	testcases := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"empty", "  \n", ""},
+		{"escapes text", "a < b & c", "<p>a &lt; b &amp; c</p>\n"},
+		{"script block", "<script>alert(1)</script>", "\n"},
+		{"inline script", "hi <script>alert(1)</script>", "<p>hi alert(1)</p>\n"},
+		{"event handler", `<img src="x.png" onerror="alert(1)">`, "\n"},
+		{"inline event handler", `hi <a href="https://x.test" onclick="alert(1)">x</a>`, "<p>hi x</p>\n"},
+		{"javascript link", "[x](javascript:alert(1))", "<p>x</p>\n"},
+		{"safe link", "[x](https://x.test)", `<p><a href="https://x.test" rel="nofollow">x</a></p>` + "\n"},
+		{"task key", "see APP-12", `<p>see <a class="task-key" href="https://app.test/tasks/APP-12">APP-12</a></p>` + "\n"},
+		{"lowercase task key", "see app-12", `<p>see <a class="task-key" href="https://app.test/tasks/APP-12">app-12</a></p>` + "\n"},
+		{"unknown task key", "see APP-13", "<p>see APP-13</p>\n"},
+		{"task key in code", "`APP-12`", "<p><code>APP-12</code></p>\n"},
+		{"task key in link", "[APP-12](https://x.test)", `<p><a href="https://x.test" rel="nofollow">APP-12</a></p>` + "\n"},
+		{"mention", "thanks @ana.", `<p>thanks <a class="mention" href="https://app.test/people/ana">@ana</a>.</p>` + "\n"},
+		{"unknown mention", "thanks @bob", "<p>thanks @bob</p>\n"},
+		{"email is no mention", "mail ana@example.com", `<p>mail <a href="mailto:ana@example.com" rel="nofollow">ana@example.com</a></p>` + "\n"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Render(tc.src, l))
+		})
+	}
+}
+
+func TestRenderWithoutLinker(t *testing.T) {
+	assert.Equal(t, "<p>APP-12 by @ana</p>\n", Render("APP-12 by @ana", Linker{}))
+}
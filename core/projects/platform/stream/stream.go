@@ -0,0 +1,115 @@
+// Package stream fans board events out to the clients streaming a project on this replica.
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Event is a board change delivered to streaming clients.
+type Event struct {
+	ID        string          `json:"id"`
+	ProjectID string          `json:"projectId"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Hub keeps a short history of events per project and delivers new events to subscribers.
+type Hub struct {
+	mu      sync.Mutex
+	size    int
+	history map[string][]Event
+	subs    map[string]map[chan Event]struct{}
+}
+
+// NewHub creates a Hub that remembers the last size events of every project.
+func NewHub(size int) *Hub {
+	return &Hub{
+		size:    size,
+		history: make(map[string][]Event),
+		subs:    make(map[string]map[chan Event]struct{}),
+	}
+}
+
+// Publish records an event and delivers it to every subscriber of its project. A subscriber
+// that is too slow to keep up is disconnected so it can reconnect and resume from history.
+func (h *Hub) Publish(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hist := append(h.history[e.ProjectID], e)
+	if len(hist) > h.size {
+		hist = hist[len(hist)-h.size:]
+	}
+	h.history[e.ProjectID] = hist
+
+	for ch := range h.subs[e.ProjectID] {
+		select {
+		case ch <- e:
+		default:
+			delete(h.subs[e.ProjectID], ch)
+			close(ch)
+		}
+	}
+}
+
+// Forget drops the history of a project, for example once it has been deleted.
+func (h *Hub) Forget(pid string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.history, pid)
+}
+
+// Subscribe registers a subscriber for a project. The events recorded after lastID are
+// returned for replay. When lastID is set but no longer part of the history, resumed is
+// false and the client should reload the board instead.
+func (h *Hub) Subscribe(pid, lastID string) (replay []Event, resumed bool, events <-chan Event, cancel func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	resumed = lastID == ""
+	if !resumed {
+		hist := h.history[pid]
+		for i := range hist {
+			if hist[i].ID == lastID {
+				replay = append(replay, hist[i+1:]...)
+				resumed = true
+				break
+			}
+		}
+	}
+
+	ch := make(chan Event, h.size)
+	if h.subs[pid] == nil {
+		h.subs[pid] = make(map[chan Event]struct{})
+	}
+	h.subs[pid][ch] = struct{}{}
+
+	cancel = func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if _, ok := h.subs[pid][ch]; ok {
+			delete(h.subs[pid], ch)
+			close(ch)
+		}
+		if len(h.subs[pid]) == 0 {
+			delete(h.subs, pid)
+		}
+	}
+
+	return replay, resumed, ch, cancel
+}
+
+// Encode writes an event in the Server-Sent Events wire format.
+func Encode(w io.Writer, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
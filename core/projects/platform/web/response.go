@@ -30,6 +30,21 @@ func Respond(ctx context.Context, w http.ResponseWriter, val interface{}, status
 	return nil
 }
 
+// RespondDocument sends a rendered document, such as HTML or Markdown, back to the client.
+func RespondDocument(ctx context.Context, w http.ResponseWriter, doc []byte, contentType string, statusCode int) error {
+	v := ctx.Value(KeyValues).(*Values)
+	v.StatusCode = statusCode
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+
+	if _, err := w.Write(doc); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // RespondError sends an error reponse back to the client.
 func RespondError(ctx context.Context, w http.ResponseWriter, err error) error {
 
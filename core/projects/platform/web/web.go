@@ -48,7 +48,14 @@ func NewApp(shutdown chan os.Signal, logger *log.Logger, mw ...Middleware) *App
 // It converts our custom handler type to the std lib Handler type. It captures
 // errors from the handler and serves them to the client in a uniform way.
 func (a *App) Handle(method, url string, h Handler) {
-	h = wrapMiddleware(a.mw, h)
+	a.HandleWith(method, url, h, a.mw...)
+}
+
+// HandleWith associates a handler function with an HTTP Method and URL pattern,
+// wrapping it in the given middleware instead of the application's. It serves
+// routes that authenticate requests on their own.
+func (a *App) HandleWith(method, url string, h Handler, mw ...Middleware) {
+	h = wrapMiddleware(mw, h)
 
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		v := Values{
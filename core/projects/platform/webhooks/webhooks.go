@@ -0,0 +1,175 @@
+// Package webhooks signs and sends event payloads to registered endpoints.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenEndpoint is returned for endpoints that aren't public http or https URLs.
+// Deliveries must not reach the service's own network, such as cloud metadata endpoints.
+var ErrForbiddenEndpoint = errors.New("webhook endpoints must be public http or https URLs")
+
+// private lists the address ranges that are not reachable from the internet.
+var private = func() []*net.IPNet {
+	var ns []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",
+		"10.0.0.0/8",
+		"100.64.0.0/10",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+		"198.18.0.0/15",
+		"fc00::/7",
+	} {
+		_, n, _ := net.ParseCIDR(cidr)
+		ns = append(ns, n)
+	}
+	return ns
+}()
+
+// public reports whether an address may receive deliveries.
+func public(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, n := range private {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckURL makes sure an endpoint is an http or https URL whose host only resolves to
+// public addresses.
+func CheckURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrForbiddenEndpoint
+	}
+
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		if !public(ip) {
+			return ErrForbiddenEndpoint
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return ErrForbiddenEndpoint
+	}
+	for _, a := range addrs {
+		if !public(a.IP) {
+			return ErrForbiddenEndpoint
+		}
+	}
+
+	return nil
+}
+
+// NewClient returns a client for sending deliveries. It refuses to connect to addresses
+// that aren't public, so endpoints that resolve differently after they were checked, or
+// redirect, can't reach the service's own network either.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !public(ip) {
+				return ErrForbiddenEndpoint
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return ErrForbiddenEndpoint
+			}
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return nil
+		},
+	}
+}
+
+// Headers set on every request. Receivers verify SignatureHeader by computing the
+// HMAC-SHA256 of the raw request body with their secret.
+const (
+	SignatureHeader = "X-Devpie-Signature"
+	EventHeader     = "X-Devpie-Event"
+	DeliveryHeader  = "X-Devpie-Delivery"
+)
+
+// Sign returns the signature header value for a payload.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Message is a signed payload bound for an endpoint.
+type Message struct {
+	URL      string
+	Secret   string
+	Event    string
+	Delivery string
+	Payload  []byte
+}
+
+// Send posts a message and returns the response status code. Any status outside the
+// 2xx range is returned as an error together with the code.
+func Send(ctx context.Context, client *http.Client, m Message) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, m.URL, bytes.NewReader(m.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Devpie-Webhooks")
+	req.Header.Set(SignatureHeader, Sign(m.Secret, m.Payload))
+	req.Header.Set(EventHeader, m.Event)
+	req.Header.Set(DeliveryHeader, m.Delivery)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(res.Body, 1<<16))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("endpoint responded with %s", res.Status)
+	}
+
+	return res.StatusCode, nil
+}
@@ -0,0 +1,53 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckURL(t *testing.T) {
+	testcases := []struct {
+		name string
+		url  string
+		want error
+	}{
+		{"public https", "https://93.184.216.34/hooks", nil},
+		{"public http", "http://93.184.216.34:8080/hooks", nil},
+		{"other scheme", "ftp://93.184.216.34/hooks", ErrForbiddenEndpoint},
+		{"no host", "https:///hooks", ErrForbiddenEndpoint},
+		{"loopback", "http://127.0.0.1/hooks", ErrForbiddenEndpoint},
+		{"localhost", "http://localhost/hooks", ErrForbiddenEndpoint},
+		{"metadata", "http://169.254.169.254/latest/meta-data", ErrForbiddenEndpoint},
+		{"private", "http://10.0.0.12/hooks", ErrForbiddenEndpoint},
+		{"private 172", "http://172.20.1.1/hooks", ErrForbiddenEndpoint},
+		{"private 192", "http://192.168.1.1/hooks", ErrForbiddenEndpoint},
+		{"unspecified", "http://0.0.0.0/hooks", ErrForbiddenEndpoint},
+		{"ipv6 loopback", "http://[::1]/hooks", ErrForbiddenEndpoint},
+		{"ipv6 unique local", "http://[fd00::1]/hooks", ErrForbiddenEndpoint},
+		{"ipv6 link local", "http://[fe80::1]/hooks", ErrForbiddenEndpoint},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, CheckURL(context.Background(), tc.url))
+		})
+	}
+}
+
+func TestNewClientRefusesPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	code, err := Send(context.Background(), NewClient(time.Second), Message{URL: srv.URL, Payload: []byte("{}")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ErrForbiddenEndpoint.Error())
+	assert.Equal(t, 0, code)
+}
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS grants (
+grant_id VARCHAR(36) PRIMARY KEY,
+project_id VARCHAR(36) NOT NULL,
+user_id VARCHAR(36) NOT NULL,
+role ROLE DEFAULT 'viewer',
+expiration TIMESTAMP WITHOUT TIME ZONE,
+created_by VARCHAR(36) NOT NULL,
+updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+UNIQUE (project_id, user_id),
+FOREIGN KEY(project_id) REFERENCES projects (project_id)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS watchers (
+watcher_id VARCHAR(36) PRIMARY KEY,
+project_id VARCHAR(36) NOT NULL,
+task_id VARCHAR(36) NOT NULL DEFAULT '',
+user_id VARCHAR(36) NOT NULL,
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+UNIQUE (project_id, task_id, user_id),
+FOREIGN KEY(project_id) REFERENCES projects (project_id)
+);
+CREATE INDEX IF NOT EXISTS watchers_user_id_idx ON watchers (user_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS webhook_deliveries_due_idx;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP INDEX IF EXISTS webhooks_project_id_idx;
+DROP TABLE IF EXISTS webhooks;
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS webhooks (
+webhook_id VARCHAR(36) PRIMARY KEY,
+project_id VARCHAR(36) NOT NULL,
+url TEXT NOT NULL,
+secret VARCHAR(64) NOT NULL,
+events TEXT[] NOT NULL DEFAULT '{}',
+created_by VARCHAR(36) NOT NULL,
+updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+FOREIGN KEY(project_id) REFERENCES projects (project_id)
+);
+CREATE INDEX IF NOT EXISTS webhooks_project_id_idx ON webhooks (project_id);
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+delivery_id VARCHAR(36) PRIMARY KEY,
+webhook_id VARCHAR(36) NOT NULL,
+event_id VARCHAR(36) NOT NULL,
+event_type TEXT NOT NULL,
+payload TEXT NOT NULL,
+status VARCHAR(16) NOT NULL DEFAULT 'pending',
+attempts INTEGER NOT NULL DEFAULT 0,
+response_code INTEGER NOT NULL DEFAULT 0,
+last_error TEXT NOT NULL DEFAULT '',
+next_attempt_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+UNIQUE (webhook_id, event_id),
+FOREIGN KEY(webhook_id) REFERENCES webhooks (webhook_id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx ON webhook_deliveries (status, next_attempt_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS time_entries_running_idx;
+DROP INDEX IF EXISTS time_entries_user_id_idx;
+DROP INDEX IF EXISTS time_entries_project_id_idx;
+DROP TABLE IF EXISTS time_entries;
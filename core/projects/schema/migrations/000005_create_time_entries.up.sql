@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS time_entries (
+entry_id VARCHAR(36) PRIMARY KEY,
+project_id VARCHAR(36) NOT NULL,
+task_id VARCHAR(36) NOT NULL,
+user_id VARCHAR(36) NOT NULL,
+note TEXT NOT NULL DEFAULT '',
+label VARCHAR(48) NOT NULL DEFAULT '',
+billable BOOLEAN NOT NULL DEFAULT TRUE,
+started_at TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+stopped_at TIMESTAMP WITHOUT TIME ZONE,
+duration INT NOT NULL DEFAULT 0,
+updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+FOREIGN KEY(project_id) REFERENCES projects (project_id),
+FOREIGN KEY(task_id) REFERENCES tasks (task_id)
+);
+CREATE INDEX IF NOT EXISTS time_entries_project_id_idx ON time_entries (project_id, started_at);
+CREATE INDEX IF NOT EXISTS time_entries_user_id_idx ON time_entries (user_id, started_at);
+CREATE UNIQUE INDEX IF NOT EXISTS time_entries_running_idx ON time_entries (user_id) WHERE stopped_at IS NULL;
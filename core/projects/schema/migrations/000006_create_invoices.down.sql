@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS time_entries_invoice_id_idx;
+ALTER TABLE time_entries DROP COLUMN IF EXISTS locked;
+ALTER TABLE time_entries DROP COLUMN IF EXISTS invoice_id;
+DROP TABLE IF EXISTS invoice_items;
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS rates;
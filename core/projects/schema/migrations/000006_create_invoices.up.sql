@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS rates (
+rate_id VARCHAR(36) PRIMARY KEY,
+project_id VARCHAR(36) NOT NULL,
+user_id VARCHAR(36) NOT NULL DEFAULT '',
+hourly_rate INT NOT NULL,
+currency VARCHAR(3) NOT NULL DEFAULT '',
+updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+UNIQUE (project_id, user_id),
+FOREIGN KEY(project_id) REFERENCES projects (project_id)
+);
+CREATE TABLE IF NOT EXISTS invoices (
+invoice_id VARCHAR(36) PRIMARY KEY,
+project_id VARCHAR(36) NOT NULL,
+number INT NOT NULL,
+status VARCHAR(16) NOT NULL DEFAULT 'draft',
+group_by VARCHAR(8) NOT NULL DEFAULT 'task',
+period_start TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+period_end TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+currency VARCHAR(3) NOT NULL,
+total INT NOT NULL DEFAULT 0,
+created_by VARCHAR(36) NOT NULL,
+finalized_at TIMESTAMP WITHOUT TIME ZONE,
+updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+UNIQUE (project_id, number),
+FOREIGN KEY(project_id) REFERENCES projects (project_id)
+);
+CREATE TABLE IF NOT EXISTS invoice_items (
+item_id VARCHAR(36) PRIMARY KEY,
+invoice_id VARCHAR(36) NOT NULL,
+position INT NOT NULL,
+description TEXT NOT NULL,
+seconds INT NOT NULL,
+rate INT NOT NULL,
+amount INT NOT NULL,
+FOREIGN KEY(invoice_id) REFERENCES invoices (invoice_id) ON DELETE CASCADE
+);
+ALTER TABLE time_entries ADD COLUMN IF NOT EXISTS invoice_id VARCHAR(36);
+ALTER TABLE time_entries ADD COLUMN IF NOT EXISTS locked BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX IF NOT EXISTS time_entries_invoice_id_idx ON time_entries (invoice_id);
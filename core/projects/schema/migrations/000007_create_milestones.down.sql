@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS tasks_milestone_id_idx;
+ALTER TABLE tasks DROP COLUMN IF EXISTS milestone_id;
+DROP INDEX IF EXISTS milestones_project_id_idx;
+DROP TABLE IF EXISTS milestones;
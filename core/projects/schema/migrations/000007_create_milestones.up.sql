@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS milestones (
+milestone_id VARCHAR(36) PRIMARY KEY,
+project_id VARCHAR(36) NOT NULL,
+title VARCHAR(64) NOT NULL,
+description TEXT NOT NULL DEFAULT '',
+due_date TIMESTAMP WITHOUT TIME ZONE,
+created_by VARCHAR(36) NOT NULL,
+updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+FOREIGN KEY(project_id) REFERENCES projects (project_id)
+);
+CREATE INDEX IF NOT EXISTS milestones_project_id_idx ON milestones (project_id, due_date);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS milestone_id VARCHAR(36) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS tasks_milestone_id_idx ON tasks (milestone_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS approvals_task_id_idx;
+DROP TABLE IF EXISTS approvals;
+ALTER TABLE tasks DROP COLUMN IF EXISTS approvers;
+ALTER TABLE tasks DROP COLUMN IF EXISTS approval_status;
+ALTER TABLE tasks DROP COLUMN IF EXISTS needs_approval;
@@ -0,0 +1,15 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS needs_approval BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS approval_status VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS approvers TEXT[] NOT NULL DEFAULT '{}';
+CREATE TABLE IF NOT EXISTS approvals (
+approval_id VARCHAR(36) PRIMARY KEY,
+task_id VARCHAR(36) NOT NULL,
+project_id VARCHAR(36) NOT NULL,
+action VARCHAR(16) NOT NULL,
+actor_id VARCHAR(36) NOT NULL,
+approvers TEXT[] NOT NULL DEFAULT '{}',
+comment TEXT NOT NULL DEFAULT '',
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+FOREIGN KEY(project_id) REFERENCES projects (project_id)
+);
+CREATE INDEX IF NOT EXISTS approvals_task_id_idx ON approvals (task_id, created_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS report_templates;
+DROP INDEX IF EXISTS tasks_completed_at_idx;
+ALTER TABLE tasks DROP COLUMN IF EXISTS completed_at;
@@ -0,0 +1,13 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP WITHOUT TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS tasks_completed_at_idx ON tasks (project_id, completed_at);
+
+CREATE TABLE IF NOT EXISTS report_templates (
+team_id VARCHAR(36) NOT NULL,
+format VARCHAR(16) NOT NULL,
+body TEXT NOT NULL,
+updated_by VARCHAR(36) NOT NULL,
+updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+PRIMARY KEY (team_id, format)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS calendar_tokens;
+DROP INDEX IF EXISTS tasks_assigned_due_idx;
+ALTER TABLE tasks DROP COLUMN IF EXISTS due_date;
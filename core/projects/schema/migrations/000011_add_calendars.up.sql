@@ -0,0 +1,10 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS due_date TIMESTAMP WITHOUT TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS tasks_assigned_due_idx ON tasks (assigned_to, due_date);
+
+CREATE TABLE IF NOT EXISTS calendar_tokens (
+user_id VARCHAR(36) PRIMARY KEY,
+token_hash VARCHAR(64) NOT NULL UNIQUE,
+last_used_at TIMESTAMP WITHOUT TIME ZONE,
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc')
+);
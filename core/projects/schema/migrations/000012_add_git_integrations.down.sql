@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS task_links;
+DROP TABLE IF EXISTS git_integrations;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS git_integrations (
+integration_id VARCHAR(36) PRIMARY KEY,
+project_id VARCHAR(36) NOT NULL,
+provider VARCHAR(16) NOT NULL,
+secret VARCHAR(64) NOT NULL,
+merge_column_id VARCHAR(36) NOT NULL DEFAULT '',
+created_by VARCHAR(36) NOT NULL,
+updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+FOREIGN KEY(project_id) REFERENCES projects (project_id)
+);
+
+CREATE INDEX IF NOT EXISTS git_integrations_project_id_idx ON git_integrations (project_id);
+
+CREATE TABLE IF NOT EXISTS task_links (
+link_id VARCHAR(36) PRIMARY KEY,
+task_id VARCHAR(36) NOT NULL,
+project_id VARCHAR(36) NOT NULL,
+integration_id VARCHAR(36) NOT NULL,
+kind VARCHAR(16) NOT NULL,
+url TEXT NOT NULL,
+title TEXT NOT NULL DEFAULT '',
+ref VARCHAR(255) NOT NULL DEFAULT '',
+state VARCHAR(16) NOT NULL DEFAULT '',
+updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+UNIQUE (task_id, url)
+);
+
+CREATE INDEX IF NOT EXISTS task_links_task_id_idx ON task_links (task_id);
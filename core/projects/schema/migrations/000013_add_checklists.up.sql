@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS checklist_items (
+item_id VARCHAR(36) PRIMARY KEY,
+task_id VARCHAR(36) NOT NULL,
+project_id VARCHAR(36) NOT NULL,
+position INT NOT NULL,
+text TEXT NOT NULL,
+done BOOLEAN NOT NULL DEFAULT FALSE,
+assigned_to VARCHAR(36) NOT NULL DEFAULT '',
+created_by VARCHAR(36) NOT NULL,
+updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc')
+);
+
+CREATE INDEX IF NOT EXISTS checklist_items_task_id_idx ON checklist_items (task_id, position);
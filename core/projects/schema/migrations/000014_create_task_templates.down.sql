@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS task_templates;
+ALTER TABLE tasks DROP COLUMN IF EXISTS labels;
@@ -0,0 +1,16 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS labels TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE TABLE IF NOT EXISTS task_templates (
+template_id VARCHAR(36) PRIMARY KEY,
+project_id VARCHAR(36) NOT NULL,
+name VARCHAR(64) NOT NULL,
+title_pattern VARCHAR(128) NOT NULL DEFAULT '',
+content TEXT NOT NULL DEFAULT '',
+labels TEXT[] NOT NULL DEFAULT '{}',
+points INT NOT NULL DEFAULT 0,
+checklist TEXT[] NOT NULL DEFAULT '{}',
+created_by VARCHAR(36) NOT NULL,
+updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+UNIQUE (project_id, name)
+);
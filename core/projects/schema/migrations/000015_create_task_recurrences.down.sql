@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS task_recurrences;
+ALTER TABLE tasks DROP COLUMN IF EXISTS recurrence_id;
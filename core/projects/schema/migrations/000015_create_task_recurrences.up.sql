@@ -0,0 +1,21 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence_id VARCHAR(36) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS task_recurrences (
+recurrence_id VARCHAR(36) PRIMARY KEY,
+project_id VARCHAR(36) NOT NULL,
+column_id VARCHAR(36) NOT NULL,
+task_id VARCHAR(36) NOT NULL,
+frequency VARCHAR(8) NOT NULL,
+every INT NOT NULL DEFAULT 1,
+weekdays TEXT[] NOT NULL DEFAULT '{}',
+max_occurrences INT,
+until TIMESTAMP WITHOUT TIME ZONE,
+occurrences INT NOT NULL DEFAULT 1,
+starts_at TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+next_run_at TIMESTAMP WITHOUT TIME ZONE,
+created_by VARCHAR(36) NOT NULL,
+updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc')
+);
+
+CREATE INDEX IF NOT EXISTS task_recurrences_next_run_at_idx ON task_recurrences (next_run_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS custom_fields;
+DROP INDEX IF EXISTS tasks_custom_fields_idx;
+ALTER TABLE tasks DROP COLUMN IF EXISTS custom_fields;
@@ -0,0 +1,15 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '{}';
+CREATE INDEX IF NOT EXISTS tasks_custom_fields_idx ON tasks USING GIN (custom_fields);
+
+CREATE TABLE IF NOT EXISTS custom_fields (
+field_id VARCHAR(36) PRIMARY KEY,
+project_id VARCHAR(36) NOT NULL,
+name VARCHAR(48) NOT NULL,
+type VARCHAR(16) NOT NULL,
+options TEXT[] NOT NULL DEFAULT '{}',
+position INT NOT NULL,
+created_by VARCHAR(36) NOT NULL,
+updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+UNIQUE (project_id, name)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS saved_views (
+view_id VARCHAR(36) PRIMARY KEY,
+project_id VARCHAR(36) NOT NULL,
+user_id VARCHAR(36) NOT NULL,
+name VARCHAR(64) NOT NULL,
+filter JSONB NOT NULL DEFAULT '{}',
+group_by VARCHAR(48) NOT NULL DEFAULT '',
+shared BOOLEAN NOT NULL DEFAULT FALSE,
+updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc')
+);
+
+CREATE INDEX IF NOT EXISTS saved_views_project_id_idx ON saved_views (project_id, user_id);
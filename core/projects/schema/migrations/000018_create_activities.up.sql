@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS activities (
+activity_id VARCHAR(36) PRIMARY KEY,
+seq BIGSERIAL UNIQUE,
+project_id VARCHAR(36) NOT NULL,
+team_id VARCHAR(36) NOT NULL DEFAULT '',
+actor_id VARCHAR(36) NOT NULL DEFAULT '',
+type VARCHAR(48) NOT NULL,
+task_id VARCHAR(36) NOT NULL DEFAULT '',
+internal BOOLEAN NOT NULL DEFAULT FALSE,
+data JSONB NOT NULL DEFAULT '{}',
+occurred_at TIMESTAMP WITHOUT TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS activities_project_id_idx ON activities (project_id, seq DESC);
+CREATE INDEX IF NOT EXISTS activities_team_id_idx ON activities (team_id, seq DESC);
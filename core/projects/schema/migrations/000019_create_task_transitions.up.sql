@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS task_transitions (
+transition_id VARCHAR(36) PRIMARY KEY,
+project_id VARCHAR(36) NOT NULL,
+task_id VARCHAR(36) NOT NULL,
+column_id VARCHAR(36) NOT NULL,
+column_title VARCHAR(36) NOT NULL,
+entered_at TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+exited_at TIMESTAMP WITHOUT TIME ZONE,
+FOREIGN KEY(project_id) REFERENCES projects (project_id)
+);
+
+CREATE INDEX IF NOT EXISTS task_transitions_task_id_idx ON task_transitions (task_id, entered_at);
+CREATE INDEX IF NOT EXISTS task_transitions_project_id_idx ON task_transitions (project_id, exited_at);
+CREATE UNIQUE INDEX IF NOT EXISTS task_transitions_open_idx ON task_transitions (task_id) WHERE exited_at IS NULL;
+
+-- history starts now for tasks already on a board
+INSERT INTO task_transitions (transition_id, project_id, task_id, column_id, column_title, entered_at)
+SELECT DISTINCT ON (t.task_id) md5(t.task_id || c.column_id)::uuid::text, t.project_id, t.task_id, c.column_id,
+c.title, NOW() AT TIME ZONE 'utc'
+FROM tasks t JOIN columns c ON c.project_id = t.project_id AND t.task_id = ANY(c.task_ids)
+ORDER BY t.task_id, c.updated_at DESC;
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS user_roles (
+user_id VARCHAR(36) PRIMARY KEY,
+client BOOLEAN NOT NULL,
+updated_at TIMESTAMP WITHOUT TIME ZONE NOT NULL
+);
@@ -0,0 +1,2 @@
+ALTER TABLE user_roles DROP COLUMN IF EXISTS nickname;
+ALTER TABLE user_roles DROP COLUMN IF EXISTS email;
@@ -0,0 +1,2 @@
+ALTER TABLE user_roles ADD COLUMN IF NOT EXISTS email VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE user_roles ADD COLUMN IF NOT EXISTS nickname VARCHAR(255) NOT NULL DEFAULT '';
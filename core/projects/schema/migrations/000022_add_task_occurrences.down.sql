@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS tasks_recurrence_occurrence_idx;
+ALTER TABLE tasks DROP COLUMN IF EXISTS occurrence;
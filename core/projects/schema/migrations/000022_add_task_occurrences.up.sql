@@ -0,0 +1,3 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS occurrence INT NOT NULL DEFAULT 0;
+
+CREATE UNIQUE INDEX IF NOT EXISTS tasks_recurrence_occurrence_idx ON tasks (recurrence_id, occurrence) WHERE occurrence > 0;
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/devpies/devpie-client-core/users/domain/digests"
+	"github.com/devpies/devpie-client-core/users/platform/auth0"
+	"github.com/devpies/devpie-client-core/users/platform/database"
+	"github.com/devpies/devpie-client-core/users/platform/web"
+)
+
+// Digest defines digest subscription handlers and their dependencies
+type Digest struct {
+	repo  database.Storer
+	log   *log.Logger
+	auth0 auth0.Auther
+	query DigestQueries
+}
+
+// DigestQueries defines queries required by digest handlers
+type DigestQueries struct {
+	digest digests.DigestQuerier
+}
+
+// Retrieve returns the digest subscription of the authenticated user
+func (d *Digest) Retrieve(w http.ResponseWriter, r *http.Request) error {
+	uid := d.auth0.UserByID(r.Context())
+
+	s, err := d.query.digest.Retrieve(r.Context(), d.repo, uid)
+	if err != nil {
+		switch err {
+		case digests.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case digests.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("failed to retrieve digest subscription: %w", err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, s, http.StatusOK)
+}
+
+// Subscribe opts the authenticated user in to digests or changes their schedule
+func (d *Digest) Subscribe(w http.ResponseWriter, r *http.Request) error {
+	var ns digests.NewSubscription
+
+	if err := web.Decode(r, &ns); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return err
+	}
+
+	if _, err := time.LoadLocation(ns.TimeZone); err != nil {
+		return web.NewRequestError(digests.ErrInvalidTimeZone, http.StatusBadRequest)
+	}
+
+	uid := d.auth0.UserByID(r.Context())
+
+	s, err := d.query.digest.Subscribe(r.Context(), d.repo, uid, ns, time.Now())
+	if err != nil {
+		switch err {
+		case digests.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("failed to subscribe to digests: %w", err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, s, http.StatusOK)
+}
+
+// Unsubscribe opts the authenticated user out of digests
+func (d *Digest) Unsubscribe(w http.ResponseWriter, r *http.Request) error {
+	uid := d.auth0.UserByID(r.Context())
+
+	if err := d.query.digest.Unsubscribe(r.Context(), d.repo, uid); err != nil {
+		switch err {
+		case digests.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case digests.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("failed to unsubscribe from digests: %w", err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
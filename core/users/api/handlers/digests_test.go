@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devpies/devpie-client-core/users/domain/digests"
+	mockQuery "github.com/devpies/devpie-client-core/users/domain/mocks"
+	mockAuth "github.com/devpies/devpie-client-core/users/platform/auth0/mocks"
+	th "github.com/devpies/devpie-client-core/users/platform/testhelpers"
+	"github.com/devpies/devpie-client-core/users/platform/web"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newSubscription() digests.NewSubscription {
+	return digests.NewSubscription{
+		Frequency: "weekly",
+		TimeZone:  "Europe/Berlin",
+	}
+}
+
+func subscription() digests.Subscription {
+	return digests.Subscription{
+		UserID:    "a4b54ec1-57f9-4c39-ab53-d936dbb6c177",
+		Frequency: "weekly",
+		TimeZone:  "Europe/Berlin",
+		UpdatedAt: time.Now(),
+		CreatedAt: time.Now(),
+	}
+}
+
+func subscriptionJSON(ns digests.NewSubscription) string {
+	return fmt.Sprintf(`{ "frequency": "%s", "timeZone": "%s" }`, ns.Frequency, ns.TimeZone)
+}
+
+func setupDigestMocks() *Digest {
+	return &Digest{
+		repo:  th.Repo(),
+		auth0: &mockAuth.Auther{},
+		query: DigestQueries{&mockQuery.DigestQuerier{}},
+	}
+}
+
+func TestDigest_Retrieve_200(t *testing.T) {
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+	s := subscription()
+
+	// setup mocks
+	fake := setupDigestMocks()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+	fake.query.digest.(*mockQuery.DigestQuerier).On("Retrieve", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid).Return(s, nil)
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_ = fake.Retrieve(w, r)
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/", nil)
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Handler Response", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, writer.Code)
+	})
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.digest.(*mockQuery.DigestQuerier).AssertExpectations(t)
+	})
+}
+
+func TestDigest_Retrieve_404(t *testing.T) {
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+
+	// setup mocks
+	fake := setupDigestMocks()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+	fake.query.digest.(*mockQuery.DigestQuerier).On("Retrieve", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid).Return(digests.Subscription{}, digests.ErrNotFound)
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		var webErr *web.Error
+		err := fake.Retrieve(w, r)
+
+		t.Run("Assert Handler Response", func(t *testing.T) {
+			assert.True(t, errors.As(err, &webErr))
+			assert.True(t, errors.Is(err.(*web.Error).Err, digests.ErrNotFound))
+			assert.Equal(t, http.StatusNotFound, err.(*web.Error).Status)
+		})
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/", nil)
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.digest.(*mockQuery.DigestQuerier).AssertExpectations(t)
+	})
+}
+
+func TestDigest_Subscribe_200(t *testing.T) {
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+	ns := newSubscription()
+	s := subscription()
+
+	// setup mocks
+	fake := setupDigestMocks()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+	fake.query.digest.(*mockQuery.DigestQuerier).On("Subscribe", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid, ns, mock.AnythingOfType("time.Time")).Return(s, nil)
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_ = fake.Subscribe(w, r)
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(subscriptionJSON(ns)))
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Handler Response", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, writer.Code)
+	})
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.digest.(*mockQuery.DigestQuerier).AssertExpectations(t)
+	})
+}
+
+func TestDigest_Subscribe_400_Invalid_Payload(t *testing.T) {
+	//setup mocks
+	fake := setupDigestMocks()
+
+	testcases := []struct {
+		name string
+		arg  string
+	}{
+		{"empty payload", ""},
+		{"empty object", "{}"},
+		{"unknown frequency", `{ "frequency": "hourly", "timeZone": "UTC" }`},
+	}
+	for _, v := range testcases {
+		// setup server
+		mux := chi.NewMux()
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			err := fake.Subscribe(w, r)
+
+			t.Run(fmt.Sprintf("Assert Handler Response/%s", v.name), func(t *testing.T) {
+				assert.NotNil(t, err)
+			})
+		})
+
+		// make request
+		writer := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(v.arg))
+		mux.ServeHTTP(writer, request)
+
+		t.Run(fmt.Sprintf("Assert Server Response/%s", v.name), func(t *testing.T) {
+			assert.Equal(t, http.StatusBadRequest, writer.Code)
+		})
+	}
+}
+
+func TestDigest_Subscribe_400_Invalid_TimeZone(t *testing.T) {
+	ns := newSubscription()
+	ns.TimeZone = "Mars/Olympus_Mons"
+
+	// setup mocks
+	fake := setupDigestMocks()
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		var webErr *web.Error
+		err := fake.Subscribe(w, r)
+
+		t.Run("Assert Handler Response", func(t *testing.T) {
+			assert.True(t, errors.As(err, &webErr))
+			assert.True(t, errors.Is(err.(*web.Error).Err, digests.ErrInvalidTimeZone))
+			assert.Equal(t, http.StatusBadRequest, err.(*web.Error).Status)
+		})
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(subscriptionJSON(ns)))
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.digest.(*mockQuery.DigestQuerier).AssertExpectations(t)
+	})
+}
+
+func TestDigest_Subscribe_500_Uncaught_Error(t *testing.T) {
+	cause := errors.New("something went wrong")
+
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+	ns := newSubscription()
+
+	// setup mocks
+	fake := setupDigestMocks()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+	fake.query.digest.(*mockQuery.DigestQuerier).On("Subscribe", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid, ns, mock.AnythingOfType("time.Time")).Return(digests.Subscription{}, cause)
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		err := fake.Subscribe(w, r)
+
+		t.Run("Assert Handler Response", func(t *testing.T) {
+			assert.True(t, errors.Is(err, cause))
+			assert.Equal(t, fmt.Sprintf(`failed to subscribe to digests: %s`, cause), err.Error())
+		})
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(subscriptionJSON(ns)))
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.digest.(*mockQuery.DigestQuerier).AssertExpectations(t)
+	})
+}
+
+func TestDigest_Unsubscribe_200(t *testing.T) {
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+
+	// setup mocks
+	fake := setupDigestMocks()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+	fake.query.digest.(*mockQuery.DigestQuerier).On("Unsubscribe", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid).Return(nil)
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_ = fake.Unsubscribe(w, r)
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodDelete, "/", nil)
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Handler Response", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, writer.Code)
+	})
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.digest.(*mockQuery.DigestQuerier).AssertExpectations(t)
+	})
+}
+
+func TestDigest_Unsubscribe_404(t *testing.T) {
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+
+	// setup mocks
+	fake := setupDigestMocks()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+	fake.query.digest.(*mockQuery.DigestQuerier).On("Unsubscribe", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid).Return(digests.ErrNotFound)
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		var webErr *web.Error
+		err := fake.Unsubscribe(w, r)
+
+		t.Run("Assert Handler Response", func(t *testing.T) {
+			assert.True(t, errors.As(err, &webErr))
+			assert.True(t, errors.Is(err.(*web.Error).Err, digests.ErrNotFound))
+			assert.Equal(t, http.StatusNotFound, err.(*web.Error).Status)
+		})
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodDelete, "/", nil)
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.digest.(*mockQuery.DigestQuerier).AssertExpectations(t)
+	})
+}
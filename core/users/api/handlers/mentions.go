@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/devpies/devpie-client-core/users/domain/mentions"
+	"github.com/devpies/devpie-client-core/users/platform/auth0"
+	"github.com/devpies/devpie-client-core/users/platform/database"
+	"github.com/devpies/devpie-client-core/users/platform/web"
+)
+
+// Mention defines mention handlers and their dependencies
+type Mention struct {
+	repo  database.Storer
+	log   *log.Logger
+	auth0 auth0.Auther
+	query MentionQueries
+}
+
+// MentionQueries defines queries required by mention handlers
+type MentionQueries struct {
+	mention mentions.MentionQuerier
+}
+
+// List returns everywhere the authenticated user has been mentioned. With flagged=true it
+// returns the mentions the user wrote that could not be resolved instead.
+func (m *Mention) List(w http.ResponseWriter, r *http.Request) error {
+	var ms []mentions.Mention
+	var err error
+
+	uid := m.auth0.UserByID(r.Context())
+
+	if r.URL.Query().Get("flagged") == "true" {
+		ms, err = m.query.mention.ListFlagged(r.Context(), m.repo, uid)
+	} else {
+		ms, err = m.query.mention.List(r.Context(), m.repo, uid)
+	}
+	if err != nil {
+		switch err {
+		case mentions.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("failed to retrieve mentions: %w", err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, ms, http.StatusOK)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/devpies/devpie-client-core/users/domain/mentions"
+	mockQuery "github.com/devpies/devpie-client-core/users/domain/mocks"
+	mockAuth "github.com/devpies/devpie-client-core/users/platform/auth0/mocks"
+	th "github.com/devpies/devpie-client-core/users/platform/testhelpers"
+	"github.com/devpies/devpie-client-core/users/platform/web"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mention() mentions.Mention {
+	return mentions.Mention{
+		ID:        "7d0c1e6a-3f7b-4b8e-9a51-2c4d5e6f7a80",
+		ProjectID: "8695a94f-7e0a-4198-8c0a-d3e12727a5ba",
+		TaskID:    "b2a1c3d4-5e6f-4a7b-8c9d-0e1f2a3b4c5d",
+		Location:  "content",
+		Handle:    "testuser",
+		UserID:    th.StringPointer("a4b54ec1-57f9-4c39-ab53-d936dbb6c177"),
+		ActorID:   th.StringPointer("d3f1c7a2-5b8e-4e1a-9c2d-0a1b2c3d4e5f"),
+		Resolved:  true,
+		CreatedAt: time.Now(),
+	}
+}
+
+func setupMentionMocks() *Mention {
+	return &Mention{
+		repo:  th.Repo(),
+		auth0: &mockAuth.Auther{},
+		query: MentionQueries{&mockQuery.MentionQuerier{}},
+	}
+}
+
+func TestMention_List_200(t *testing.T) {
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+	ms := []mentions.Mention{mention()}
+
+	testcases := []struct {
+		name   string
+		query  string
+		method string
+	}{
+		{"mentioned", "", "List"},
+		{"flagged", "?flagged=true", "ListFlagged"},
+	}
+	for _, v := range testcases {
+		// setup mocks
+		fake := setupMentionMocks()
+		fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+		fake.query.mention.(*mockQuery.MentionQuerier).On(v.method, mock.AnythingOfType("*context.valueCtx"), fake.repo, uid).Return(ms, nil)
+
+		// setup server
+		mux := chi.NewMux()
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			_ = fake.List(w, r)
+		})
+
+		// make request
+		writer := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/%s", v.query), nil)
+		mux.ServeHTTP(writer, request)
+
+		t.Run(fmt.Sprintf("Assert Handler Response/%s", v.name), func(t *testing.T) {
+			assert.Equal(t, http.StatusOK, writer.Code)
+		})
+
+		t.Run(fmt.Sprintf("Assert Mock Expectations/%s", v.name), func(t *testing.T) {
+			fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+			fake.query.mention.(*mockQuery.MentionQuerier).AssertExpectations(t)
+		})
+	}
+}
+
+func TestMention_List_400(t *testing.T) {
+	uid := "mock"
+
+	// setup mocks
+	fake := setupMentionMocks()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+	fake.query.mention.(*mockQuery.MentionQuerier).On("List", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid).Return(nil, mentions.ErrInvalidID)
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		var webErr *web.Error
+		err := fake.List(w, r)
+
+		t.Run("Assert Handler Response", func(t *testing.T) {
+			assert.True(t, errors.As(err, &webErr))
+			assert.True(t, errors.Is(err.(*web.Error).Err, mentions.ErrInvalidID))
+			assert.Equal(t, http.StatusBadRequest, err.(*web.Error).Status)
+		})
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/", nil)
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.mention.(*mockQuery.MentionQuerier).AssertExpectations(t)
+	})
+}
+
+func TestMention_List_500_Uncaught_Error(t *testing.T) {
+	cause := errors.New("something went wrong")
+
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+
+	// setup mocks
+	fake := setupMentionMocks()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+	fake.query.mention.(*mockQuery.MentionQuerier).On("ListFlagged", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid).Return(nil, cause)
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		err := fake.List(w, r)
+
+		t.Run("Assert Handler Response", func(t *testing.T) {
+			assert.True(t, errors.Is(err, cause))
+			assert.Equal(t, fmt.Sprintf(`failed to retrieve mentions: %s`, cause), err.Error())
+		})
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/?flagged=true", nil)
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.mention.(*mockQuery.MentionQuerier).AssertExpectations(t)
+	})
+}
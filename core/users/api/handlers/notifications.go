@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/devpies/devpie-client-core/users/domain/notifications"
+	"github.com/devpies/devpie-client-core/users/platform/auth0"
+	"github.com/devpies/devpie-client-core/users/platform/database"
+	"github.com/devpies/devpie-client-core/users/platform/web"
+	"github.com/go-chi/chi"
+)
+
+// Notification defines notification handlers and their dependencies
+type Notification struct {
+	repo  database.Storer
+	log   *log.Logger
+	auth0 auth0.Auther
+	query NotificationQueries
+}
+
+// NotificationQueries defines queries required by notification handlers
+type NotificationQueries struct {
+	notification notifications.NotificationQuerier
+}
+
+// List returns the notifications of the authenticated user
+func (n *Notification) List(w http.ResponseWriter, r *http.Request) error {
+	uid := n.auth0.UserByID(r.Context())
+	unread := r.URL.Query().Get("unread") == "true"
+
+	ns, err := n.query.notification.List(r.Context(), n.repo, uid, unread)
+	if err != nil {
+		switch err {
+		case notifications.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("failed to retrieve notifications: %w", err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, ns, http.StatusOK)
+}
+
+// UnreadCount returns the number of unread notifications of the authenticated user
+func (n *Notification) UnreadCount(w http.ResponseWriter, r *http.Request) error {
+	uid := n.auth0.UserByID(r.Context())
+
+	count, err := n.query.notification.CountUnread(r.Context(), n.repo, uid)
+	if err != nil {
+		switch err {
+		case notifications.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("failed to count notifications: %w", err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, notifications.UnreadCount{Count: count}, http.StatusOK)
+}
+
+// MarkRead marks a notification of the authenticated user as read
+func (n *Notification) MarkRead(w http.ResponseWriter, r *http.Request) error {
+	uid := n.auth0.UserByID(r.Context())
+	nid := chi.URLParam(r, "nid")
+
+	if err := n.query.notification.MarkRead(r.Context(), n.repo, uid, nid, time.Now()); err != nil {
+		switch err {
+		case notifications.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case notifications.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("failed to mark notification as read: %w", err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+// MarkAllRead marks every notification of the authenticated user as read
+func (n *Notification) MarkAllRead(w http.ResponseWriter, r *http.Request) error {
+	uid := n.auth0.UserByID(r.Context())
+
+	if err := n.query.notification.MarkAllRead(r.Context(), n.repo, uid, time.Now()); err != nil {
+		switch err {
+		case notifications.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("failed to mark notifications as read: %w", err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
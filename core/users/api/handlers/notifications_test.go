@@ -0,0 +1,363 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockQuery "github.com/devpies/devpie-client-core/users/domain/mocks"
+	"github.com/devpies/devpie-client-core/users/domain/notifications"
+	mockAuth "github.com/devpies/devpie-client-core/users/platform/auth0/mocks"
+	th "github.com/devpies/devpie-client-core/users/platform/testhelpers"
+	"github.com/devpies/devpie-client-core/users/platform/web"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func notification() notifications.Notification {
+	return notifications.Notification{
+		ID:         "5c1a2b1e-0f5e-4d6a-8a55-6a2f2b6a7c01",
+		UserID:     "a4b54ec1-57f9-4c39-ab53-d936dbb6c177",
+		ActorID:    th.StringPointer("d3f1c7a2-5b8e-4e1a-9c2d-0a1b2c3d4e5f"),
+		Type:       notifications.TeamInvite.String(),
+		Message:    "You've been invited to a team",
+		ResourceID: th.StringPointer("39541c75-ca3e-4e2b-9728-54327772d001"),
+		UpdatedAt:  time.Now(),
+		CreatedAt:  time.Now(),
+	}
+}
+
+func setupNotificationMocks() *Notification {
+	return &Notification{
+		repo:  th.Repo(),
+		auth0: &mockAuth.Auther{},
+		query: NotificationQueries{&mockQuery.NotificationQuerier{}},
+	}
+}
+
+func TestNotification_List_200(t *testing.T) {
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+	ns := []notifications.Notification{notification()}
+
+	testcases := []struct {
+		name   string
+		query  string
+		unread bool
+	}{
+		{"all", "", false},
+		{"unread", "?unread=true", true},
+	}
+	for _, v := range testcases {
+		// setup mocks
+		fake := setupNotificationMocks()
+		fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+		fake.query.notification.(*mockQuery.NotificationQuerier).On("List", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid, v.unread).Return(ns, nil)
+
+		// setup server
+		mux := chi.NewMux()
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			_ = fake.List(w, r)
+		})
+
+		// make request
+		writer := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/%s", v.query), nil)
+		mux.ServeHTTP(writer, request)
+
+		t.Run(fmt.Sprintf("Assert Handler Response/%s", v.name), func(t *testing.T) {
+			assert.Equal(t, http.StatusOK, writer.Code)
+		})
+
+		t.Run(fmt.Sprintf("Assert Mock Expectations/%s", v.name), func(t *testing.T) {
+			fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+			fake.query.notification.(*mockQuery.NotificationQuerier).AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotification_List_400(t *testing.T) {
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+
+	// setup mocks
+	fake := setupNotificationMocks()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+	fake.query.notification.(*mockQuery.NotificationQuerier).On("List", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid, false).Return(nil, notifications.ErrInvalidID)
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		var webErr *web.Error
+		err := fake.List(w, r)
+
+		t.Run("Assert Handler Response", func(t *testing.T) {
+			assert.True(t, errors.As(err, &webErr))
+			assert.True(t, errors.Is(err.(*web.Error).Err, notifications.ErrInvalidID))
+			assert.Equal(t, http.StatusBadRequest, err.(*web.Error).Status)
+		})
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/", nil)
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.notification.(*mockQuery.NotificationQuerier).AssertExpectations(t)
+	})
+}
+
+func TestNotification_List_500_Uncaught_Error(t *testing.T) {
+	cause := errors.New("something went wrong")
+
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+
+	// setup mocks
+	fake := setupNotificationMocks()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+	fake.query.notification.(*mockQuery.NotificationQuerier).On("List", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid, false).Return(nil, cause)
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		err := fake.List(w, r)
+
+		t.Run("Assert Handler Response", func(t *testing.T) {
+			assert.True(t, errors.Is(err, cause))
+			assert.Equal(t, fmt.Sprintf(`failed to retrieve notifications: %s`, cause), err.Error())
+		})
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/", nil)
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.notification.(*mockQuery.NotificationQuerier).AssertExpectations(t)
+	})
+}
+
+func TestNotification_UnreadCount_200(t *testing.T) {
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+
+	// setup mocks
+	fake := setupNotificationMocks()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+	fake.query.notification.(*mockQuery.NotificationQuerier).On("CountUnread", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid).Return(3, nil)
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_ = fake.UnreadCount(w, r)
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/", nil)
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Handler Response", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, writer.Code)
+		assert.JSONEq(t, `{ "count": 3 }`, writer.Body.String())
+	})
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.notification.(*mockQuery.NotificationQuerier).AssertExpectations(t)
+	})
+}
+
+func TestNotification_UnreadCount_500_Uncaught_Error(t *testing.T) {
+	cause := errors.New("something went wrong")
+
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+
+	// setup mocks
+	fake := setupNotificationMocks()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+	fake.query.notification.(*mockQuery.NotificationQuerier).On("CountUnread", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid).Return(0, cause)
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		err := fake.UnreadCount(w, r)
+
+		t.Run("Assert Handler Response", func(t *testing.T) {
+			assert.True(t, errors.Is(err, cause))
+			assert.Equal(t, fmt.Sprintf(`failed to count notifications: %s`, cause), err.Error())
+		})
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/", nil)
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.notification.(*mockQuery.NotificationQuerier).AssertExpectations(t)
+	})
+}
+
+func TestNotification_MarkRead_200(t *testing.T) {
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+	n := notification()
+
+	// setup mocks
+	fake := setupNotificationMocks()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+	fake.query.notification.(*mockQuery.NotificationQuerier).On("MarkRead", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid, n.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/{nid}", func(w http.ResponseWriter, r *http.Request) {
+		_ = fake.MarkRead(w, r)
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/%s", n.ID), nil)
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Handler Response", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, writer.Code)
+	})
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.notification.(*mockQuery.NotificationQuerier).AssertExpectations(t)
+	})
+}
+
+func TestNotification_MarkRead_400(t *testing.T) {
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+	nid := "mock"
+
+	// setup mocks
+	fake := setupNotificationMocks()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+	fake.query.notification.(*mockQuery.NotificationQuerier).On("MarkRead", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid, nid, mock.AnythingOfType("time.Time")).Return(notifications.ErrInvalidID)
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/{nid}", func(w http.ResponseWriter, r *http.Request) {
+		var webErr *web.Error
+		err := fake.MarkRead(w, r)
+
+		t.Run("Assert Handler Response", func(t *testing.T) {
+			assert.True(t, errors.As(err, &webErr))
+			assert.True(t, errors.Is(err.(*web.Error).Err, notifications.ErrInvalidID))
+			assert.Equal(t, http.StatusBadRequest, err.(*web.Error).Status)
+		})
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/%s", nid), nil)
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.notification.(*mockQuery.NotificationQuerier).AssertExpectations(t)
+	})
+}
+
+func TestNotification_MarkRead_404(t *testing.T) {
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+	n := notification()
+
+	// setup mocks
+	fake := setupNotificationMocks()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+	fake.query.notification.(*mockQuery.NotificationQuerier).On("MarkRead", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid, n.ID, mock.AnythingOfType("time.Time")).Return(notifications.ErrNotFound)
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/{nid}", func(w http.ResponseWriter, r *http.Request) {
+		var webErr *web.Error
+		err := fake.MarkRead(w, r)
+
+		t.Run("Assert Handler Response", func(t *testing.T) {
+			assert.True(t, errors.As(err, &webErr))
+			assert.True(t, errors.Is(err.(*web.Error).Err, notifications.ErrNotFound))
+			assert.Equal(t, http.StatusNotFound, err.(*web.Error).Status)
+		})
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/%s", n.ID), nil)
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.notification.(*mockQuery.NotificationQuerier).AssertExpectations(t)
+	})
+}
+
+func TestNotification_MarkAllRead_200(t *testing.T) {
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+
+	// setup mocks
+	fake := setupNotificationMocks()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+	fake.query.notification.(*mockQuery.NotificationQuerier).On("MarkAllRead", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid, mock.AnythingOfType("time.Time")).Return(nil)
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_ = fake.MarkAllRead(w, r)
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, "/", nil)
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Handler Response", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, writer.Code)
+	})
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.notification.(*mockQuery.NotificationQuerier).AssertExpectations(t)
+	})
+}
+
+func TestNotification_MarkAllRead_500_Uncaught_Error(t *testing.T) {
+	cause := errors.New("something went wrong")
+
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+
+	// setup mocks
+	fake := setupNotificationMocks()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid)
+	fake.query.notification.(*mockQuery.NotificationQuerier).On("MarkAllRead", mock.AnythingOfType("*context.valueCtx"), fake.repo, uid, mock.AnythingOfType("time.Time")).Return(cause)
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		err := fake.MarkAllRead(w, r)
+
+		t.Run("Assert Handler Response", func(t *testing.T) {
+			assert.True(t, errors.Is(err, cause))
+			assert.Equal(t, fmt.Sprintf(`failed to mark notifications as read: %s`, cause), err.Error())
+		})
+	})
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, "/", nil)
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.notification.(*mockQuery.NotificationQuerier).AssertExpectations(t)
+	})
+}
@@ -7,8 +7,11 @@ import (
 
 	mid "github.com/devpies/devpie-client-core/users/api/middleware"
 	"github.com/devpies/devpie-client-core/users/api/publishers"
+	"github.com/devpies/devpie-client-core/users/domain/digests"
 	"github.com/devpies/devpie-client-core/users/domain/invites"
 	"github.com/devpies/devpie-client-core/users/domain/memberships"
+	"github.com/devpies/devpie-client-core/users/domain/mentions"
+	"github.com/devpies/devpie-client-core/users/domain/notifications"
 	"github.com/devpies/devpie-client-core/users/domain/projects"
 	"github.com/devpies/devpie-client-core/users/domain/teams"
 	"github.com/devpies/devpie-client-core/users/domain/users"
@@ -33,7 +36,9 @@ func API(shutdown chan os.Signal, repo database.Storer, log *log.Logger, origins
 		MAPIAudience: auth0MAPIAudience,
 	}
 
-	app := web.NewApp(shutdown, log, mid.Logger(log), a0.Authenticate(), mid.Errors(log), mid.Panics(log))
+	rl := &Roles{repo: repo, auth0: a0, query: &users.Queries{}}
+
+	app := web.NewApp(shutdown, log, mid.Logger(log), a0.Authenticate(), mid.Errors(log), mid.Panics(log), rl.Record())
 
 	h := HealthCheck{repo: repo}
 
@@ -48,10 +53,14 @@ func API(shutdown chan os.Signal, repo database.Storer, log *log.Logger, origins
 			&memberships.Queries{},
 			&users.Queries{},
 			&invites.Queries{},
+			&notifications.Queries{},
 		},
 		&publishers.Publishers{},
 	}
 	m := Membership{repo, log, a0, nats, MembershipQueries{&memberships.Queries{}}}
+	n := Notification{repo, log, a0, NotificationQueries{&notifications.Queries{}}}
+	mn := Mention{repo, log, a0, MentionQueries{&mentions.Queries{}}}
+	d := Digest{repo, log, a0, DigestQueries{&digests.Queries{}}}
 
 	app.Handle(http.MethodPost, "/api/v1/users", u.Create)
 	app.Handle(http.MethodGet, "/api/v1/users/me", u.RetrieveMe)
@@ -66,5 +75,16 @@ func API(shutdown chan os.Signal, repo database.Storer, log *log.Logger, origins
 	app.Handle(http.MethodGet, "/api/v1/users/teams/{tid}/members", m.RetrieveMemberships)
 	app.Handle(http.MethodPatch, "/api/v1/users/teams/{tid}/invites/{iid}", tm.UpdateInvite)
 
+	app.Handle(http.MethodGet, "/api/v1/users/notifications", n.List)
+	app.Handle(http.MethodGet, "/api/v1/users/notifications/unread", n.UnreadCount)
+	app.Handle(http.MethodPost, "/api/v1/users/notifications/read", n.MarkAllRead)
+	app.Handle(http.MethodPost, "/api/v1/users/notifications/{nid}/read", n.MarkRead)
+
+	app.Handle(http.MethodGet, "/api/v1/users/mentions", mn.List)
+
+	app.Handle(http.MethodGet, "/api/v1/users/digest", d.Retrieve)
+	app.Handle(http.MethodPut, "/api/v1/users/digest", d.Subscribe)
+	app.Handle(http.MethodDelete, "/api/v1/users/digest", d.Unsubscribe)
+
 	return Cors(origins).Handler(app)
 }
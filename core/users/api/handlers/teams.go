@@ -10,6 +10,7 @@ import (
 	"github.com/devpies/devpie-client-core/users/api/publishers"
 	"github.com/devpies/devpie-client-core/users/domain/invites"
 	"github.com/devpies/devpie-client-core/users/domain/memberships"
+	"github.com/devpies/devpie-client-core/users/domain/notifications"
 	"github.com/devpies/devpie-client-core/users/domain/projects"
 	"github.com/devpies/devpie-client-core/users/domain/teams"
 	"github.com/devpies/devpie-client-core/users/domain/users"
@@ -36,11 +37,12 @@ type Team struct {
 
 // TeamQueries defines queries required by team handlers
 type TeamQueries struct {
-	team       teams.TeamQuerier
-	project    projects.ProjectQuerier
-	membership memberships.MembershipQuerier
-	user       users.UserQuerier
-	invite     invites.InviteQuerier
+	team         teams.TeamQuerier
+	project      projects.ProjectQuerier
+	membership   memberships.MembershipQuerier
+	user         users.UserQuerier
+	invite       invites.InviteQuerier
+	notification notifications.NotificationQuerier
 }
 
 // Create creates a new team for a project
@@ -289,10 +291,25 @@ func (t *Team) CreateInvite(w http.ResponseWriter, r *http.Request) error {
 			return fmt.Errorf("failed to send email: %w", err)
 		}
 
-		_, err = t.query.invite.Create(r.Context(), t.repo, ni, time.Now())
+		iv, err := t.query.invite.Create(r.Context(), t.repo, ni, time.Now())
 		if err != nil {
 			return err
 		}
+
+		actor := t.auth0.UserByID(r.Context())
+
+		nn := notifications.NewNotification{
+			UserID:     ni.UserID,
+			ActorID:    &actor,
+			EventID:    &iv.ID,
+			Type:       notifications.TeamInvite.String(),
+			Message:    "You've been invited to a team",
+			ResourceID: &tid,
+		}
+
+		if _, err = t.query.notification.Create(r.Context(), t.repo, nn, time.Now()); err != nil {
+			return fmt.Errorf("failed to create notification: %w", err)
+		}
 	}
 
 	return web.Respond(r.Context(), w, nil, http.StatusCreated)
@@ -13,6 +13,7 @@ import (
 	"github.com/devpies/devpie-client-core/users/domain/invites"
 	"github.com/devpies/devpie-client-core/users/domain/memberships"
 	mockQuery "github.com/devpies/devpie-client-core/users/domain/mocks"
+	"github.com/devpies/devpie-client-core/users/domain/notifications"
 	"github.com/devpies/devpie-client-core/users/domain/projects"
 	"github.com/devpies/devpie-client-core/users/domain/teams"
 	"github.com/devpies/devpie-client-core/users/domain/users"
@@ -44,6 +45,7 @@ func setupTeamMocks() *Team {
 			&mockQuery.MembershipQuerier{},
 			&mockQuery.UserQuerier{},
 			&mockQuery.InviteQuerier{},
+			&mockQuery.NotificationQuerier{},
 		},
 		publish: &mockPub.Publisher{},
 	}
@@ -1101,6 +1103,7 @@ func TestTeams_List_500_Uncaught_Error_On_Retrieve(t *testing.T) {
 }
 
 func TestTeam_CreateInvite_200_New_Users(t *testing.T) {
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
 	tm := team()
 	au := authUser()
 	nu := newUser()
@@ -1115,6 +1118,8 @@ func TestTeam_CreateInvite_200_New_Users(t *testing.T) {
 	fake.auth0.(*mockAuth.Auther).On("UpdateUserAppMetaData", auth0.Token{}, au.Auth0ID, u.ID).Return(nil).Twice()
 	fake.auth0.(*mockAuth.Auther).On("ChangePasswordTicket", auth0.Token{}, au, mock.AnythingOfType("string")).Return("link-to-change-password", nil).Twice()
 	fake.query.invite.(*mockQuery.InviteQuerier).On("Create", mock.AnythingOfType("*context.valueCtx"), fake.repo, mock.AnythingOfType("invites.NewInvite"), mock.AnythingOfType("time.Time")).Return(invites.Invite{}, nil).Twice()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid).Twice()
+	fake.query.notification.(*mockQuery.NotificationQuerier).On("Create", mock.AnythingOfType("*context.valueCtx"), fake.repo, mock.AnythingOfType("notifications.NewNotification"), mock.AnythingOfType("time.Time")).Return(notifications.Notification{}, nil).Twice()
 
 	// setup server
 	mux := chi.NewMux()
@@ -1137,10 +1142,12 @@ func TestTeam_CreateInvite_200_New_Users(t *testing.T) {
 		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
 		fake.query.user.(*mockQuery.UserQuerier).AssertExpectations(t)
 		fake.query.invite.(*mockQuery.InviteQuerier).AssertExpectations(t)
+		fake.query.notification.(*mockQuery.NotificationQuerier).AssertExpectations(t)
 	})
 }
 
 func TestTeam_CreateInvite_200_Existing_Users(t *testing.T) {
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
 	tm := team()
 	u := user()
 
@@ -1149,6 +1156,8 @@ func TestTeam_CreateInvite_200_Existing_Users(t *testing.T) {
 	fake.auth0.(*mockAuth.Auther).On("GenerateToken").Return(auth0.Token{}, nil).Once()
 	fake.query.user.(*mockQuery.UserQuerier).On("RetrieveByEmail", fake.repo, mock.AnythingOfType("string")).Return(u, nil).Twice()
 	fake.query.invite.(*mockQuery.InviteQuerier).On("Create", mock.AnythingOfType("*context.valueCtx"), fake.repo, mock.AnythingOfType("invites.NewInvite"), mock.AnythingOfType("time.Time")).Return(invites.Invite{}, nil).Twice()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid).Twice()
+	fake.query.notification.(*mockQuery.NotificationQuerier).On("Create", mock.AnythingOfType("*context.valueCtx"), fake.repo, mock.AnythingOfType("notifications.NewNotification"), mock.AnythingOfType("time.Time")).Return(notifications.Notification{}, nil).Twice()
 
 	// setup server
 	mux := chi.NewMux()
@@ -1171,6 +1180,7 @@ func TestTeam_CreateInvite_200_Existing_Users(t *testing.T) {
 		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
 		fake.query.user.(*mockQuery.UserQuerier).AssertExpectations(t)
 		fake.query.invite.(*mockQuery.InviteQuerier).AssertExpectations(t)
+		fake.query.notification.(*mockQuery.NotificationQuerier).AssertExpectations(t)
 	})
 }
 
@@ -1433,6 +1443,47 @@ func TestTeam_CreateInvite_500_Uncaught_Error_On_Invite_Create(t *testing.T) {
 	})
 }
 
+func TestTeam_CreateInvite_500_Uncaught_Error_On_Notification_Create(t *testing.T) {
+	cause := errors.New("something went wrong")
+
+	uid := "a4b54ec1-57f9-4c39-ab53-d936dbb6c177"
+	tm := team()
+	u := user()
+
+	//setup mocks
+	fake := setupTeamMocks()
+	fake.auth0.(*mockAuth.Auther).On("GenerateToken").Return(auth0.Token{}, nil).Once()
+	fake.query.user.(*mockQuery.UserQuerier).On("RetrieveByEmail", fake.repo, mock.AnythingOfType("string")).Return(u, nil).Once()
+	fake.query.invite.(*mockQuery.InviteQuerier).On("Create", mock.AnythingOfType("*context.valueCtx"), fake.repo, mock.AnythingOfType("invites.NewInvite"), mock.AnythingOfType("time.Time")).Return(invites.Invite{}, nil).Once()
+	fake.auth0.(*mockAuth.Auther).On("UserByID", mock.AnythingOfType("*context.valueCtx")).Return(uid).Once()
+	fake.query.notification.(*mockQuery.NotificationQuerier).On("Create", mock.AnythingOfType("*context.valueCtx"), fake.repo, mock.AnythingOfType("notifications.NewNotification"), mock.AnythingOfType("time.Time")).Return(notifications.Notification{}, cause).Once()
+
+	// setup server
+	mux := chi.NewMux()
+	mux.HandleFunc("/{tid}", func(w http.ResponseWriter, r *http.Request) {
+		err := fake.CreateInvite(w, r)
+
+		t.Run("Assert Handler Response", func(t *testing.T) {
+			assert.True(t, errors.Is(err, cause))
+			assert.Equal(t, fmt.Sprintf("failed to create notification: %s", cause), err.Error())
+		})
+	})
+
+	list := strings.NewReader(`{ "emailList": ["example@devpie.io"] }`)
+
+	// make request
+	writer := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/%s", tm.ID), list)
+	mux.ServeHTTP(writer, request)
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		fake.auth0.(*mockAuth.Auther).AssertExpectations(t)
+		fake.query.user.(*mockQuery.UserQuerier).AssertExpectations(t)
+		fake.query.invite.(*mockQuery.InviteQuerier).AssertExpectations(t)
+		fake.query.notification.(*mockQuery.NotificationQuerier).AssertExpectations(t)
+	})
+}
+
 func TestTeam_CreateInvite_500_Uncaught_Error_On_SendMail(t *testing.T) {
 	cause := errors.New("something went wrong")
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/devpies/devpie-client-core/users/domain/users"
@@ -86,3 +87,46 @@ func (u *User) Create(w http.ResponseWriter, r *http.Request) error {
 
 	return web.Respond(r.Context(), w, user, status)
 }
+
+// Roles remembers who holds the client role, so what only the team may see is kept from
+// clients in emails and notifications
+type Roles struct {
+	repo  database.Storer
+	auth0 auth0.Auther
+	query users.UserQuerier
+	seen  sync.Map
+}
+
+// recordedRole is what Record last wrote for a user
+type recordedRole struct {
+	client   bool
+	nickname string
+}
+
+// Record is middleware recording the role and nickname of authenticated users. They are only
+// written again when they change.
+func (rs *Roles) Record() web.Middleware {
+	return func(after web.Handler) web.Handler {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			uid := rs.auth0.UserByID(r.Context())
+			p := recordedRole{
+				client:   rs.auth0.HasRole(r.Context(), auth0.RoleClient),
+				nickname: rs.auth0.UserNickname(r.Context()),
+			}
+
+			if seen, ok := rs.seen.Load(uid); uid != "" && (!ok || seen.(recordedRole) != p) {
+				err := rs.query.RecordRole(r.Context(), rs.repo, uid, p.client, p.nickname, time.Now())
+				switch err {
+				case nil:
+					rs.seen.Store(uid, p)
+				case users.ErrNotFound:
+					// the user is being created and is recorded on a later request
+				default:
+					return fmt.Errorf("failed to record user role: %w", err)
+				}
+			}
+
+			return after(w, r)
+		}
+	}
+}
@@ -416,3 +416,63 @@ func TestUsers_Create_500_Uncaught_Error_On_UpdateUserAppMetaData(t *testing.T)
 		fake.query.user.(*mockQuery.UserQuerier).AssertExpectations(t)
 	})
 }
+
+func TestRoles_Record_Once(t *testing.T) {
+	// setup mocks
+	u := user()
+	a0 := &mockAuth.Auther{}
+	q := &mockQuery.UserQuerier{}
+	rs := &Roles{repo: th.Repo(), auth0: a0, query: q}
+	a0.On("UserByID", mock.Anything).Return(u.ID)
+	a0.On("HasRole", mock.Anything, auth0.RoleClient).Return(true)
+	a0.On("UserNickname", mock.Anything).Return("testuser")
+	q.On("RecordRole", mock.Anything, rs.repo, u.ID, true, "testuser", mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	calls := 0
+	h := rs.Record()(func(w http.ResponseWriter, r *http.Request) error {
+		calls++
+		return nil
+	})
+
+	// make requests
+	for i := 0; i < 2; i++ {
+		request, _ := http.NewRequest(http.MethodGet, "/", nil)
+		assert.Nil(t, h(httptest.NewRecorder(), request))
+	}
+
+	t.Run("Assert Handler Calls", func(t *testing.T) {
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		a0.AssertExpectations(t)
+		q.AssertExpectations(t)
+	})
+}
+
+func TestRoles_Record_Until_User_Exists(t *testing.T) {
+	// setup mocks
+	u := user()
+	a0 := &mockAuth.Auther{}
+	q := &mockQuery.UserQuerier{}
+	rs := &Roles{repo: th.Repo(), auth0: a0, query: q}
+	a0.On("UserByID", mock.Anything).Return(u.ID)
+	a0.On("HasRole", mock.Anything, auth0.RoleClient).Return(false)
+	a0.On("UserNickname", mock.Anything).Return("testuser")
+	q.On("RecordRole", mock.Anything, rs.repo, u.ID, false, "testuser", mock.AnythingOfType("time.Time")).Return(users.ErrNotFound).Twice()
+
+	h := rs.Record()(func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	})
+
+	// make requests
+	for i := 0; i < 2; i++ {
+		request, _ := http.NewRequest(http.MethodGet, "/", nil)
+		assert.Nil(t, h(httptest.NewRecorder(), request))
+	}
+
+	t.Run("Assert Mock Expectations", func(t *testing.T) {
+		a0.AssertExpectations(t)
+		q.AssertExpectations(t)
+	})
+}
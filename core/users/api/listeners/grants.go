@@ -0,0 +1,88 @@
+package listeners
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/devpies/devpie-client-core/users/domain/grants"
+	"github.com/devpies/devpie-client-events/go/events"
+	"github.com/nats-io/stan.go"
+)
+
+// GrantEvent mirrors the grant events published by the projects service
+type GrantEvent struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	Data     GrantEventData  `json:"data"`
+	Metadata events.Metadata `json:"metadata"`
+}
+
+// GrantEventData describes the grant. Only the ids are set when it was revoked.
+type GrantEventData struct {
+	GrantID    string `json:"grantId"`
+	ProjectID  string `json:"projectId"`
+	UserID     string `json:"userId"`
+	Role       string `json:"role"`
+	Expiration string `json:"expiration"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+// handleGrantCreated listens for a GrantCreated event
+func (l *Listener) handleGrantCreated(m *stan.Msg) {
+	var msg GrantEvent
+
+	if err := json.Unmarshal(m.Data, &msg); err != nil {
+		l.log.Printf("warning: failed to unmarshal Command \n %v", err)
+	}
+
+	event := msg.Data
+
+	createdtime, err := events.ParseTime(event.CreatedAt)
+	if err != nil {
+		l.log.Printf("failed to parse time")
+	}
+
+	g := grants.GrantCopy{
+		ID:        event.GrantID,
+		ProjectID: event.ProjectID,
+		UserID:    event.UserID,
+		Role:      event.Role,
+		CreatedAt: createdtime,
+	}
+
+	if event.Expiration != "" {
+		expiration, err := events.ParseTime(event.Expiration)
+		if err != nil {
+			l.log.Printf("failed to parse time")
+		} else {
+			g.Expiration = &expiration
+		}
+	}
+
+	if err = l.query.grant.Create(context.Background(), l.repo, g); err != nil {
+		l.log.Printf("failed to create grant: %s \n %v", event.GrantID, err)
+	}
+
+	err = m.Ack()
+	if err != nil {
+		l.log.Printf("failed to Acknowledge message \n %v", err)
+	}
+}
+
+// handleGrantDeleted listens for a GrantDeleted event
+func (l *Listener) handleGrantDeleted(m *stan.Msg) {
+	var msg GrantEvent
+
+	if err := json.Unmarshal(m.Data, &msg); err != nil {
+		l.log.Printf("warning: failed to unmarshal Command \n %v", err)
+	}
+
+	if err := l.query.grant.Delete(context.Background(), l.repo, msg.Data.GrantID); err != nil {
+		l.log.Printf("failed to delete grant: %s \n %v", msg.Data.GrantID, err)
+	}
+
+	err := m.Ack()
+	if err != nil {
+		l.log.Printf("failed to Acknowledge message \n %v", err)
+	}
+}
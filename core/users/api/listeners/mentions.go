@@ -0,0 +1,96 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devpies/devpie-client-core/users/domain/mentions"
+	"github.com/devpies/devpie-client-core/users/domain/notifications"
+)
+
+// recordMentions records the people mentioned in a task's content and comments. Only the
+// team can be mentioned in internal tasks and comments.
+func (l *Listener) recordMentions(msg TaskEvent) {
+	event := msg.Data
+
+	locations := map[string]string{"content": event.Content}
+	for _, c := range event.Comments {
+		locations[mentions.CommentLocation("comment", c)] = c
+	}
+	for _, c := range event.InternalComments {
+		locations[mentions.CommentLocation("internal", c)] = c
+	}
+
+	var cs []mentions.Candidate
+	var err error
+
+	for location, text := range locations {
+		handles := mentions.Parse(text)
+		if len(handles) == 0 {
+			continue
+		}
+
+		if cs == nil {
+			cs, err = l.query.mention.Candidates(context.Background(), l.repo, event.ProjectID)
+			if err != nil {
+				l.log.Printf("failed to retrieve project members: %s \n %v", event.ProjectID, err)
+				return
+			}
+		}
+
+		candidates := cs
+		if event.Internal || strings.HasPrefix(location, "internal:") {
+			candidates = mentions.Team(cs)
+		}
+
+		for _, handle := range handles {
+			l.recordMention(msg, location, handle, candidates)
+		}
+	}
+}
+
+// recordMention stores a mention and notifies the mentioned user the first time it is seen.
+// Handles that don't match anyone with access to the project are stored unresolved.
+func (l *Listener) recordMention(msg TaskEvent, location, handle string, cs []mentions.Candidate) {
+	event := msg.Data
+
+	nm := mentions.NewMention{
+		ProjectID: event.ProjectID,
+		TaskID:    event.TaskID,
+		Location:  location,
+		Handle:    handle,
+		ActorID:   &msg.Metadata.UserID,
+	}
+
+	uid, ok := mentions.Resolve(handle, cs)
+	if ok {
+		nm.UserID = &uid
+	}
+
+	_, created, err := l.query.mention.Create(context.Background(), l.repo, nm, time.Now())
+	if err != nil {
+		l.log.Printf("failed to create mention: %s \n %v", handle, err)
+		return
+	}
+
+	// users are not notified about their own actions
+	if !created || !ok || uid == msg.Metadata.UserID {
+		return
+	}
+
+	nn := notifications.NewNotification{
+		UserID:     uid,
+		ActorID:    &msg.Metadata.UserID,
+		EventID:    &msg.ID,
+		Type:       notifications.Mentioned.String(),
+		Message:    fmt.Sprintf("You were mentioned in %s %s", event.Key, event.Title),
+		ResourceID: &event.TaskID,
+		ProjectID:  &event.ProjectID,
+	}
+
+	if _, err := l.query.notification.Create(context.Background(), l.repo, nn, time.Now()); err != nil {
+		l.log.Printf("failed to create notification for mention: %s \n %v", handle, err)
+	}
+}
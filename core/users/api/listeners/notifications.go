@@ -0,0 +1,174 @@
+package listeners
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/devpies/devpie-client-core/users/domain/notifications"
+	"github.com/devpies/devpie-client-events/go/events"
+	"github.com/nats-io/stan.go"
+)
+
+// Subjects published by the projects service
+const (
+	EventsTaskCreated      = "TaskCreated"
+	EventsTaskUpdated      = "TaskUpdated"
+	EventsTaskMoved        = "TaskMoved"
+	EventsTaskCommented    = "TaskCommented"
+	EventsTaskAssigned     = "TaskAssigned"
+	EventsWatchersNotified = "WatchersNotified"
+	EventsGrantCreated     = "GrantCreated"
+	EventsGrantDeleted     = "GrantDeleted"
+)
+
+// TaskEvent mirrors the task events published by the projects service
+type TaskEvent struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	Data     TaskEventData   `json:"data"`
+	Metadata events.Metadata `json:"metadata"`
+}
+
+// TaskEventData describes the task after the change
+type TaskEventData struct {
+	TaskID           string   `json:"taskId"`
+	ProjectID        string   `json:"projectId"`
+	Key              string   `json:"key"`
+	Title            string   `json:"title"`
+	Content          string   `json:"content"`
+	Comments         []string `json:"comments"`
+	CommentsHTML     []string `json:"commentsHtml"`
+	Internal         bool     `json:"internal"`
+	InternalComments []string `json:"internalComments"`
+	AssignedTo       string   `json:"assignedTo"`
+	ColumnTitle      string   `json:"columnTitle"`
+}
+
+// WatchEvent mirrors the event published by the projects service when a watched item changes
+type WatchEvent struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	Data     WatchEventData  `json:"data"`
+	Metadata events.Metadata `json:"metadata"`
+}
+
+// WatchEventData describes what changed and who watches it
+type WatchEventData struct {
+	ProjectID  string   `json:"projectId"`
+	TaskID     string   `json:"taskId"`
+	Key        string   `json:"key"`
+	Title      string   `json:"title"`
+	Action     string   `json:"action"`
+	Recipients []string `json:"recipients"`
+}
+
+// handleTaskAssigned listens for a TaskAssigned event
+func (l *Listener) handleTaskAssigned(m *stan.Msg) {
+	var msg TaskEvent
+
+	if err := json.Unmarshal(m.Data, &msg); err != nil {
+		l.log.Printf("warning: failed to unmarshal Command \n %v", err)
+	}
+
+	event := msg.Data
+
+	// users are not notified about their own actions
+	if event.AssignedTo != "" && event.AssignedTo != msg.Metadata.UserID {
+		nn := notifications.NewNotification{
+			UserID:     event.AssignedTo,
+			ActorID:    &msg.Metadata.UserID,
+			EventID:    &msg.ID,
+			Type:       notifications.TaskAssigned.String(),
+			Message:    fmt.Sprintf("You've been assigned to %s %s", event.Key, event.Title),
+			ResourceID: &event.TaskID,
+			ProjectID:  &event.ProjectID,
+		}
+
+		if _, err := l.query.notification.Create(context.Background(), l.repo, nn, time.Now()); err != nil {
+			l.log.Printf("failed to create notification for task: %s \n %v", event.TaskID, err)
+		}
+	}
+
+	err := m.Ack()
+	if err != nil {
+		l.log.Printf("failed to Acknowledge message \n %v", err)
+	}
+}
+
+// handleMembershipUpdated listens for a MembershipUpdatedEvent
+func (l *Listener) handleMembershipUpdated(m *stan.Msg) {
+	msg, err := events.UnmarshalMembershipUpdatedEvent(m.Data)
+	if err != nil {
+		l.log.Printf("warning: failed to unmarshal Command \n %v", err)
+	}
+
+	event := msg.Data
+
+	mem, err := l.query.membership.RetrieveByID(context.Background(), l.repo, event.MembershipID)
+	if err != nil {
+		l.log.Printf("failed to retrieve membership: %s \n %v", event.MembershipID, err)
+	} else if mem.UserID != msg.Metadata.UserID {
+		nn := notifications.NewNotification{
+			UserID:     mem.UserID,
+			ActorID:    &msg.Metadata.UserID,
+			EventID:    &msg.ID,
+			Type:       notifications.RoleChanged.String(),
+			Message:    fmt.Sprintf("Your team role changed to %s", event.Role),
+			ResourceID: &mem.TeamID,
+		}
+
+		if _, err = l.query.notification.Create(context.Background(), l.repo, nn, time.Now()); err != nil {
+			l.log.Printf("failed to create notification for membership: %s \n %v", event.MembershipID, err)
+		}
+	}
+
+	err = m.Ack()
+	if err != nil {
+		l.log.Printf("failed to Acknowledge message \n %v", err)
+	}
+}
+
+// handleWatchersNotified listens for a WatchersNotified event
+func (l *Listener) handleWatchersNotified(m *stan.Msg) {
+	var msg WatchEvent
+
+	if err := json.Unmarshal(m.Data, &msg); err != nil {
+		l.log.Printf("warning: failed to unmarshal Command \n %v", err)
+	}
+
+	event := msg.Data
+
+	message := fmt.Sprintf("Project %s was %s", event.Title, event.Action)
+	resource := event.ProjectID
+	if event.TaskID != "" {
+		message = fmt.Sprintf("%s %s was %s", event.Key, event.Title, event.Action)
+		resource = event.TaskID
+	}
+
+	for _, uid := range event.Recipients {
+		if uid == msg.Metadata.UserID {
+			continue
+		}
+
+		nn := notifications.NewNotification{
+			UserID:     uid,
+			ActorID:    &msg.Metadata.UserID,
+			EventID:    &msg.ID,
+			Type:       notifications.WatchedChange.String(),
+			Message:    message,
+			ResourceID: &resource,
+			ProjectID:  &event.ProjectID,
+		}
+
+		if _, err := l.query.notification.Create(context.Background(), l.repo, nn, time.Now()); err != nil {
+			l.log.Printf("failed to create notification for user: %s \n %v", uid, err)
+		}
+	}
+
+	err := m.Ack()
+	if err != nil {
+		l.log.Printf("failed to Acknowledge message \n %v", err)
+	}
+}
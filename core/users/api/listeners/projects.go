@@ -96,6 +96,9 @@ func (l *Listener) handleProjectDeleted(m *stan.Msg) {
 	if err = l.query.project.Delete(context.Background(), l.repo, event.ProjectID); err != nil {
 		l.log.Printf("failed to delete project: %s \n %v", event.ProjectID, err)
 	}
+	if err = l.query.grant.DeleteAll(context.Background(), l.repo, event.ProjectID); err != nil {
+		l.log.Printf("failed to delete grants of project: %s \n %v", event.ProjectID, err)
+	}
 
 	err = m.Ack()
 	if err != nil {
@@ -6,6 +6,11 @@ import (
 	"log"
 	"time"
 
+	"github.com/devpies/devpie-client-core/users/domain/digests"
+	"github.com/devpies/devpie-client-core/users/domain/grants"
+	"github.com/devpies/devpie-client-core/users/domain/memberships"
+	"github.com/devpies/devpie-client-core/users/domain/mentions"
+	"github.com/devpies/devpie-client-core/users/domain/notifications"
 	"github.com/devpies/devpie-client-core/users/domain/projects"
 	"github.com/devpies/devpie-client-core/users/platform/database"
 	"github.com/devpies/devpie-client-events/go/events"
@@ -21,7 +26,12 @@ type Listener struct {
 
 // ListenerQueries defines queries required by subscription handlers
 type ListenerQueries struct {
-	project ProjectQuerier
+	project      ProjectQuerier
+	membership   MembershipQuerier
+	notification notifications.NotificationQuerier
+	mention      mentions.MentionQuerier
+	digest       digests.DigestQuerier
+	grant        grants.GrantQuerier
 }
 
 // ProjectQuerier describes behavior required for executing project related queries
@@ -31,13 +41,18 @@ type ProjectQuerier interface {
 	Delete(ctx context.Context, repo database.Storer, pid string) error
 }
 
+// MembershipQuerier describes behavior required for executing membership related queries
+type MembershipQuerier interface {
+	RetrieveByID(ctx context.Context, repo database.Storer, mid string) (memberships.Membership, error)
+}
+
 // NewListener creates a new Listener object
 func NewListener(log *log.Logger, repo *database.Repository) *Listener {
 	dur, err := time.ParseDuration("5s")
 	if err != nil {
 		log.Printf("warning: parse duration error: %v", err)
 	}
-	return &Listener{log, repo, dur, ListenerQueries{&projects.Queries{}}}
+	return &Listener{log, repo, dur, ListenerQueries{&projects.Queries{}, &memberships.Queries{}, &notifications.Queries{}, &mentions.Queries{}, &digests.Queries{}, &grants.Queries{}}}
 }
 
 // RegisterAll registers all subscription handlers defined in the implementation body
@@ -48,4 +63,22 @@ func (l *Listener) RegisterAll(nats *events.Client, queueGrp string) {
 		stan.SetManualAckMode(), stan.AckWait(l.dur), stan.DurableName(queueGrp))
 	nats.Listen(string(events.EventsProjectDeleted), queueGrp, l.handleProjectDeleted, stan.DeliverAllAvailable(),
 		stan.SetManualAckMode(), stan.AckWait(l.dur), stan.DurableName(queueGrp))
+	nats.Listen(EventsTaskCreated, queueGrp, l.handleTaskCreated, stan.DeliverAllAvailable(),
+		stan.SetManualAckMode(), stan.AckWait(l.dur), stan.DurableName(queueGrp))
+	nats.Listen(EventsTaskUpdated, queueGrp, l.handleTaskUpdated, stan.DeliverAllAvailable(),
+		stan.SetManualAckMode(), stan.AckWait(l.dur), stan.DurableName(queueGrp))
+	nats.Listen(EventsTaskMoved, queueGrp, l.handleTaskMoved, stan.DeliverAllAvailable(),
+		stan.SetManualAckMode(), stan.AckWait(l.dur), stan.DurableName(queueGrp))
+	nats.Listen(EventsTaskCommented, queueGrp, l.handleTaskCommented, stan.DeliverAllAvailable(),
+		stan.SetManualAckMode(), stan.AckWait(l.dur), stan.DurableName(queueGrp))
+	nats.Listen(EventsTaskAssigned, queueGrp, l.handleTaskAssigned, stan.DeliverAllAvailable(),
+		stan.SetManualAckMode(), stan.AckWait(l.dur), stan.DurableName(queueGrp))
+	nats.Listen(EventsWatchersNotified, queueGrp, l.handleWatchersNotified, stan.DeliverAllAvailable(),
+		stan.SetManualAckMode(), stan.AckWait(l.dur), stan.DurableName(queueGrp))
+	nats.Listen(string(events.EventsMembershipUpdated), queueGrp, l.handleMembershipUpdated, stan.DeliverAllAvailable(),
+		stan.SetManualAckMode(), stan.AckWait(l.dur), stan.DurableName(queueGrp))
+	nats.Listen(EventsGrantCreated, queueGrp, l.handleGrantCreated, stan.DeliverAllAvailable(),
+		stan.SetManualAckMode(), stan.AckWait(l.dur), stan.DurableName(queueGrp))
+	nats.Listen(EventsGrantDeleted, queueGrp, l.handleGrantDeleted, stan.DeliverAllAvailable(),
+		stan.SetManualAckMode(), stan.AckWait(l.dur), stan.DurableName(queueGrp))
 }
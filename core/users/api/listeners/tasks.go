@@ -0,0 +1,114 @@
+package listeners
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/devpies/devpie-client-core/users/domain/digests"
+	"github.com/nats-io/stan.go"
+)
+
+// doneColumn is the title of the board column holding completed tasks
+const doneColumn = "Done"
+
+// handleTaskCreated listens for a TaskCreated event
+func (l *Listener) handleTaskCreated(m *stan.Msg) {
+	var msg TaskEvent
+
+	if err := json.Unmarshal(m.Data, &msg); err != nil {
+		l.log.Printf("warning: failed to unmarshal Command \n %v", err)
+	}
+
+	l.recordActivity(msg, digests.TaskCreated, "", "")
+	l.recordMentions(msg)
+
+	err := m.Ack()
+	if err != nil {
+		l.log.Printf("failed to Acknowledge message \n %v", err)
+	}
+}
+
+// handleTaskUpdated listens for a TaskUpdated event
+func (l *Listener) handleTaskUpdated(m *stan.Msg) {
+	var msg TaskEvent
+
+	if err := json.Unmarshal(m.Data, &msg); err != nil {
+		l.log.Printf("warning: failed to unmarshal Command \n %v", err)
+	}
+
+	l.recordMentions(msg)
+
+	err := m.Ack()
+	if err != nil {
+		l.log.Printf("failed to Acknowledge message \n %v", err)
+	}
+}
+
+// handleTaskMoved listens for a TaskMoved event
+func (l *Listener) handleTaskMoved(m *stan.Msg) {
+	var msg TaskEvent
+
+	if err := json.Unmarshal(m.Data, &msg); err != nil {
+		l.log.Printf("warning: failed to unmarshal Command \n %v", err)
+	}
+
+	if strings.EqualFold(msg.Data.ColumnTitle, doneColumn) {
+		l.recordActivity(msg, digests.TaskCompleted, "", "")
+	} else {
+		l.recordActivity(msg, digests.TaskMoved, msg.Data.ColumnTitle, "")
+	}
+
+	err := m.Ack()
+	if err != nil {
+		l.log.Printf("failed to Acknowledge message \n %v", err)
+	}
+}
+
+// handleTaskCommented listens for a TaskCommented event
+func (l *Listener) handleTaskCommented(m *stan.Msg) {
+	var msg TaskEvent
+
+	if err := json.Unmarshal(m.Data, &msg); err != nil {
+		l.log.Printf("warning: failed to unmarshal Command \n %v", err)
+	}
+
+	var comment, commentHTML string
+	if n := len(msg.Data.Comments); n > 0 {
+		comment = msg.Data.Comments[n-1]
+	}
+	if n := len(msg.Data.CommentsHTML); n > 0 && n == len(msg.Data.Comments) {
+		commentHTML = msg.Data.CommentsHTML[n-1]
+	}
+
+	l.recordActivity(msg, digests.TaskCommented, comment, commentHTML)
+
+	err := m.Ack()
+	if err != nil {
+		l.log.Printf("failed to Acknowledge message \n %v", err)
+	}
+}
+
+// recordActivity stores task activity for digests. detailHTML is the detail rendered by the
+// projects service, when it is Markdown.
+func (l *Listener) recordActivity(msg TaskEvent, t digests.ActivityType, detail, detailHTML string) {
+	event := msg.Data
+
+	na := digests.NewActivity{
+		EventID:    msg.ID,
+		ProjectID:  event.ProjectID,
+		TaskID:     event.TaskID,
+		ActorID:    &msg.Metadata.UserID,
+		Type:       t.String(),
+		Key:        event.Key,
+		Title:      event.Title,
+		Detail:     detail,
+		DetailHTML: detailHTML,
+		Internal:   event.Internal,
+	}
+
+	if err := l.query.digest.RecordActivity(context.Background(), l.repo, na, time.Now()); err != nil {
+		l.log.Printf("failed to record activity for task: %s \n %v", event.TaskID, err)
+	}
+}
@@ -0,0 +1,218 @@
+package schedulers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/devpies/devpie-client-core/users/domain/digests"
+	"github.com/devpies/devpie-client-core/users/platform/database"
+	"github.com/devpies/devpie-client-core/users/platform/sendgrid"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+)
+
+// retention is how long activity is kept, long enough for a weekly digest that was delayed
+const retention = 8 * 24 * time.Hour
+
+// Scheduler defines scheduled jobs and their dependencies
+type Scheduler struct {
+	log    *log.Logger
+	repo   *database.Repository
+	sender sendgrid.Sender
+	hour   int
+	query  SchedulerQueries
+}
+
+// SchedulerQueries defines queries required by scheduled jobs
+type SchedulerQueries struct {
+	digest digests.DigestQuerier
+}
+
+// NewScheduler creates a new Scheduler object. Digests go out at the given hour of the day
+// in each subscriber's time zone.
+func NewScheduler(log *log.Logger, repo *database.Repository, sender sendgrid.Sender, hour int) *Scheduler {
+	return &Scheduler{log, repo, sender, hour, SchedulerQueries{&digests.Queries{}}}
+}
+
+// Start runs the scheduled jobs every interval until stop is closed. Every replica may run
+// it: each digest is claimed in the database before it is sent.
+func (s *Scheduler) Start(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			if err := s.SendDigests(context.Background(), now); err != nil {
+				s.log.Printf("failed to send digests \n %v", err)
+			}
+		}
+	}
+}
+
+// SendDigests emails every subscriber whose digest is due
+func (s *Scheduler) SendDigests(ctx context.Context, now time.Time) error {
+	rs, err := s.query.digest.Recipients(ctx, s.repo)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range rs {
+		loc, err := time.LoadLocation(r.TimeZone)
+		if err != nil {
+			loc = time.UTC
+		}
+
+		due := digests.PeriodStart(now, loc, r.Frequency, s.hour)
+		if r.LastSentAt != nil && !r.LastSentAt.Before(due) {
+			continue
+		}
+
+		claimed, err := s.query.digest.Claim(ctx, s.repo, r.UserID, due, now)
+		if err != nil {
+			s.log.Printf("failed to claim digest: %s \n %v", r.UserID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.sendDigest(ctx, r, loc, now); err != nil {
+			s.log.Printf("failed to send digest: %s \n %v", r.UserID, err)
+
+			if err := s.query.digest.Release(ctx, s.repo, r.UserID, r.LastSentAt); err != nil {
+				s.log.Printf("failed to release digest: %s \n %v", r.UserID, err)
+			}
+		}
+	}
+
+	return s.query.digest.PruneActivity(ctx, s.repo, now.Add(-retention))
+}
+
+func (s *Scheduler) sendDigest(ctx context.Context, r digests.Recipient, loc *time.Location, now time.Time) error {
+	since := now.Add(-digests.PeriodLength(r.Frequency))
+	if r.LastSentAt != nil && r.LastSentAt.After(since) {
+		since = *r.LastSentAt
+	}
+
+	as, err := s.query.digest.Activity(ctx, s.repo, r.UserID, since, now)
+	if err != nil {
+		return err
+	}
+	if len(as) == 0 {
+		return nil
+	}
+
+	subject, html, text, err := renderDigest(r, as, loc)
+	if err != nil {
+		return err
+	}
+
+	from := mail.NewEmail("DevPie", "people@devpie.io")
+	to := mail.NewEmail(name(r), r.Email)
+	message := mail.NewSingleEmail(from, subject, to, text, html)
+
+	if _, err := sendgrid.SendMail(message, s.sender); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// project groups the activity of a single project in a digest
+type project struct {
+	Name    string
+	Entries []entry
+}
+
+// entry is a line in a digest. DetailHTML is the sanitized HTML the projects service rendered
+// from a Markdown detail; Detail is shown as text when there is none.
+type entry struct {
+	Label      string
+	Key        string
+	Title      string
+	Detail     string
+	DetailHTML template.HTML
+	Time       string
+}
+
+var digestHTML = template.Must(template.New("digest").Parse(`<p>{{.Greeting}}</p>
+<p>{{.Intro}}</p>
+{{range .Projects}}<h3>{{.Name}}</h3>
+<ul>
+{{range .Entries}}<li><strong>{{.Label}}</strong> {{.Key}} {{.Title}}{{if .DetailHTML}}: {{.DetailHTML}}{{else if .Detail}}: {{.Detail}}{{end}} <small>{{.Time}}</small></li>
+{{end}}</ul>
+{{end}}<p><small>{{.Footer}}</small></p>`))
+
+func renderDigest(r digests.Recipient, as []digests.Activity, loc *time.Location) (string, string, string, error) {
+	p := phrasesFor(r.Locale)
+
+	subject := p.DailySubject
+	if r.Frequency == digests.Weekly.String() {
+		subject = p.WeeklySubject
+	}
+
+	labels := map[string]string{
+		digests.TaskCreated.String():   p.Created,
+		digests.TaskCompleted.String(): p.Completed,
+		digests.TaskMoved.String():     p.Moved,
+		digests.TaskCommented.String(): p.Commented,
+	}
+
+	var ps []project
+	for _, a := range as {
+		if len(ps) == 0 || ps[len(ps)-1].Name != a.ProjectName {
+			ps = append(ps, project{Name: a.ProjectName})
+		}
+		current := &ps[len(ps)-1]
+		current.Entries = append(current.Entries, entry{
+			Label:      labels[a.Type],
+			Key:        a.Key,
+			Title:      a.Title,
+			Detail:     a.Detail,
+			DetailHTML: template.HTML(a.DetailHTML),
+			Time:       a.CreatedAt.In(loc).Format("Jan 2 15:04"),
+		})
+	}
+
+	data := struct {
+		Greeting string
+		Intro    string
+		Footer   string
+		Projects []project
+	}{fmt.Sprintf(p.Greeting, name(r)), p.Intro, p.Footer, ps}
+
+	var html bytes.Buffer
+	if err := digestHTML.Execute(&html, data); err != nil {
+		return "", "", "", err
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "%s\n\n%s\n", data.Greeting, data.Intro)
+	for _, pr := range ps {
+		fmt.Fprintf(&text, "\n%s\n", pr.Name)
+		for _, e := range pr.Entries {
+			fmt.Fprintf(&text, "- %s %s %s", e.Label, e.Key, e.Title)
+			if e.Detail != "" {
+				fmt.Fprintf(&text, ": %s", e.Detail)
+			}
+			fmt.Fprintf(&text, " (%s)\n", e.Time)
+		}
+	}
+	fmt.Fprintf(&text, "\n%s\n", p.Footer)
+
+	return subject, html.String(), text.String(), nil
+}
+
+func name(r digests.Recipient) string {
+	if r.FirstName != nil && *r.FirstName != "" {
+		return *r.FirstName
+	}
+	return strings.Split(r.Email, "@")[0]
+}
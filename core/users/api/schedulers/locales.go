@@ -0,0 +1,74 @@
+package schedulers
+
+import "strings"
+
+// phrases holds the wording of a digest email in one language
+type phrases struct {
+	DailySubject  string
+	WeeklySubject string
+	Greeting      string
+	Intro         string
+	Created       string
+	Completed     string
+	Moved         string
+	Commented     string
+	Footer        string
+}
+
+var locales = map[string]phrases{
+	"en": {
+		DailySubject:  "Your daily DevPie digest",
+		WeeklySubject: "Your weekly DevPie digest",
+		Greeting:      "Hi %s,",
+		Intro:         "Here's what happened on your projects.",
+		Created:       "Created",
+		Completed:     "Completed",
+		Moved:         "Moved",
+		Commented:     "Commented",
+		Footer:        "You receive this email because you subscribed to DevPie digests.",
+	},
+	"es": {
+		DailySubject:  "Tu resumen diario de DevPie",
+		WeeklySubject: "Tu resumen semanal de DevPie",
+		Greeting:      "Hola %s,",
+		Intro:         "Esto es lo que pasó en tus proyectos.",
+		Created:       "Creada",
+		Completed:     "Completada",
+		Moved:         "Movida",
+		Commented:     "Comentada",
+		Footer:        "Recibes este correo porque te suscribiste a los resúmenes de DevPie.",
+	},
+	"fr": {
+		DailySubject:  "Votre résumé quotidien DevPie",
+		WeeklySubject: "Votre résumé hebdomadaire DevPie",
+		Greeting:      "Bonjour %s,",
+		Intro:         "Voici ce qui s'est passé sur vos projets.",
+		Created:       "Créée",
+		Completed:     "Terminée",
+		Moved:         "Déplacée",
+		Commented:     "Commentée",
+		Footer:        "Vous recevez cet e-mail car vous êtes abonné aux résumés DevPie.",
+	},
+	"de": {
+		DailySubject:  "Deine tägliche DevPie-Zusammenfassung",
+		WeeklySubject: "Deine wöchentliche DevPie-Zusammenfassung",
+		Greeting:      "Hallo %s,",
+		Intro:         "Das ist in deinen Projekten passiert.",
+		Created:       "Erstellt",
+		Completed:     "Erledigt",
+		Moved:         "Verschoben",
+		Commented:     "Kommentiert",
+		Footer:        "Du erhältst diese E-Mail, weil du DevPie-Zusammenfassungen abonniert hast.",
+	},
+}
+
+// phrasesFor returns the wording for a locale such as "de" or "es-MX", falling back to English
+func phrasesFor(locale *string) phrases {
+	if locale != nil {
+		lang := strings.ToLower(strings.SplitN(strings.Replace(*locale, "_", "-", 1), "-", 2)[0])
+		if p, ok := locales[lang]; ok {
+			return p
+		}
+	}
+	return locales["en"]
+}
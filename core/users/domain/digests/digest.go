@@ -0,0 +1,294 @@
+package digests
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/devpies/devpie-client-core/users/platform/database"
+	"github.com/google/uuid"
+)
+
+// Error codes returned by failures to handle digests.
+var (
+	ErrNotFound        = errors.New("digest subscription not found")
+	ErrInvalidID       = errors.New("id provided was not a valid UUID")
+	ErrInvalidTimeZone = errors.New("time zone provided was not recognized")
+)
+
+// activityLimit caps the number of entries in a single digest
+const activityLimit = 500
+
+// DigestQuerier describes behavior required for executing digest related queries
+type DigestQuerier interface {
+	Subscribe(ctx context.Context, repo database.Storer, uid string, ns NewSubscription, now time.Time) (Subscription, error)
+	Retrieve(ctx context.Context, repo database.Storer, uid string) (Subscription, error)
+	Unsubscribe(ctx context.Context, repo database.Storer, uid string) error
+	RecordActivity(ctx context.Context, repo database.Storer, na NewActivity, now time.Time) error
+	Recipients(ctx context.Context, repo database.Storer) ([]Recipient, error)
+	Claim(ctx context.Context, repo database.Storer, uid string, due, now time.Time) (bool, error)
+	Release(ctx context.Context, repo database.Storer, uid string, last *time.Time) error
+	Activity(ctx context.Context, repo database.Storer, uid string, since, until time.Time) ([]Activity, error)
+	PruneActivity(ctx context.Context, repo database.Storer, before time.Time) error
+}
+
+// Queries defines method implementations for interacting with the digest tables
+type Queries struct{}
+
+// Subscribe creates or changes a user's digest subscription
+func (q *Queries) Subscribe(ctx context.Context, repo database.Storer, uid string, ns NewSubscription, now time.Time) (Subscription, error) {
+	var s Subscription
+
+	if _, err := uuid.Parse(uid); err != nil {
+		return s, ErrInvalidID
+	}
+
+	s = Subscription{
+		UserID:    uid,
+		Frequency: ns.Frequency,
+		TimeZone:  ns.TimeZone,
+		UpdatedAt: now.UTC(),
+		CreatedAt: now.UTC(),
+	}
+
+	stmt := repo.Insert(
+		"digest_subscriptions",
+	).SetMap(map[string]interface{}{
+		"user_id":    s.UserID,
+		"frequency":  s.Frequency,
+		"time_zone":  s.TimeZone,
+		"updated_at": s.UpdatedAt,
+		"created_at": s.CreatedAt,
+	}).Suffix(
+		"ON CONFLICT (user_id) DO UPDATE SET frequency = EXCLUDED.frequency, time_zone = EXCLUDED.time_zone, " +
+			"updated_at = EXCLUDED.updated_at RETURNING last_sent_at, created_at",
+	)
+
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return s, fmt.Errorf("%w: arguments (%v)", err, args)
+	}
+
+	if err := repo.QueryRowxContext(ctx, query, args...).Scan(&s.LastSentAt, &s.CreatedAt); err != nil {
+		return s, err
+	}
+
+	return s, nil
+}
+
+// Retrieve retrieves the digest subscription of a user
+func (q *Queries) Retrieve(ctx context.Context, repo database.Storer, uid string) (Subscription, error) {
+	var s Subscription
+
+	if _, err := uuid.Parse(uid); err != nil {
+		return s, ErrInvalidID
+	}
+
+	stmt := repo.Select(
+		"user_id",
+		"frequency",
+		"time_zone",
+		"last_sent_at",
+		"updated_at",
+		"created_at",
+	).From(
+		"digest_subscriptions",
+	).Where(sq.Eq{"user_id": "?"})
+
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return s, fmt.Errorf("%w: arguments (%v)", err, args)
+	}
+
+	if err := repo.QueryRowxContext(ctx, query, uid).StructScan(&s); err != nil {
+		if err == sql.ErrNoRows {
+			return s, ErrNotFound
+		}
+		return s, err
+	}
+
+	return s, nil
+}
+
+// Unsubscribe stops a user's digests
+func (q *Queries) Unsubscribe(ctx context.Context, repo database.Storer, uid string) error {
+	if _, err := uuid.Parse(uid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"digest_subscriptions",
+	).Where(sq.Eq{"user_id": uid})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// RecordActivity stores activity for upcoming digests. Activity already recorded for the
+// same event is ignored.
+func (q *Queries) RecordActivity(ctx context.Context, repo database.Storer, na NewActivity, now time.Time) error {
+	stmt := repo.Insert(
+		"activities",
+	).SetMap(map[string]interface{}{
+		"activity_id": uuid.New().String(),
+		"event_id":    na.EventID,
+		"project_id":  na.ProjectID,
+		"task_id":     na.TaskID,
+		"actor_id":    na.ActorID,
+		"type":        na.Type,
+		"key":         na.Key,
+		"title":       na.Title,
+		"detail":      na.Detail,
+		"detail_html": na.DetailHTML,
+		"internal":    na.Internal,
+		"created_at":  now.UTC(),
+	}).Suffix("ON CONFLICT (event_id, type) DO NOTHING")
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Recipients retrieves every digest subscriber
+func (q *Queries) Recipients(ctx context.Context, repo database.Storer) ([]Recipient, error) {
+	var rs []Recipient
+
+	stmt := repo.Select(
+		"user_id",
+		"email",
+		"first_name",
+		"locale",
+		"frequency",
+		"time_zone",
+		"last_sent_at",
+	).From(
+		"digest_subscriptions",
+	).Join("users USING (user_id)")
+
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: arguments (%v)", err, args)
+	}
+
+	if err := repo.SelectContext(ctx, &rs, query); err != nil {
+		return nil, err
+	}
+
+	return rs, nil
+}
+
+// Claim marks a subscriber's digest as sent when it was last sent before due. Only one
+// caller succeeds for a given period, so replicas running the schedule side by side
+// don't send the same digest twice.
+func (q *Queries) Claim(ctx context.Context, repo database.Storer, uid string, due, now time.Time) (bool, error) {
+	stmt := repo.Update(
+		"digest_subscriptions",
+	).Set(
+		"last_sent_at", now.UTC(),
+	).Where(sq.Eq{"user_id": uid}).Where(sq.Or{sq.Eq{"last_sent_at": nil}, sq.Lt{"last_sent_at": due.UTC()}})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
+
+// Release gives a claimed digest back, so it is tried again on the next run
+func (q *Queries) Release(ctx context.Context, repo database.Storer, uid string, last *time.Time) error {
+	stmt := repo.Update(
+		"digest_subscriptions",
+	).Set(
+		"last_sent_at", last,
+	).Where(sq.Eq{"user_id": uid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Activity retrieves what happened between since and until on the projects a user can see:
+// the projects they own and those of the teams they belong to. Activity on internal tasks
+// is only included for users known not to be clients
+func (q *Queries) Activity(ctx context.Context, repo database.Storer, uid string, since, until time.Time) ([]Activity, error) {
+	var as = make([]Activity, 0)
+
+	if _, err := uuid.Parse(uid); err != nil {
+		return as, ErrInvalidID
+	}
+
+	stmt := repo.Select(
+		"a.activity_id",
+		"a.project_id",
+		"p.name AS project_name",
+		"a.task_id",
+		"a.actor_id",
+		"a.type",
+		"COALESCE(a.key, '') AS key",
+		"COALESCE(a.title, '') AS title",
+		"COALESCE(a.detail, '') AS detail",
+		"COALESCE(a.detail_html, '') AS detail_html",
+		"a.created_at",
+	).From(
+		"activities a",
+	).Join(
+		"projects p USING (project_id)",
+	).Where(
+		"(p.user_id = ? OR p.team_id IN (SELECT team_id FROM memberships WHERE user_id = ?))", uid, uid,
+	).Where(
+		"(NOT a.internal OR NOT COALESCE((SELECT client FROM users WHERE user_id = ?), TRUE))", uid,
+	).Where(
+		sq.GtOrEq{"a.created_at": since.UTC()},
+	).Where(
+		sq.Lt{"a.created_at": until.UTC()},
+	).OrderBy("p.name", "a.created_at").Limit(activityLimit)
+
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: arguments (%v)", err, args)
+	}
+
+	if err := repo.SelectContext(ctx, &as, query, args...); err != nil {
+		return nil, err
+	}
+
+	return as, nil
+}
+
+// PruneActivity removes activity no digest will include anymore
+func (q *Queries) PruneActivity(ctx context.Context, repo database.Storer, before time.Time) error {
+	stmt := repo.Delete(
+		"activities",
+	).Where(sq.Lt{"created_at": before.UTC()})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
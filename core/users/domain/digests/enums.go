@@ -0,0 +1,31 @@
+package digests
+
+// Frequency type for enumerated values
+type Frequency int
+
+// Frequencies
+const (
+	Daily Frequency = iota
+	Weekly
+)
+
+// String retrieves the corresponding string value for a frequency
+func (f Frequency) String() string {
+	return [...]string{"daily", "weekly"}[f]
+}
+
+// ActivityType type for enumerated values
+type ActivityType int
+
+// Activity types
+const (
+	TaskCreated ActivityType = iota
+	TaskCompleted
+	TaskMoved
+	TaskCommented
+)
+
+// String retrieves the corresponding string value for an activity type
+func (a ActivityType) String() string {
+	return [...]string{"created", "completed", "moved", "commented"}[a]
+}
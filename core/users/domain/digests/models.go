@@ -0,0 +1,60 @@
+package digests
+
+import "time"
+
+// Subscription represents a user's choice to receive activity digests by email
+type Subscription struct {
+	UserID     string     `db:"user_id" json:"userId"`
+	Frequency  string     `db:"frequency" json:"frequency"`
+	TimeZone   string     `db:"time_zone" json:"timeZone"`
+	LastSentAt *time.Time `db:"last_sent_at" json:"lastSentAt"`
+	UpdatedAt  time.Time  `db:"updated_at" json:"updatedAt"`
+	CreatedAt  time.Time  `db:"created_at" json:"createdAt"`
+}
+
+// NewSubscription represents a request to receive activity digests
+type NewSubscription struct {
+	Frequency string `json:"frequency" validate:"required,oneof=daily weekly"`
+	TimeZone  string `json:"timeZone" validate:"required"`
+}
+
+// Recipient represents a subscriber with the details needed to write them an email
+type Recipient struct {
+	UserID     string     `db:"user_id"`
+	Email      string     `db:"email"`
+	FirstName  *string    `db:"first_name"`
+	Locale     *string    `db:"locale"`
+	Frequency  string     `db:"frequency"`
+	TimeZone   string     `db:"time_zone"`
+	LastSentAt *time.Time `db:"last_sent_at"`
+}
+
+// Activity represents something that happened on a task, recorded for digests
+type Activity struct {
+	ID          string    `db:"activity_id" json:"id"`
+	ProjectID   string    `db:"project_id" json:"projectId"`
+	ProjectName string    `db:"project_name" json:"projectName"`
+	TaskID      string    `db:"task_id" json:"taskId"`
+	ActorID     *string   `db:"actor_id" json:"actorId"`
+	Type        string    `db:"type" json:"type"`
+	Key         string    `db:"key" json:"key"`
+	Title       string    `db:"title" json:"title"`
+	Detail      string    `db:"detail" json:"detail"`
+	DetailHTML  string    `db:"detail_html" json:"detailHtml"`
+	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
+}
+
+// NewActivity represents activity to record. EventID makes redelivered events harmless.
+// Activity on internal tasks is left out of the digests of clients.
+type NewActivity struct {
+	EventID    string  `json:"eventId" validate:"required"`
+	ProjectID  string  `json:"projectId" validate:"required"`
+	TaskID     string  `json:"taskId" validate:"required"`
+	ActorID    *string `json:"actorId"`
+	Type       string  `json:"type" validate:"required"`
+	Key        string  `json:"key"`
+	Title      string  `json:"title"`
+	Detail     string  `json:"detail"`
+	DetailHTML string  `json:"detailHtml"`
+	Internal   bool    `json:"internal"`
+}
@@ -0,0 +1,29 @@
+package digests
+
+import "time"
+
+// PeriodStart returns the most recent moment a digest was scheduled at or before now:
+// the given hour of the day in the subscriber's time zone, on Mondays for weekly digests.
+func PeriodStart(now time.Time, loc *time.Location, frequency string, hour int) time.Time {
+	local := now.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), hour, 0, 0, 0, loc)
+
+	if start.After(local) {
+		start = start.AddDate(0, 0, -1)
+	}
+
+	if frequency == Weekly.String() {
+		offset := (int(start.Weekday()) - int(time.Monday) + 7) % 7
+		start = start.AddDate(0, 0, -offset)
+	}
+
+	return start.UTC()
+}
+
+// PeriodLength returns how far back a first digest looks
+func PeriodLength(frequency string) time.Duration {
+	if frequency == Weekly.String() {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
@@ -0,0 +1,84 @@
+package grants
+
+import (
+	"context"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/devpies/devpie-client-core/users/platform/database"
+	"github.com/google/uuid"
+)
+
+// Error codes returned by failures to handle grants.
+var (
+	ErrInvalidID = errors.New("id provided was not a valid UUID")
+)
+
+// GrantQuerier describes behavior required for executing grant related queries
+type GrantQuerier interface {
+	Create(ctx context.Context, repo database.Storer, g GrantCopy) error
+	Delete(ctx context.Context, repo database.Storer, gid string) error
+	DeleteAll(ctx context.Context, repo database.Storer, pid string) error
+}
+
+// Queries defines method implementations for interacting with the grants table
+type Queries struct{}
+
+// Create inserts a grant into the database. A grant given again to the same user replaces
+// the role and expiration of the copy.
+func (q *Queries) Create(ctx context.Context, repo database.Storer, g GrantCopy) error {
+	if _, err := uuid.Parse(g.ID); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Insert(
+		"grants",
+	).SetMap(map[string]interface{}{
+		"grant_id":   g.ID,
+		"project_id": g.ProjectID,
+		"user_id":    g.UserID,
+		"role":       g.Role,
+		"expiration": g.Expiration,
+		"created_at": g.CreatedAt,
+	}).Suffix("ON CONFLICT (grant_id) DO UPDATE SET role = EXCLUDED.role, expiration = EXCLUDED.expiration")
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes a grant from the database
+func (q *Queries) Delete(ctx context.Context, repo database.Storer, gid string) error {
+	if _, err := uuid.Parse(gid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"grants",
+	).Where(sq.Eq{"grant_id": gid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAll removes every grant of a project from the database
+func (q *Queries) DeleteAll(ctx context.Context, repo database.Storer, pid string) error {
+	if _, err := uuid.Parse(pid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Delete(
+		"grants",
+	).Where(sq.Eq{"project_id": pid})
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package grants
+
+import (
+	"time"
+)
+
+// GrantCopy represents a project grant copied from the projects service. A grant gives a
+// user access to a single project without a team membership.
+type GrantCopy struct {
+	ID         string     `db:"grant_id" json:"grantId"`
+	ProjectID  string     `db:"project_id" json:"projectId"`
+	UserID     string     `db:"user_id" json:"userId"`
+	Role       string     `db:"role" json:"role"`
+	Expiration *time.Time `db:"expiration" json:"expiration"`
+	CreatedAt  time.Time  `db:"created_at" json:"createdAt"`
+}
@@ -135,6 +135,40 @@ func (q *Queries) RetrieveMembership(ctx context.Context, repo database.Storer,
 	return m, nil
 }
 
+// RetrieveByID retrieves a single membership by its id
+func (q *Queries) RetrieveByID(ctx context.Context, repo database.Storer, mid string) (Membership, error) {
+	var m Membership
+
+	if _, err := uuid.Parse(mid); err != nil {
+		return m, ErrInvalidID
+	}
+
+	stmt := repo.Select(
+		"membership_id",
+		"user_id",
+		"team_id",
+		"role",
+		"updated_at",
+		"created_at",
+	).From(
+		"memberships",
+	).Where(sq.Eq{"membership_id": "?"})
+
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return m, fmt.Errorf("%w: arguments (%v)", err, args)
+	}
+	err = repo.QueryRowxContext(ctx, query, mid).StructScan(&m)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return m, ErrNotFound
+		}
+		return m, err
+	}
+
+	return m, nil
+}
+
 // Update modifies a membership in the database
 func (q *Queries) Update(ctx context.Context, repo database.Storer, tid string, update UpdateMembership, uid string, now time.Time) error {
 	m, err := q.RetrieveMembership(ctx, repo, tid, uid)
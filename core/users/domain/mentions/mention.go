@@ -0,0 +1,166 @@
+package mentions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/devpies/devpie-client-core/users/platform/database"
+	"github.com/google/uuid"
+)
+
+// Error codes returned by failures to handle mentions.
+var (
+	ErrInvalidID = errors.New("id provided was not a valid UUID")
+)
+
+// listLimit caps the number of mentions returned at once
+const listLimit = 100
+
+// MentionQuerier describes behavior required for executing mention related queries
+type MentionQuerier interface {
+	Create(ctx context.Context, repo database.Storer, nm NewMention, now time.Time) (Mention, bool, error)
+	List(ctx context.Context, repo database.Storer, uid string) ([]Mention, error)
+	ListFlagged(ctx context.Context, repo database.Storer, uid string) ([]Mention, error)
+	Candidates(ctx context.Context, repo database.Storer, pid string) ([]Candidate, error)
+}
+
+// Queries defines method implementations for interacting with the mentions table
+type Queries struct{}
+
+// Create inserts a new mention into the database. It reports false when the same handle
+// was already mentioned at the same location, so an edit doesn't notify anyone twice.
+// Mentions in comments recorded when comments were keyed by position count as seen once.
+func (q *Queries) Create(ctx context.Context, repo database.Storer, nm NewMention, now time.Time) (Mention, bool, error) {
+	m := Mention{
+		ID:        uuid.New().String(),
+		ProjectID: nm.ProjectID,
+		TaskID:    nm.TaskID,
+		Location:  nm.Location,
+		Handle:    nm.Handle,
+		UserID:    nm.UserID,
+		ActorID:   nm.ActorID,
+		Resolved:  nm.UserID != nil,
+		CreatedAt: now.UTC(),
+	}
+
+	stmt := repo.Insert(
+		"mentions",
+	).SetMap(map[string]interface{}{
+		"mention_id": m.ID,
+		"project_id": m.ProjectID,
+		"task_id":    m.TaskID,
+		"location":   m.Location,
+		"handle":     m.Handle,
+		"user_id":    m.UserID,
+		"actor_id":   m.ActorID,
+		"resolved":   m.Resolved,
+		"created_at": m.CreatedAt,
+	}).Suffix("ON CONFLICT (task_id, location, handle) DO NOTHING RETURNING mention_id")
+
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return m, false, fmt.Errorf("%w: arguments (%v)", err, args)
+	}
+
+	if err := repo.QueryRowxContext(ctx, query, args...).Scan(&m.ID); err != nil {
+		if err == sql.ErrNoRows {
+			return m, false, nil
+		}
+		return m, false, err
+	}
+
+	if strings.HasPrefix(m.Location, "comment:") {
+		stmt := repo.Delete(
+			"mentions",
+		).Where(`mention_id = (
+			SELECT mention_id FROM mentions
+			WHERE task_id = ? AND handle = ? AND location LIKE 'legacy:comment:%' LIMIT 1
+		)`, m.TaskID, m.Handle)
+
+		res, err := stmt.ExecContext(ctx)
+		if err != nil {
+			return m, false, err
+		}
+		if n, err := res.RowsAffected(); err == nil && n > 0 {
+			return m, false, nil
+		}
+	}
+
+	return m, true, nil
+}
+
+// List retrieves the most recent mentions of a user
+func (q *Queries) List(ctx context.Context, repo database.Storer, uid string) ([]Mention, error) {
+	return q.list(ctx, repo, uid, sq.Eq{"user_id": uid})
+}
+
+// ListFlagged retrieves the mentions written by a user that could not be resolved
+func (q *Queries) ListFlagged(ctx context.Context, repo database.Storer, uid string) ([]Mention, error) {
+	return q.list(ctx, repo, uid, sq.Eq{"actor_id": uid, "resolved": false})
+}
+
+func (q *Queries) list(ctx context.Context, repo database.Storer, uid string, where sq.Sqlizer) ([]Mention, error) {
+	var ms = make([]Mention, 0)
+
+	if _, err := uuid.Parse(uid); err != nil {
+		return ms, ErrInvalidID
+	}
+
+	stmt := repo.Select(
+		"mention_id",
+		"project_id",
+		"task_id",
+		"location",
+		"handle",
+		"user_id",
+		"actor_id",
+		"resolved",
+		"created_at",
+	).From(
+		"mentions",
+	).Where(where).OrderBy("created_at DESC").Limit(listLimit)
+
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: arguments (%v)", err, args)
+	}
+
+	if err := repo.SelectContext(ctx, &ms, query, args...); err != nil {
+		return nil, err
+	}
+
+	return ms, nil
+}
+
+// Candidates retrieves the users who can be mentioned in a project: its owner, the members
+// of its team and the holders of unexpired grants, the same people the projects service
+// links mentions to
+func (q *Queries) Candidates(ctx context.Context, repo database.Storer, pid string) ([]Candidate, error) {
+	var cs []Candidate
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return cs, ErrInvalidID
+	}
+
+	query := `
+	SELECT user_id, email, nickname, client FROM users
+	WHERE user_id IN (
+		SELECT user_id FROM projects WHERE project_id = $1
+		UNION
+		SELECT m.user_id FROM memberships m JOIN projects p ON m.team_id = p.team_id WHERE p.project_id = $1
+		UNION
+		SELECT user_id FROM grants WHERE project_id = $1
+		AND (expiration IS NULL OR expiration > (NOW() AT TIME ZONE 'utc'))
+	)`
+
+	if err := repo.SelectContext(ctx, &cs, query, pid); err != nil {
+		return nil, err
+	}
+
+	return cs, nil
+}
@@ -0,0 +1,36 @@
+package mentions
+
+import "time"
+
+// Mention represents an @handle or @email found in a task's content or comments.
+// A mention that could not be matched to someone with access to the project is
+// stored unresolved so its author can be told about it.
+type Mention struct {
+	ID        string    `db:"mention_id" json:"id"`
+	ProjectID string    `db:"project_id" json:"projectId"`
+	TaskID    string    `db:"task_id" json:"taskId"`
+	Location  string    `db:"location" json:"location"`
+	Handle    string    `db:"handle" json:"handle"`
+	UserID    *string   `db:"user_id" json:"userId"`
+	ActorID   *string   `db:"actor_id" json:"actorId"`
+	Resolved  bool      `db:"resolved" json:"resolved"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+// NewMention represents a mention to store
+type NewMention struct {
+	ProjectID string  `json:"projectId" validate:"required"`
+	TaskID    string  `json:"taskId" validate:"required"`
+	Location  string  `json:"location" validate:"required"`
+	Handle    string  `json:"handle" validate:"required"`
+	UserID    *string `json:"userId"`
+	ActorID   *string `json:"actorId"`
+}
+
+// Candidate represents a user with access to a project who can be mentioned
+type Candidate struct {
+	UserID   string  `db:"user_id"`
+	Email    string  `db:"email"`
+	Nickname *string `db:"nickname"`
+	Client   *bool   `db:"client"`
+}
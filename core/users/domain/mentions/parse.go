@@ -0,0 +1,77 @@
+package mentions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// pattern matches @handle and @email when the @ starts a word, so plain email
+// addresses in the text are not taken for mentions
+var pattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.+-]+(?:@[\w-]+(?:\.[\w-]+)+)?)`)
+
+// Parse returns the distinct handles mentioned in a text, without the leading @
+func Parse(text string) []string {
+	var handles []string
+	seen := make(map[string]bool)
+
+	for _, m := range pattern.FindAllStringSubmatch(text, -1) {
+		h := strings.TrimRight(m[1], ".")
+		if h == "" || seen[strings.ToLower(h)] {
+			continue
+		}
+		seen[strings.ToLower(h)] = true
+		handles = append(handles, h)
+	}
+
+	return handles
+}
+
+// Resolve matches a handle against the users who have access to a project. A handle
+// matches a full email address, the part of an email before the @, or a nickname, as the
+// projects service matches it when linking mentions. It returns false when nobody or more
+// than one person matches.
+func Resolve(handle string, cs []Candidate) (string, bool) {
+	var match string
+	h := strings.ToLower(handle)
+
+	for _, c := range cs {
+		email := strings.ToLower(c.Email)
+		local := strings.SplitN(email, "@", 2)[0]
+
+		ok := h == email || h == local
+		if !ok && c.Nickname != nil {
+			ok = h == strings.ToLower(*c.Nickname)
+		}
+		if !ok {
+			continue
+		}
+		if match != "" && match != c.UserID {
+			return "", false
+		}
+		match = c.UserID
+	}
+
+	return match, match != ""
+}
+
+// Team keeps the candidates known not to hold the client role. Users whose role was never
+// seen are left out too.
+func Team(cs []Candidate) []Candidate {
+	team := make([]Candidate, 0, len(cs))
+	for _, c := range cs {
+		if c.Client != nil && !*c.Client {
+			team = append(team, c)
+		}
+	}
+	return team
+}
+
+// CommentLocation keys a comment by its content rather than its position, which shifts
+// when an earlier comment is deleted. Task events don't name the authors of comments, so
+// the same text posted twice on a task counts as one comment.
+func CommentLocation(prefix, text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return prefix + ":" + hex.EncodeToString(sum[:8])
+}
@@ -101,3 +101,17 @@ func (_m *UserQuerier) RetrieveMeByAuthID(ctx context.Context, repo database.Sto
 
 	return r0, r1
 }
+
+// RecordRole provides a mock function with given fields: ctx, repo, uid, client, nickname, now
+func (_m *UserQuerier) RecordRole(ctx context.Context, repo database.Storer, uid string, client bool, nickname string, now time.Time) error {
+	ret := _m.Called(ctx, repo, uid, client, nickname, now)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.Storer, string, bool, string, time.Time) error); ok {
+		r0 = rf(ctx, repo, uid, client, nickname, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
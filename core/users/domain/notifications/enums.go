@@ -0,0 +1,18 @@
+package notifications
+
+// Type type for enumerated values
+type Type int
+
+// Types
+const (
+	TaskAssigned Type = iota
+	Mentioned
+	TeamInvite
+	RoleChanged
+	WatchedChange
+)
+
+// String retrieves the corresponding string value for a notification type
+func (t Type) String() string {
+	return [...]string{"task_assigned", "mentioned", "team_invite", "role_changed", "watched_change"}[t]
+}
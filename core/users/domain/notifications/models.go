@@ -0,0 +1,34 @@
+package notifications
+
+import "time"
+
+// Notification represents an entry in a user's inbox
+type Notification struct {
+	ID         string    `db:"notification_id" json:"id"`
+	UserID     string    `db:"user_id" json:"userId"`
+	ActorID    *string   `db:"actor_id" json:"actorId"`
+	Type       string    `db:"type" json:"type"`
+	Message    string    `db:"message" json:"message"`
+	ResourceID *string   `db:"resource_id" json:"resourceId"`
+	ProjectID  *string   `db:"project_id" json:"projectId"`
+	Read       bool      `db:"read" json:"read"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updatedAt"`
+	CreatedAt  time.Time `db:"created_at" json:"createdAt"`
+}
+
+// NewNotification represents a notification to deliver. EventID is set when the
+// notification originates from an event so redelivered events are not duplicated.
+type NewNotification struct {
+	UserID     string  `json:"userId" validate:"required"`
+	ActorID    *string `json:"actorId"`
+	EventID    *string `json:"eventId"`
+	Type       string  `json:"type" validate:"required"`
+	Message    string  `json:"message" validate:"required"`
+	ResourceID *string `json:"resourceId"`
+	ProjectID  *string `json:"projectId"`
+}
+
+// UnreadCount represents the number of unread notifications of a user
+type UnreadCount struct {
+	Count int `json:"count"`
+}
@@ -0,0 +1,197 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/devpies/devpie-client-core/users/platform/database"
+	"github.com/google/uuid"
+)
+
+// Error codes returned by failures to handle notifications.
+var (
+	ErrNotFound  = errors.New("notification not found")
+	ErrInvalidID = errors.New("id provided was not a valid UUID")
+)
+
+// listLimit caps the number of notifications returned at once
+const listLimit = 100
+
+// NotificationQuerier describes behavior required for executing notification related queries
+type NotificationQuerier interface {
+	Create(ctx context.Context, repo database.Storer, nn NewNotification, now time.Time) (Notification, error)
+	List(ctx context.Context, repo database.Storer, uid string, unread bool) ([]Notification, error)
+	CountUnread(ctx context.Context, repo database.Storer, uid string) (int, error)
+	MarkRead(ctx context.Context, repo database.Storer, uid, nid string, now time.Time) error
+	MarkAllRead(ctx context.Context, repo database.Storer, uid string, now time.Time) error
+}
+
+// Queries defines method implementations for interacting with the notifications table
+type Queries struct{}
+
+// Create inserts a new notification into the database. A notification for an event
+// the user was already notified about is ignored.
+func (q *Queries) Create(ctx context.Context, repo database.Storer, nn NewNotification, now time.Time) (Notification, error) {
+	var n Notification
+
+	if _, err := uuid.Parse(nn.UserID); err != nil {
+		return n, ErrInvalidID
+	}
+
+	n = Notification{
+		ID:         uuid.New().String(),
+		UserID:     nn.UserID,
+		ActorID:    nn.ActorID,
+		Type:       nn.Type,
+		Message:    nn.Message,
+		ResourceID: nn.ResourceID,
+		ProjectID:  nn.ProjectID,
+		Read:       false,
+		UpdatedAt:  now.UTC(),
+		CreatedAt:  now.UTC(),
+	}
+
+	stmt := repo.Insert(
+		"notifications",
+	).SetMap(map[string]interface{}{
+		"notification_id": n.ID,
+		"user_id":         n.UserID,
+		"actor_id":        n.ActorID,
+		"event_id":        nn.EventID,
+		"type":            n.Type,
+		"message":         n.Message,
+		"resource_id":     n.ResourceID,
+		"project_id":      n.ProjectID,
+		"read":            n.Read,
+		"updated_at":      n.UpdatedAt,
+		"created_at":      n.CreatedAt,
+	}).Suffix("ON CONFLICT (user_id, event_id) DO NOTHING")
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return n, err
+	}
+
+	return n, nil
+}
+
+// List retrieves the most recent notifications of a user, optionally only unread ones
+func (q *Queries) List(ctx context.Context, repo database.Storer, uid string, unread bool) ([]Notification, error) {
+	var ns = make([]Notification, 0)
+
+	if _, err := uuid.Parse(uid); err != nil {
+		return ns, ErrInvalidID
+	}
+
+	stmt := repo.Select(
+		"notification_id",
+		"user_id",
+		"actor_id",
+		"type",
+		"message",
+		"resource_id",
+		"project_id",
+		"read",
+		"updated_at",
+		"created_at",
+	).From(
+		"notifications",
+	).Where(sq.Eq{"user_id": "?"})
+
+	if unread {
+		stmt = stmt.Where("read = FALSE")
+	}
+
+	stmt = stmt.OrderBy("created_at DESC").Limit(listLimit)
+
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: arguments (%v)", err, args)
+	}
+
+	if err := repo.SelectContext(ctx, &ns, query, uid); err != nil {
+		return nil, err
+	}
+
+	return ns, nil
+}
+
+// CountUnread returns the number of unread notifications of a user
+func (q *Queries) CountUnread(ctx context.Context, repo database.Storer, uid string) (int, error) {
+	var count int
+
+	if _, err := uuid.Parse(uid); err != nil {
+		return count, ErrInvalidID
+	}
+
+	stmt := repo.Select(
+		"COUNT(*)",
+	).From(
+		"notifications",
+	).Where(sq.Eq{"user_id": "?"}).Where("read = FALSE")
+
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return count, fmt.Errorf("%w: arguments (%v)", err, args)
+	}
+
+	if err := repo.GetContext(ctx, &count, query, uid); err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+// MarkRead marks a single notification of a user as read
+func (q *Queries) MarkRead(ctx context.Context, repo database.Storer, uid, nid string, now time.Time) error {
+	if _, err := uuid.Parse(uid); err != nil {
+		return ErrInvalidID
+	}
+	if _, err := uuid.Parse(nid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Update(
+		"notifications",
+	).SetMap(map[string]interface{}{
+		"read":       true,
+		"updated_at": now.UTC(),
+	}).Where(sq.Eq{"user_id": uid, "notification_id": nid})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// MarkAllRead marks every unread notification of a user as read
+func (q *Queries) MarkAllRead(ctx context.Context, repo database.Storer, uid string, now time.Time) error {
+	if _, err := uuid.Parse(uid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Update(
+		"notifications",
+	).SetMap(map[string]interface{}{
+		"read":       true,
+		"updated_at": now.UTC(),
+	}).Where(sq.Eq{"user_id": uid}).Where("read = FALSE")
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -26,6 +26,7 @@ type UserQuerier interface {
 	RetrieveByEmail(repo database.Storer, email string) (User, error)
 	RetrieveMe(ctx context.Context, repo database.Storer, uid string) (User, error)
 	RetrieveMeByAuthID(ctx context.Context, repo database.Storer, aid string) (User, error)
+	RecordRole(ctx context.Context, repo database.Storer, uid string, client bool, nickname string, now time.Time) error
 }
 
 // Queries defines method implementations for interacting with the users table
@@ -176,3 +177,31 @@ func (q *Queries) RetrieveMeByAuthID(ctx context.Context, repo database.Storer,
 
 	return u, nil
 }
+
+// RecordRole remembers whether a user holds the client role and their nickname, as last seen
+// in their access token, so what only the team may see is kept from clients in emails and
+// notifications, and mentions match the nicknames the projects service matches.
+// It reports ErrNotFound until the user is created
+func (q *Queries) RecordRole(ctx context.Context, repo database.Storer, uid string, client bool, nickname string, now time.Time) error {
+	if _, err := uuid.Parse(uid); err != nil {
+		return ErrInvalidID
+	}
+
+	stmt := repo.Update(
+		"users",
+	).SetMap(map[string]interface{}{
+		"client":     client,
+		"nickname":   nickname,
+		"updated_at": now.UTC(),
+	}).Where(sq.Eq{"user_id": uid})
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -12,13 +12,16 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Embed time zones for digest schedules
 
 	"github.com/ardanlabs/conf"
 	"github.com/devpies/devpie-client-core/users/api/handlers"
 	"github.com/devpies/devpie-client-core/users/api/listeners"
+	"github.com/devpies/devpie-client-core/users/api/schedulers"
 	"github.com/devpies/devpie-client-core/users/platform/database"
 	"github.com/devpies/devpie-client-events/go/events"
 	"github.com/pkg/errors"
+	"github.com/sendgrid/sendgrid-go"
 )
 
 func main() {
@@ -60,6 +63,10 @@ func run() error {
 			ClientID  string `conf:"default:client-id"`
 			ClusterID string `conf:"default:cluster-id"`
 		}
+		Digest struct {
+			Hour     int           `conf:"default:8"`
+			Interval time.Duration `conf:"default:5m"`
+		}
 	}
 
 	if err := conf.Parse(os.Args[1:], "API", &cfg); err != nil {
@@ -135,6 +142,15 @@ func run() error {
 		l.RegisterAll(nats, queueGroup)
 	}(repo, nats, infolog, queueGroup)
 
+	// =========================================================================
+	// Start Scheduler
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	s := schedulers.NewScheduler(infolog, repo, sendgrid.NewSendClient(cfg.Web.SendgridAPIKey).Send, cfg.Digest.Hour)
+	go s.Start(cfg.Digest.Interval, stop)
+
 	// =========================================================================
 	// Start API Service
 
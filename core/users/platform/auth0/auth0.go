@@ -35,6 +35,8 @@ type Auther interface {
 	PemCert(token *jwt.Token) (string, error)
 	UserByID(r context.Context) string
 	UserBySubject(ctx context.Context) string
+	HasRole(ctx context.Context, role string) bool
+	UserNickname(ctx context.Context) string
 	GenerateToken() (Token, error)
 	ConnectionID(token Token) (string, error)
 	CheckScope(scope, tokenString string) (bool, error)
@@ -226,6 +228,28 @@ func (a0 *Auth0) UserByID(ctx context.Context) string {
 	return fmt.Sprintf("%v", claims["https://client.devpie.io/claims/user_id"])
 }
 
+// RoleClient is the Auth0 role of client users (integrations/auth0/roles).
+const RoleClient = "client"
+
+// HasRole reports whether the user was assigned an Auth0 role
+func (a0 *Auth0) HasRole(ctx context.Context, role string) bool {
+	claims := ctx.Value("user").(*jwt.Token).Claims.(jwt.MapClaims)
+	list, _ := claims["https://client.devpie.io/claims/roles"].([]interface{})
+	for _, r := range list {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// UserNickname returns the nickname of the user, set by the set-claim--profile rule
+func (a0 *Auth0) UserNickname(ctx context.Context) string {
+	claims := ctx.Value("user").(*jwt.Token).Claims.(jwt.MapClaims)
+	nickname, _ := claims["https://client.devpie.io/claims/nickname"].(string)
+	return nickname
+}
+
 // GenerateToken generates a new management Token if one does not exist otherwise it returns an existing one.
 func (a0 *Auth0) GenerateToken() (Token, error) {
 	var t Token
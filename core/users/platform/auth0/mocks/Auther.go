@@ -138,6 +138,20 @@ func (_m *Auther) GenerateToken() (auth0.Token, error) {
 	return r0, r1
 }
 
+// HasRole provides a mock function with given fields: ctx, role
+func (_m *Auther) HasRole(ctx context.Context, role string) bool {
+	ret := _m.Called(ctx, role)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, role)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // IsExpired provides a mock function with given fields: token
 func (_m *Auther) IsExpired(token auth0.Token) bool {
 	ret := _m.Called(token)
@@ -277,3 +291,17 @@ func (_m *Auther) UserBySubject(ctx context.Context) string {
 
 	return r0
 }
+
+// UserNickname provides a mock function with given fields: ctx
+func (_m *Auther) UserNickname(ctx context.Context) string {
+	ret := _m.Called(ctx)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context) string); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
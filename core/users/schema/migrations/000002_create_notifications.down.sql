@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS notifications_user_id_read_idx;
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS notifications (
+notification_id VARCHAR(36) PRIMARY KEY,
+user_id VARCHAR(36) NOT NULL,
+actor_id VARCHAR(36),
+event_id VARCHAR(36),
+type VARCHAR(32) NOT NULL,
+message TEXT NOT NULL,
+resource_id VARCHAR(36),
+project_id VARCHAR(36),
+read BOOLEAN DEFAULT FALSE,
+updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+UNIQUE (user_id, event_id),
+FOREIGN KEY (user_id) REFERENCES users (user_id)
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_id_read_idx ON notifications (user_id, read);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS mentions_user_id_idx;
+DROP TABLE IF EXISTS mentions;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS mentions (
+mention_id VARCHAR(36) PRIMARY KEY,
+project_id VARCHAR(36) NOT NULL,
+task_id VARCHAR(36) NOT NULL,
+location VARCHAR(32) NOT NULL,
+handle VARCHAR(128) NOT NULL,
+user_id VARCHAR(36),
+actor_id VARCHAR(36),
+resolved BOOLEAN DEFAULT FALSE,
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+UNIQUE (task_id, location, handle),
+FOREIGN KEY (user_id) REFERENCES users (user_id)
+);
+
+CREATE INDEX IF NOT EXISTS mentions_user_id_idx ON mentions (user_id);
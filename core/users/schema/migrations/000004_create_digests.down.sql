@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS activities_created_at_idx;
+DROP TABLE IF EXISTS activities;
+DROP TABLE IF EXISTS digest_subscriptions;
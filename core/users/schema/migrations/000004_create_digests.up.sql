@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS digest_subscriptions (
+user_id VARCHAR(36) PRIMARY KEY,
+frequency VARCHAR(8) NOT NULL DEFAULT 'daily',
+time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+last_sent_at TIMESTAMP WITHOUT TIME ZONE,
+updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+FOREIGN KEY (user_id) REFERENCES users (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS activities (
+activity_id VARCHAR(36) PRIMARY KEY,
+event_id VARCHAR(36) NOT NULL,
+project_id VARCHAR(36) NOT NULL,
+task_id VARCHAR(36) NOT NULL,
+actor_id VARCHAR(36),
+type VARCHAR(32) NOT NULL,
+key VARCHAR(10),
+title VARCHAR(48),
+detail TEXT,
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc'),
+UNIQUE (event_id, type)
+);
+
+CREATE INDEX IF NOT EXISTS activities_created_at_idx ON activities (created_at);
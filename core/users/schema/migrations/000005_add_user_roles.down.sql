@@ -0,0 +1,3 @@
+UPDATE mentions SET location = substring(location FROM 8) WHERE location LIKE 'legacy:%';
+
+ALTER TABLE users DROP COLUMN IF EXISTS client;
@@ -0,0 +1,5 @@
+-- NULL until the user is seen with or without the client role
+ALTER TABLE users ADD COLUMN IF NOT EXISTS client BOOLEAN;
+
+-- mentions in comments were keyed by position, which shifts when a comment is deleted
+UPDATE mentions SET location = 'legacy:' || location WHERE location LIKE 'comment:%';
@@ -0,0 +1,3 @@
+-- activity recorded before tasks could be told apart is kept from clients
+ALTER TABLE activities ADD COLUMN IF NOT EXISTS internal BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE activities ALTER COLUMN internal SET DEFAULT FALSE;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS grants;
+ALTER TABLE users DROP COLUMN IF EXISTS nickname;
@@ -0,0 +1,13 @@
+-- mentions match nicknames as the projects service does, not first names
+ALTER TABLE users ADD COLUMN IF NOT EXISTS nickname VARCHAR(255);
+
+CREATE TABLE IF NOT EXISTS grants (
+grant_id VARCHAR(36) PRIMARY KEY,
+project_id VARCHAR(36) NOT NULL,
+user_id VARCHAR(36) NOT NULL,
+role ROLE NOT NULL,
+expiration TIMESTAMP WITHOUT TIME ZONE,
+created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc')
+);
+
+CREATE INDEX IF NOT EXISTS grants_project_id_idx ON grants (project_id);
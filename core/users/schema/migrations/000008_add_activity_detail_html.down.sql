@@ -0,0 +1 @@
+ALTER TABLE activities DROP COLUMN IF EXISTS detail_html;
@@ -0,0 +1,2 @@
+-- comments are rendered by the projects service, so digests show them as the web app does
+ALTER TABLE activities ADD COLUMN IF NOT EXISTS detail_html TEXT;
@@ -0,0 +1,12 @@
+function SetProfile(user, context, callback) {
+    const namespace = 'https://client-domain.com/claims/';
+
+    if(user.email) {
+        context.accessToken[namespace + 'email'] = user.email;
+    }
+    if(user.nickname) {
+        context.accessToken[namespace + 'nickname'] = user.nickname;
+    }
+
+    callback(null, user, context);
+}